@@ -1,17 +1,44 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 
 	"github.com/hrygo/dialecta/internal/cli"
 	"github.com/hrygo/dialecta/internal/config"
+	"github.com/hrygo/dialecta/internal/llm"
 )
 
 func main() {
 	// Parse command-line flags
 	opts := cli.ParseFlags()
 
+	// List local Ollama models and exit
+	if opts.ListModels {
+		ui := cli.DefaultUI()
+		models, err := llm.ListOllamaModels(context.Background(), "")
+		if err != nil {
+			ui.PrintError("获取 Ollama 模型列表失败: " + err.Error())
+			os.Exit(1)
+		}
+		ui.PrintOllamaModels(models)
+		return
+	}
+
+	// Prune the response cache and exit
+	if opts.PruneCache {
+		ui := cli.DefaultUI()
+		cache := opts.NewCache()
+		stats, err := cache.Prune(opts.CacheMaxAge, opts.CacheMaxSize*1024*1024)
+		if err != nil {
+			ui.PrintError("清理缓存失败: " + err.Error())
+			os.Exit(1)
+		}
+		ui.PrintCachePruned(cache.Dir(), stats)
+		return
+	}
+
 	// Show help if needed
 	if opts.NeedsHelp() {
 		flag.Usage()
@@ -20,6 +47,19 @@ func main() {
 
 	// Load configuration and apply options
 	cfg := config.New()
+	if err := opts.CheckFlags(); err != nil {
+		exitConfigError(err)
+	}
+	personas, err := opts.LoadPanel()
+	if err != nil {
+		exitConfigError(err)
+	}
+	cfg.Personas = personas
+	judges, err := opts.JudgePanel()
+	if err != nil {
+		exitConfigError(err)
+	}
+	cfg.Judges = judges
 	opts.ApplyToConfig(cfg)
 
 	// In interactive mode, let user select model combination
@@ -40,9 +80,16 @@ func main() {
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
-		ui := cli.DefaultUI()
-		ui.PrintError("配置错误: " + err.Error())
-		os.Exit(1)
+		exitConfigError(err)
+	}
+
+	toolbox, err := opts.NewToolbox()
+	if err != nil {
+		exitConfigError(err)
+	}
+	aggregation, err := opts.Aggregation()
+	if err != nil {
+		exitConfigError(err)
 	}
 
 	// Read material
@@ -60,9 +107,20 @@ func main() {
 
 	// Run the debate
 	runner := cli.NewRunner(cfg, opts.Stream)
+	runner.SetShowReasoning(opts.ShowReasoning)
+	runner.SetStructured(opts.Structured)
+	runner.SetRounds(opts.Rounds)
+	runner.SetAggregation(aggregation)
+	runner.SetTools(toolbox)
 	if err := runner.Run(ctx, material); err != nil {
 		ui := cli.DefaultUI()
 		ui.PrintError(err.Error())
 		os.Exit(1)
 	}
 }
+
+// exitConfigError reports an invalid configuration and exits
+func exitConfigError(err error) {
+	cli.DefaultUI().PrintError("配置错误: " + err.Error())
+	os.Exit(1)
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/testutil"
+)
+
+// TestRunner_Run_Cassette replays the debate cassettes recorded for the
+// executor tests through the CLI runner
+func TestRunner_Run_Cassette(t *testing.T) {
+	if testutil.Recording() {
+		t.Skip("the cassettes are recorded by the executor tests")
+	}
+	for _, provider := range testutil.CassetteProviders {
+		t.Run(string(provider), func(t *testing.T) {
+			cassette := testutil.OpenCassette(t, provider)
+			cfg := testutil.CassetteConfig(provider, cassette)
+			t.Chdir(t.TempDir()) // reports/ is written to the working directory
+
+			var out bytes.Buffer
+			runner := NewRunnerWithOptions(cfg, true, NewUI(&out, &out), NewInputReader(strings.NewReader(""), &out))
+			if err := runner.Run(context.Background(), testutil.CassetteMaterial); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			for _, want := range []string{string(provider), "Token Usage", "$"} {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q", want)
+				}
+			}
+			if n := cassette.Unused(); n != 0 {
+				t.Errorf("%d recorded interactions were not replayed", n)
+			}
+		})
+	}
+}
@@ -1,40 +1,121 @@
 package cli
 
 import (
+	"cmp"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/hrygo/dialecta/internal/config"
+	"github.com/hrygo/dialecta/internal/debate"
 	"github.com/hrygo/dialecta/internal/llm"
 )
 
 // Options holds the parsed command-line options
 type Options struct {
-	ProProvider   string
-	ProModel      string
-	ConProvider   string
-	ConModel      string
-	JudgeProvider string
-	JudgeModel    string
-	Stream        bool
-	Interactive   bool
-	Source        string // file path, "-" for stdin, or empty for no source
+	ProProvider    string
+	ProModel       string
+	ProBaseURL     string
+	ConProvider    string
+	ConModel       string
+	ConBaseURL     string
+	JudgeProvider  string
+	JudgeModel     string
+	JudgeBaseURL   string
+	ProExec        string // exec provider plugin command line
+	ConExec        string
+	JudgeExec      string
+	ProFallback    string // comma-separated provider[:model] fallback chain
+	ConFallback    string
+	JudgeFallback  string
+	Stream         bool
+	Interactive    bool
+	ShowReasoning  bool   // print reasoning-model thinking, dimmed
+	Structured     bool   // roles answer in schema-validated JSON
+	Tools          string // comma-separated built-in tools the debaters may call
+	Continuations  int    // continuation requests per role when an answer hits max tokens
+	Rounds         int    // debate rounds: opening statements, then rebuttals
+	Panel          string // panel file of personas debating instead of Pro and Con
+	Judges         string // comma-separated provider[:model] judge panel
+	ChiefJudge     bool   // the adjudicator reconciles the judge panel's verdicts
+	JudgeAggregate string // how the judge panel's scores are combined: mean, median or majority
+	ListModels     bool   // list locally installed Ollama models and exit
+	Source         string // file path, "-" for stdin, or empty for no source
+
+	ConnectTimeout time.Duration // per-role connect timeout; 0 keeps the role default
+	Timeout        time.Duration // per-request total timeout; 0 keeps the role default
+	IdleTimeout    time.Duration // max silence between stream chunks; 0 keeps the role default
+
+	ProDeployment   string // Azure OpenAI deployment; empty uses the model
+	ConDeployment   string
+	JudgeDeployment string
+	AzureAPIVersion string // Azure OpenAI api-version for every role
+
+	Cache        bool          // serve repeated requests from the response cache
+	NoCache      bool          // overrides Cache
+	CacheDir     string        // empty uses llm.DefaultCacheDir()
+	PruneCache   bool          // prune the response cache and exit
+	CacheMaxAge  time.Duration // prune entries unused for longer than this
+	CacheMaxSize int64         // prune down to this many MB
+
+	RateLimit string // comma-separated provider:rpm=N:tpm=N:inflight=N limits
+
+	Profile          string // API key set; empty uses DIALECTA_PROFILE
+	CredentialsFile  string // empty uses DIALECTA_CREDENTIALS_FILE or llm.DefaultCredentialsFile()
+	CredentialHelper string // helper command line; empty uses DIALECTA_CREDENTIAL_HELPER
 }
 
 // ParseFlags parses command-line flags and returns Options
 func ParseFlags() *Options {
 	opts := &Options{}
 
-	flag.StringVar(&opts.ProProvider, "pro-provider", "deepseek", "Provider for affirmative (deepseek, gemini, dashscope)")
+	flag.StringVar(&opts.ProProvider, "pro-provider", "deepseek", "Provider for affirmative ("+llm.ProviderNames()+")")
 	flag.StringVar(&opts.ProModel, "pro-model", "", "Model for affirmative")
-	flag.StringVar(&opts.ConProvider, "con-provider", "dashscope", "Provider for negative (deepseek, gemini, dashscope)")
+	flag.StringVar(&opts.ProBaseURL, "pro-base-url", "", "OpenAI-compatible base URL or Azure endpoint for affirmative")
+	flag.StringVar(&opts.ConProvider, "con-provider", "dashscope", "Provider for negative ("+llm.ProviderNames()+")")
 	flag.StringVar(&opts.ConModel, "con-model", "", "Model for negative")
-	flag.StringVar(&opts.JudgeProvider, "judge-provider", "gemini", "Provider for adjudicator (deepseek, gemini, dashscope)")
+	flag.StringVar(&opts.ConBaseURL, "con-base-url", "", "OpenAI-compatible base URL or Azure endpoint for negative")
+	flag.StringVar(&opts.JudgeProvider, "judge-provider", "gemini", "Provider for adjudicator ("+llm.ProviderNames()+")")
 	flag.StringVar(&opts.JudgeModel, "judge-model", "", "Model for adjudicator")
+	flag.StringVar(&opts.JudgeBaseURL, "judge-base-url", "", "OpenAI-compatible base URL or Azure endpoint for adjudicator")
+	flag.StringVar(&opts.ProExec, "pro-exec", "", "Plugin command for an exec affirmative, e.g. \"./my-model --fast\"")
+	flag.StringVar(&opts.ConExec, "con-exec", "", "Plugin command for an exec negative")
+	flag.StringVar(&opts.JudgeExec, "judge-exec", "", "Plugin command for an exec adjudicator")
+	flag.StringVar(&opts.ProDeployment, "pro-deployment", "", "Azure OpenAI deployment for affirmative (default: the model name)")
+	flag.StringVar(&opts.ConDeployment, "con-deployment", "", "Azure OpenAI deployment for negative")
+	flag.StringVar(&opts.JudgeDeployment, "judge-deployment", "", "Azure OpenAI deployment for adjudicator")
+	flag.StringVar(&opts.AzureAPIVersion, "azure-api-version", "", "Azure OpenAI api-version for every role (default: AZURE_OPENAI_API_VERSION or "+llm.DefaultAzureAPIVersion+")")
+	flag.StringVar(&opts.ProFallback, "pro-fallback", "", "Fallback chain for affirmative, e.g. gemini,openai:gpt-4o-mini")
+	flag.StringVar(&opts.ConFallback, "con-fallback", "", "Fallback chain for negative")
+	flag.StringVar(&opts.JudgeFallback, "judge-fallback", "", "Fallback chain for adjudicator, e.g. deepseek")
 	flag.BoolVar(&opts.Stream, "stream", true, "Enable streaming output")
 	flag.BoolVar(&opts.Interactive, "interactive", false, "Interactive mode - enter material via stdin")
 	flag.BoolVar(&opts.Interactive, "i", false, "Interactive mode (shorthand)")
+	flag.BoolVar(&opts.ShowReasoning, "show-reasoning", false, "Show reasoning from thinking models (dimmed)")
+	flag.BoolVar(&opts.Structured, "structured", false, "Ask every role for schema-validated JSON instead of Markdown")
+	flag.StringVar(&opts.Tools, "tools", "", "Tools Pro and Con may call, comma-separated (calculator)")
+	flag.IntVar(&opts.Rounds, "rounds", 1, "Debate rounds: after the opening statements Pro and Con rebut each other N-1 times")
+	flag.StringVar(&opts.Panel, "panel", "", "JSON file of personas (CFO, Security, ...) that debate instead of Pro and Con")
+	flag.StringVar(&opts.Judges, "judges", "", "Judge panel ruling concurrently instead of the adjudicator, e.g. deepseek,anthropic,gemini")
+	flag.BoolVar(&opts.ChiefJudge, "chief-judge", false, "Let the adjudicator reconcile the judge panel's verdicts")
+	flag.StringVar(&opts.JudgeAggregate, "judge-aggregate", "mean", "Combine the judge panel's scores by mean, median or majority (the mean of the judges in the majority decision); decisions go by majority")
+	flag.IntVar(&opts.Continuations, "continuations", 0, "Ask each role up to N times to continue an answer cut off at max tokens")
+	flag.DurationVar(&opts.ConnectTimeout, "connect-timeout", 0, "Connect timeout for every role (default 10s)")
+	flag.DurationVar(&opts.Timeout, "timeout", 0, "Total time allowed per request, for every role (default 10m)")
+	flag.DurationVar(&opts.IdleTimeout, "idle-timeout", 0, "Fail a stream that sends nothing for this long, for every role (default 2m)")
+	flag.BoolVar(&opts.Cache, "cache", false, "Reuse cached responses for identical requests")
+	flag.BoolVar(&opts.NoCache, "no-cache", false, "Disable the response cache (overrides --cache)")
+	flag.StringVar(&opts.CacheDir, "cache-dir", "", "Response cache directory (default: user cache dir)")
+	flag.BoolVar(&opts.PruneCache, "prune-cache", false, "Prune the response cache using --cache-max-age/--cache-max-size and exit")
+	flag.DurationVar(&opts.CacheMaxAge, "cache-max-age", 30*24*time.Hour, "Prune cache entries unused for longer than this")
+	flag.Int64Var(&opts.CacheMaxSize, "cache-max-size", 200, "Prune the cache down to this size in MB")
+	flag.StringVar(&opts.RateLimit, "rate-limit", "", "Per-provider limits shared by all roles, e.g. dashscope:rpm=60:tpm=100000:inflight=2")
+	flag.StringVar(&opts.Profile, "profile", "", "API key set from the credentials file or helper; skips the environment variables (default: environment, then \"default\")")
+	flag.StringVar(&opts.CredentialsFile, "credentials-file", "", "API keys file, one [profile] per key set (default: user config dir)")
+	flag.StringVar(&opts.CredentialHelper, "credential-helper", "", "Command printing API keys, e.g. \"pass-keys --vault ai\"")
+	flag.BoolVar(&opts.ListModels, "list-ollama-models", false, "List locally installed Ollama models and exit")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, `
@@ -48,15 +129,23 @@ func ParseFlags() *Options {
   dialecta --interactive / -i     %s▸ Interactive input mode%s
 
 %s%sAI PROVIDERS%s
-  %s◈ deepseek%s   DeepSeek API       %s→ DEEPSEEK_API_KEY%s
-  %s◈ gemini%s     Google Gemini      %s→ GEMINI_API_KEY / GOOGLE_API_KEY%s
-  %s◈ dashscope%s  Alibaba Qwen       %s→ DASHSCOPE_API_KEY%s
-
+%s
 %s%sEXAMPLES%s
   %s$%s dialecta proposal.md
   %s$%s cat plan.txt | dialecta -
   %s$%s echo "我们应该启动AI创业项目" | dialecta -
   %s$%s dialecta --judge-provider deepseek --judge-model deepseek-chat doc.md
+  %s$%s dialecta --pro-provider openai --pro-base-url http://localhost:8000/v1 doc.md
+  %s$%s dialecta --pro-provider ollama --con-provider ollama --judge-provider ollama doc.md
+  %s$%s dialecta --pro-provider mock --con-provider mock --judge-provider mock doc.md
+  %s$%s dialecta --pro-provider exec --pro-exec "./in-house-model --json" doc.md
+  %s$%s dialecta --judge-provider azure --judge-base-url https://my-res.openai.azure.com --judge-deployment gpt4o-prod doc.md
+  %s$%s dialecta --rounds 3 doc.md          %s# opening statements + 2 rebuttal rounds%s
+  %s$%s dialecta --panel panel.json doc.md  %s# CFO, Security, ... instead of Pro and Con%s
+  %s$%s dialecta --judges deepseek,anthropic,gemini --chief-judge doc.md
+  %s$%s dialecta --judge-fallback deepseek,anthropic doc.md
+  %s$%s dialecta --cache doc.md             %s# re-runs reuse debater responses%s
+  %s$%s dialecta --rate-limit dashscope:rpm=60:inflight=1 doc.md
 
 %s%sOPTIONS%s
 `, ColorBrightCyan, ColorBold, ColorReset,
@@ -67,14 +156,23 @@ func ParseFlags() *Options {
 			ColorDim, ColorReset,
 			ColorDim, ColorReset,
 			ColorBrightWhite, ColorBold, ColorReset,
-			ColorBrightGreen, ColorReset, ColorDim, ColorReset,
-			ColorBrightMagenta, ColorReset, ColorDim, ColorReset,
-			ColorBrightYellow, ColorReset, ColorDim, ColorReset,
+			providersHelp(),
 			ColorBrightWhite, ColorBold, ColorReset,
 			ColorBrightCyan, ColorReset,
 			ColorBrightCyan, ColorReset,
 			ColorBrightCyan, ColorReset,
 			ColorBrightCyan, ColorReset,
+			ColorBrightCyan, ColorReset,
+			ColorBrightCyan, ColorReset,
+			ColorBrightCyan, ColorReset,
+			ColorBrightCyan, ColorReset,
+			ColorBrightCyan, ColorReset,
+			ColorBrightCyan, ColorReset, ColorDim, ColorReset,
+			ColorBrightCyan, ColorReset, ColorDim, ColorReset,
+			ColorBrightCyan, ColorReset,
+			ColorBrightCyan, ColorReset,
+			ColorBrightCyan, ColorReset, ColorDim, ColorReset,
+			ColorBrightCyan, ColorReset,
 			ColorBrightWhite, ColorBold, ColorReset)
 		flag.PrintDefaults()
 		fmt.Fprintln(os.Stderr)
@@ -90,6 +188,20 @@ func ParseFlags() *Options {
 	return opts
 }
 
+// providerColors tint the provider names in the help text, in turn
+var providerColors = []string{ColorBrightGreen, ColorBrightMagenta, ColorBrightYellow, ColorBrightBlue, ColorBrightRed, ColorBrightWhite}
+
+// providersHelp lists the registered providers and their environment
+func providersHelp() string {
+	var b strings.Builder
+	for i, spec := range llm.Providers() {
+		fmt.Fprintf(&b, "  %s◈ %-10s%s %-18s %s→ %s%s\n",
+			providerColors[i%len(providerColors)], spec.Name, ColorReset,
+			spec.Description, ColorDim, spec.EnvSummary(), ColorReset)
+	}
+	return b.String()
+}
+
 // ApplyToConfig applies the options to a config
 // Temperature and MaxTokens are role-specific and remain unchanged when switching providers
 func (opts *Options) ApplyToConfig(cfg *config.Config) {
@@ -105,6 +217,18 @@ func (opts *Options) ApplyToConfig(cfg *config.Config) {
 	if opts.ProModel != "" {
 		cfg.ProRole.Model = opts.ProModel
 	}
+	if opts.ProBaseURL != "" {
+		cfg.ProRole.BaseURL = opts.ProBaseURL
+	}
+	if command, err := llm.SplitCommand(opts.ProExec); err == nil && len(command) > 0 {
+		cfg.ProRole.Command = command
+	}
+	if opts.ProDeployment != "" {
+		cfg.ProRole.Deployment = opts.ProDeployment
+	}
+	if fallbacks, err := config.ParseFallbacks(opts.ProFallback); err == nil && len(fallbacks) > 0 {
+		cfg.ProRole.Fallbacks = fallbacks
+	}
 
 	// Con role - keep role-specific Temperature and MaxTokens
 	if p, err := llm.ParseProvider(opts.ConProvider); err == nil {
@@ -118,6 +242,18 @@ func (opts *Options) ApplyToConfig(cfg *config.Config) {
 	if opts.ConModel != "" {
 		cfg.ConRole.Model = opts.ConModel
 	}
+	if opts.ConBaseURL != "" {
+		cfg.ConRole.BaseURL = opts.ConBaseURL
+	}
+	if command, err := llm.SplitCommand(opts.ConExec); err == nil && len(command) > 0 {
+		cfg.ConRole.Command = command
+	}
+	if opts.ConDeployment != "" {
+		cfg.ConRole.Deployment = opts.ConDeployment
+	}
+	if fallbacks, err := config.ParseFallbacks(opts.ConFallback); err == nil && len(fallbacks) > 0 {
+		cfg.ConRole.Fallbacks = fallbacks
+	}
 
 	// Judge role - keep role-specific Temperature and MaxTokens
 	if p, err := llm.ParseProvider(opts.JudgeProvider); err == nil {
@@ -131,6 +267,144 @@ func (opts *Options) ApplyToConfig(cfg *config.Config) {
 	if opts.JudgeModel != "" {
 		cfg.JudgeRole.Model = opts.JudgeModel
 	}
+	if opts.JudgeBaseURL != "" {
+		cfg.JudgeRole.BaseURL = opts.JudgeBaseURL
+	}
+	if command, err := llm.SplitCommand(opts.JudgeExec); err == nil && len(command) > 0 {
+		cfg.JudgeRole.Command = command
+	}
+	if opts.JudgeDeployment != "" {
+		cfg.JudgeRole.Deployment = opts.JudgeDeployment
+	}
+	if fallbacks, err := config.ParseFallbacks(opts.JudgeFallback); err == nil && len(fallbacks) > 0 {
+		cfg.JudgeRole.Fallbacks = fallbacks
+	}
+
+	cfg.ChiefJudge = opts.ChiefJudge
+
+	if opts.Continuations > 0 {
+		for _, role := range cfg.Roles() {
+			role.MaxContinuations = opts.Continuations
+		}
+	}
+
+	for _, role := range cfg.Roles() {
+		if opts.ConnectTimeout > 0 {
+			role.Timeouts.Connect = opts.ConnectTimeout
+		}
+		if opts.Timeout > 0 {
+			role.Timeouts.Total = opts.Timeout
+		}
+		if opts.IdleTimeout > 0 {
+			role.Timeouts.Idle = opts.IdleTimeout
+		}
+		if opts.AzureAPIVersion != "" {
+			role.APIVersion = opts.AzureAPIVersion
+		}
+	}
+
+	if opts.CacheEnabled() {
+		cfg.SetCache(opts.NewCache())
+	}
+
+	if limits, err := config.ParseRateLimits(opts.RateLimit); err == nil {
+		for provider, limit := range limits {
+			cfg.SetRateLimit(provider, limit)
+		}
+	}
+
+	cfg.SetCredentials(opts.NewCredentialSources())
+}
+
+// CheckFlags reports the first flag value that ApplyToConfig cannot parse;
+// ApplyToConfig itself skips such values, so call it first
+func (opts *Options) CheckFlags() error {
+	for _, f := range []struct{ flag, value string }{
+		{"pro-fallback", opts.ProFallback},
+		{"con-fallback", opts.ConFallback},
+		{"judge-fallback", opts.JudgeFallback},
+	} {
+		if _, err := config.ParseFallbacks(f.value); err != nil {
+			return fmt.Errorf("--%s: %w", f.flag, err)
+		}
+	}
+	for _, f := range []struct{ flag, value string }{
+		{"pro-exec", opts.ProExec},
+		{"con-exec", opts.ConExec},
+		{"judge-exec", opts.JudgeExec},
+	} {
+		if _, err := llm.SplitCommand(f.value); err != nil {
+			return fmt.Errorf("--%s: %w", f.flag, err)
+		}
+	}
+	if _, err := config.ParseRateLimits(opts.RateLimit); err != nil {
+		return fmt.Errorf("--rate-limit: %w", err)
+	}
+	return nil
+}
+
+// LoadPanel returns the personas of the --panel file, or nil when there is
+// none. Load them into config.Config.Personas before ApplyToConfig, so that
+// timeouts, the cache and the other per-role options reach them too.
+func (opts *Options) LoadPanel() ([]config.Persona, error) {
+	if opts.Panel == "" {
+		return nil, nil
+	}
+	return config.LoadPersonas(opts.Panel)
+}
+
+// JudgePanel returns the judges of --judges, or nil when there are none.
+// Like the personas, they belong in config.Config.Judges before
+// ApplyToConfig.
+func (opts *Options) JudgePanel() ([]config.RoleConfig, error) {
+	return config.ParseJudges(opts.Judges)
+}
+
+// Aggregation returns how the judge panel's scores are combined
+func (opts *Options) Aggregation() (debate.Aggregation, error) {
+	return debate.ParseAggregation(opts.JudgeAggregate)
+}
+
+// NewToolbox returns the built-in tools selected by --tools, or nil when
+// none are
+func (opts *Options) NewToolbox() (*debate.Toolbox, error) {
+	if strings.TrimSpace(opts.Tools) == "" {
+		return nil, nil
+	}
+	return debate.BuiltinToolbox(strings.Split(opts.Tools, ","))
+}
+
+// CacheEnabled reports whether the response cache should be used
+func (opts *Options) CacheEnabled() bool {
+	return opts.Cache && !opts.NoCache
+}
+
+// NewCache returns the response cache selected by --cache-dir
+func (opts *Options) NewCache() *llm.Cache {
+	dir := opts.CacheDir
+	if dir == "" {
+		dir = llm.DefaultCacheDir()
+	}
+	return llm.NewCache(dir)
+}
+
+// NewCredentialSources returns the API key sources selected by --profile,
+// --credentials-file and --credential-helper or their environment
+// variables, or nil when only the provider environment variables are used.
+// The default credentials file is read when it exists.
+func (opts *Options) NewCredentialSources() *llm.CredentialSources {
+	file := cmp.Or(opts.CredentialsFile, os.Getenv(llm.CredentialsFileEnv))
+	if file == "" {
+		if _, err := os.Stat(llm.DefaultCredentialsFile()); err == nil {
+			file = llm.DefaultCredentialsFile()
+		}
+	}
+	helper := strings.Fields(cmp.Or(opts.CredentialHelper, os.Getenv(llm.CredentialHelperEnv)))
+	profile := cmp.Or(opts.Profile, os.Getenv(llm.ProfileEnv))
+	if file == "" && len(helper) == 0 && profile == "" {
+		return nil
+	}
+	return llm.NewCredentialSources(file, helper, profile)
 }
 
 // NeedsHelp returns true if help should be shown (no source and not interactive)
@@ -1,9 +1,15 @@
 package cli
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hrygo/dialecta/internal/config"
+	"github.com/hrygo/dialecta/internal/debate"
 	"github.com/hrygo/dialecta/internal/llm"
 )
 
@@ -174,3 +180,345 @@ func TestOptions_ApplyToConfig_InvalidProvider(t *testing.T) {
 		t.Errorf("Invalid provider should not change JudgeRole.Provider")
 	}
 }
+
+func TestOptions_ApplyToConfig_BaseURL(t *testing.T) {
+	opts := &Options{
+		ProProvider:   "openai",
+		ProBaseURL:    "http://localhost:8000/v1",
+		ConProvider:   "dashscope",
+		JudgeProvider: "gemini",
+	}
+
+	cfg := config.New()
+	opts.ApplyToConfig(cfg)
+
+	if cfg.ProRole.Provider != llm.ProviderOpenAI {
+		t.Errorf("ProRole.Provider = %v, want %v", cfg.ProRole.Provider, llm.ProviderOpenAI)
+	}
+	if cfg.ProRole.Model != config.GetDefaultModel(llm.ProviderOpenAI) {
+		t.Errorf("ProRole.Model = %v, want provider default", cfg.ProRole.Model)
+	}
+	if cfg.ProRole.BaseURL != "http://localhost:8000/v1" {
+		t.Errorf("ProRole.BaseURL = %v, want %v", cfg.ProRole.BaseURL, "http://localhost:8000/v1")
+	}
+	if cfg.ConRole.BaseURL != "" {
+		t.Errorf("ConRole.BaseURL = %v, want empty", cfg.ConRole.BaseURL)
+	}
+}
+
+func TestOptions_ApplyToConfig_Exec(t *testing.T) {
+	opts := &Options{
+		ProProvider:   "exec",
+		ProExec:       "./in-house-model --json  --quiet",
+		ConProvider:   "dashscope",
+		JudgeProvider: "gemini",
+	}
+
+	cfg := config.New()
+	opts.ApplyToConfig(cfg)
+
+	if cfg.ProRole.Provider != llm.ProviderExec || cfg.ProRole.Model != "default" {
+		t.Errorf("ProRole = %v/%v, want exec with its default model", cfg.ProRole.Provider, cfg.ProRole.Model)
+	}
+	if want := []string{"./in-house-model", "--json", "--quiet"}; !reflect.DeepEqual(cfg.ProRole.Command, want) {
+		t.Errorf("ProRole.Command = %q, want %q", cfg.ProRole.Command, want)
+	}
+	if cfg.ConRole.Command != nil {
+		t.Errorf("ConRole.Command = %q, want none", cfg.ConRole.Command)
+	}
+}
+
+func TestOptions_ApplyToConfig_Azure(t *testing.T) {
+	opts := &Options{
+		ProProvider:     "deepseek",
+		ConProvider:     "dashscope",
+		JudgeProvider:   "azure",
+		JudgeBaseURL:    "https://my-res.openai.azure.com",
+		JudgeDeployment: "gpt4o-prod",
+		JudgeFallback:   "azure:gpt-4o-mini",
+		AzureAPIVersion: "2025-01-01-preview",
+	}
+
+	cfg := config.New()
+	opts.ApplyToConfig(cfg)
+
+	llmCfg := cfg.JudgeRole.ToLLMConfig()
+	if llmCfg.Provider != llm.ProviderAzure || llmCfg.Model != "gpt-4o" || llmCfg.Deployment != "gpt4o-prod" || llmCfg.BaseURL != "https://my-res.openai.azure.com" {
+		t.Errorf("JudgeRole = %+v, want the Azure deployment", llmCfg)
+	}
+	if chain := cfg.JudgeRole.Chain(); chain[1].APIVersion != "2025-01-01-preview" || chain[1].Deployment != "" {
+		t.Errorf("fallback = %+v, want the api-version but not the primary deployment", chain[1])
+	}
+	if cfg.ProRole.Deployment != "" {
+		t.Errorf("ProRole.Deployment = %q, want none", cfg.ProRole.Deployment)
+	}
+}
+
+func TestOptions_ApplyToConfig_Fallback(t *testing.T) {
+	opts := &Options{
+		ProProvider:   "deepseek",
+		ConProvider:   "dashscope",
+		JudgeProvider: "gemini",
+		JudgeFallback: "deepseek,anthropic:claude-opus-4-1",
+		ConFallback:   "not-a-provider",
+	}
+
+	cfg := config.New()
+	opts.ApplyToConfig(cfg)
+
+	want := []config.Fallback{
+		{Provider: llm.ProviderDeepSeek, Model: "deepseek-chat"},
+		{Provider: llm.ProviderAnthropic, Model: "claude-opus-4-1"},
+	}
+	if len(cfg.JudgeRole.Fallbacks) != len(want) {
+		t.Fatalf("JudgeRole.Fallbacks = %v, want %v", cfg.JudgeRole.Fallbacks, want)
+	}
+	for i := range want {
+		if cfg.JudgeRole.Fallbacks[i] != want[i] {
+			t.Errorf("JudgeRole.Fallbacks[%d] = %v, want %v", i, cfg.JudgeRole.Fallbacks[i], want[i])
+		}
+	}
+	if len(cfg.ProRole.Fallbacks) != 0 || len(cfg.ConRole.Fallbacks) != 0 {
+		t.Errorf("Pro/Con fallbacks should be empty, got %v / %v", cfg.ProRole.Fallbacks, cfg.ConRole.Fallbacks)
+	}
+}
+
+func TestOptions_ApplyToConfig_Continuations(t *testing.T) {
+	cfg := config.New()
+	(&Options{ProProvider: "deepseek", ConProvider: "dashscope", JudgeProvider: "gemini"}).ApplyToConfig(cfg)
+	if cfg.ProRole.MaxContinuations != 0 || cfg.JudgeRole.MaxContinuations != 0 {
+		t.Errorf("MaxContinuations = %d/%d, want 0 by default", cfg.ProRole.MaxContinuations, cfg.JudgeRole.MaxContinuations)
+	}
+
+	opts := &Options{ProProvider: "deepseek", ConProvider: "dashscope", JudgeProvider: "gemini", Continuations: 2}
+	opts.ApplyToConfig(cfg)
+	for name, role := range map[string]config.RoleConfig{"pro": cfg.ProRole, "con": cfg.ConRole, "judge": cfg.JudgeRole} {
+		if role.MaxContinuations != 2 {
+			t.Errorf("%s MaxContinuations = %d, want 2", name, role.MaxContinuations)
+		}
+	}
+}
+
+func TestOptions_ApplyToConfig_Timeouts(t *testing.T) {
+	cfg := config.New()
+	(&Options{ProProvider: "deepseek", ConProvider: "dashscope", JudgeProvider: "gemini"}).ApplyToConfig(cfg)
+	if cfg.JudgeRole.Timeouts != llm.DefaultTimeouts {
+		t.Errorf("Timeouts = %+v, want the defaults", cfg.JudgeRole.Timeouts)
+	}
+
+	opts := &Options{ProProvider: "deepseek", ConProvider: "dashscope", JudgeProvider: "gemini", IdleTimeout: 30 * time.Second, Timeout: time.Minute}
+	opts.ApplyToConfig(cfg)
+	want := llm.Timeouts{Connect: llm.DefaultTimeouts.Connect, Total: time.Minute, Idle: 30 * time.Second}
+	for name, role := range map[string]config.RoleConfig{"pro": cfg.ProRole, "con": cfg.ConRole, "judge": cfg.JudgeRole} {
+		if role.Timeouts != want {
+			t.Errorf("%s Timeouts = %+v, want %+v", name, role.Timeouts, want)
+		}
+	}
+}
+
+func TestOptions_ApplyToConfig_Cache(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name      string
+		cache     bool
+		noCache   bool
+		wantCache bool
+	}{
+		{"default off", false, false, false},
+		{"--cache", true, false, true},
+		{"--no-cache wins", true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{
+				ProProvider:   "deepseek",
+				ConProvider:   "dashscope",
+				JudgeProvider: "gemini",
+				Cache:         tt.cache,
+				NoCache:       tt.noCache,
+				CacheDir:      dir,
+			}
+			cfg := config.New()
+			opts.ApplyToConfig(cfg)
+
+			for _, role := range []config.RoleConfig{cfg.ProRole, cfg.ConRole, cfg.JudgeRole} {
+				if (role.Cache != nil) != tt.wantCache {
+					t.Errorf("%s: Cache = %v, want enabled %v", role.Provider, role.Cache, tt.wantCache)
+				}
+				if tt.wantCache && role.Cache.Dir() != dir {
+					t.Errorf("%s: Cache.Dir() = %q, want %q", role.Provider, role.Cache.Dir(), dir)
+				}
+			}
+		})
+	}
+}
+
+func TestOptions_ApplyToConfig_RateLimit(t *testing.T) {
+	opts := &Options{
+		ProProvider:   "dashscope",
+		ConProvider:   "dashscope",
+		JudgeProvider: "gemini",
+		RateLimit:     "dashscope:rpm=60:inflight=1",
+	}
+	cfg := config.New()
+	opts.ApplyToConfig(cfg)
+
+	want := llm.RateLimit{RequestsPerMinute: 60, MaxInFlight: 1}
+	for _, role := range []config.RoleConfig{cfg.ProRole, cfg.ConRole} {
+		if got := role.ToLLMConfig().RateLimit; got != want {
+			t.Errorf("%s RateLimit = %+v, want %+v", role.Provider, got, want)
+		}
+	}
+	if got := cfg.JudgeRole.ToLLMConfig().RateLimit; !got.IsZero() {
+		t.Errorf("judge RateLimit = %+v, want none", got)
+	}
+}
+
+func TestOptions_NewToolbox(t *testing.T) {
+	if toolbox, err := (&Options{}).NewToolbox(); toolbox != nil || err != nil {
+		t.Errorf("NewToolbox() = %v, %v, want no toolbox without --tools", toolbox, err)
+	}
+	toolbox, err := (&Options{Tools: "calculator"}).NewToolbox()
+	if err != nil || toolbox.Len() != 1 {
+		t.Errorf("NewToolbox(calculator) = %v, %v", toolbox, err)
+	}
+	if _, err := (&Options{Tools: "calculator,weather"}).NewToolbox(); err == nil {
+		t.Error("NewToolbox() should reject unknown tools")
+	}
+}
+
+func TestOptions_CheckFlags(t *testing.T) {
+	opts := &Options{ProFallback: "gemini,openai:gpt-4o-mini"}
+	if err := opts.CheckFlags(); err != nil {
+		t.Errorf("CheckFlags() error = %v", err)
+	}
+	opts.JudgeFallback = "nope"
+	if err := opts.CheckFlags(); err == nil || !strings.Contains(err.Error(), "--judge-fallback") {
+		t.Errorf("CheckFlags() error = %v, want one naming --judge-fallback", err)
+	}
+
+	opts = &Options{RateLimit: "dashscope:rpm=60:tpm=100000:inflight=2"}
+	if err := opts.CheckFlags(); err != nil {
+		t.Errorf("CheckFlags() error = %v", err)
+	}
+	opts.RateLimit = "dashscope:rpm=lots"
+	if err := opts.CheckFlags(); err == nil || !strings.Contains(err.Error(), "--rate-limit") {
+		t.Errorf("CheckFlags() error = %v, want one naming --rate-limit", err)
+	}
+	opts = &Options{ConExec: `./model --prompt "it's fine"`}
+	if err := opts.CheckFlags(); err != nil {
+		t.Errorf("CheckFlags() error = %v", err)
+	}
+	opts.ConExec = `./model --prompt "unterminated`
+	if err := opts.CheckFlags(); err == nil || !strings.Contains(err.Error(), "--con-exec") {
+		t.Errorf("CheckFlags() error = %v, want one naming --con-exec", err)
+	}
+}
+
+func TestOptions_LoadPanel(t *testing.T) {
+	if personas, err := (&Options{}).LoadPanel(); personas != nil || err != nil {
+		t.Errorf("LoadPanel() = %v, %v, want no personas without --panel", personas, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "panel.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "CFO"}, {"name": "Ops", "provider": "dashscope"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	opts := &Options{ProProvider: "deepseek", ConProvider: "dashscope", JudgeProvider: "gemini", Panel: path, Continuations: 2, Timeout: time.Minute}
+	personas, err := opts.LoadPanel()
+	if err != nil || len(personas) != 2 {
+		t.Fatalf("LoadPanel() = %v, %v", personas, err)
+	}
+
+	// Per-role options reach the personas
+	cfg := config.New()
+	cfg.Personas = personas
+	opts.ApplyToConfig(cfg)
+	for _, p := range cfg.Personas {
+		if p.Role.MaxContinuations != 2 || p.Role.Timeouts.Total != time.Minute {
+			t.Errorf("%s MaxContinuations = %d, Timeouts = %+v; want the options applied", p.Name, p.Role.MaxContinuations, p.Role.Timeouts)
+		}
+	}
+
+	if _, err := (&Options{Panel: filepath.Join(t.TempDir(), "missing.json")}).LoadPanel(); err == nil {
+		t.Error("LoadPanel() should fail for a missing panel file")
+	}
+}
+
+func TestOptions_JudgePanel(t *testing.T) {
+	if judges, err := (&Options{}).JudgePanel(); judges != nil || err != nil {
+		t.Errorf("JudgePanel() = %v, %v, want no judges without --judges", judges, err)
+	}
+
+	opts := &Options{ProProvider: "deepseek", ConProvider: "dashscope", JudgeProvider: "gemini", Judges: "deepseek,anthropic:claude-sonnet-4-5", ChiefJudge: true, Continuations: 2}
+	judges, err := opts.JudgePanel()
+	if err != nil || len(judges) != 2 {
+		t.Fatalf("JudgePanel() = %v, %v", judges, err)
+	}
+	if judges[1].Provider != llm.ProviderAnthropic || judges[1].Model != "claude-sonnet-4-5" {
+		t.Errorf("judges[1] = %s/%s, want anthropic/claude-sonnet-4-5", judges[1].Provider, judges[1].Model)
+	}
+
+	// Per-role options reach the judges
+	cfg := config.New()
+	cfg.Judges = judges
+	opts.ApplyToConfig(cfg)
+	if !cfg.ChiefJudge {
+		t.Error("ApplyToConfig() should set ChiefJudge")
+	}
+	for i, j := range cfg.Judges {
+		if j.MaxContinuations != 2 {
+			t.Errorf("judge %d MaxContinuations = %d, want 2", i+1, j.MaxContinuations)
+		}
+	}
+
+	if _, err := (&Options{Judges: "deepseek,nope"}).JudgePanel(); err == nil {
+		t.Error("JudgePanel() should fail for an unknown provider")
+	}
+}
+
+func TestOptions_Aggregation(t *testing.T) {
+	for in, want := range map[string]debate.Aggregation{"": debate.AggregateMean, "mean": debate.AggregateMean, "median": debate.AggregateMedian, "majority": debate.AggregateMajority} {
+		if got, err := (&Options{JudgeAggregate: in}).Aggregation(); got != want || err != nil {
+			t.Errorf("Aggregation(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := (&Options{JudgeAggregate: "mode"}).Aggregation(); err == nil {
+		t.Error("Aggregation() should fail for an unknown aggregation")
+	}
+}
+
+func TestProvidersHelp(t *testing.T) {
+	help := providersHelp()
+	for _, spec := range llm.Providers() {
+		if !strings.Contains(help, string(spec.Name)) || !strings.Contains(help, spec.EnvSummary()) {
+			t.Errorf("providers help misses %s (%s)", spec.Name, spec.EnvSummary())
+		}
+	}
+}
+
+func TestOptions_NewCredentialSources(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // no default credentials file
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(llm.CredentialsFileEnv, "")
+	t.Setenv(llm.CredentialHelperEnv, "")
+	t.Setenv(llm.ProfileEnv, "")
+	if sources := (&Options{}).NewCredentialSources(); sources != nil {
+		t.Errorf("NewCredentialSources() = %+v, want nil without any source", sources)
+	}
+
+	t.Setenv(llm.CredentialsFileEnv, "/etc/dialecta/keys")
+	t.Setenv(llm.ProfileEnv, "team")
+	opts := &Options{Profile: "work", CredentialHelper: "pass-keys --vault ai"}
+	sources := opts.NewCredentialSources()
+	if sources == nil || sources.File != "/etc/dialecta/keys" || sources.Profile != "work" || !reflect.DeepEqual(sources.Helper, []string{"pass-keys", "--vault", "ai"}) {
+		t.Fatalf("NewCredentialSources() = %+v, want flags over the environment", sources)
+	}
+
+	cfg := config.New()
+	opts.ApplyToConfig(cfg)
+	if cfg.ProRole.Credentials == nil || cfg.JudgeRole.Credentials.Profile != "work" {
+		t.Errorf("ApplyToConfig() did not set the credential sources")
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/hrygo/dialecta/internal/llm"
 )
 
 // InputReader handles reading material input from various sources
@@ -216,21 +220,65 @@ type ModelCombination struct {
 	ConProvider   string
 }
 
-// GetModelCombinations returns available model combinations
-// Strategy: Judge matches the stronger side (Pro or dominant model)
+// GetModelCombinations returns the model combinations offered by the
+// registered providers' menus (llm.ProviderMenu), grouped by judge in
+// ascending menu order. Debates naming an unregistered provider are skipped.
 func GetModelCombinations() []ModelCombination {
-	return []ModelCombination{
-		{ID: "1", Name: "All Gemini", JudgeProvider: "gemini", ProProvider: "gemini", ConProvider: "gemini"},
-		{ID: "2", Name: "Gemini Judge, DeepSeek Debate", JudgeProvider: "gemini", ProProvider: "deepseek", ConProvider: "deepseek"},
-		{ID: "3", Name: "Gemini Judge, DeepSeek vs Qwen", JudgeProvider: "gemini", ProProvider: "deepseek", ConProvider: "dashscope"},
-		{ID: "4", Name: "Gemini Judge, Qwen Debate", JudgeProvider: "gemini", ProProvider: "dashscope", ConProvider: "dashscope"},
-		{ID: "5", Name: "All DeepSeek", JudgeProvider: "deepseek", ProProvider: "deepseek", ConProvider: "deepseek"},
-		{ID: "6", Name: "DeepSeek Judge, DeepSeek vs Qwen", JudgeProvider: "deepseek", ProProvider: "deepseek", ConProvider: "dashscope"},
-		{ID: "7", Name: "DeepSeek Judge, Qwen Debate", JudgeProvider: "deepseek", ProProvider: "dashscope", ConProvider: "dashscope"},
-		{ID: "8", Name: "All Qwen", JudgeProvider: "dashscope", ProProvider: "dashscope", ConProvider: "dashscope"},
+	var combinations []ModelCombination
+	for _, judge := range menuProviders() {
+		for _, debate := range judge.Menu.Debates {
+			pro, proOK := llm.LookupProvider(string(debate[0]))
+			con, conOK := llm.LookupProvider(string(debate[1]))
+			if !proOK || !conOK {
+				continue
+			}
+			combinations = append(combinations, ModelCombination{
+				ID:            strconv.Itoa(len(combinations) + 1),
+				Name:          combinationName(judge, pro, con),
+				JudgeProvider: string(judge.Name),
+				ProProvider:   string(pro.Name),
+				ConProvider:   string(con.Name),
+			})
+		}
+	}
+	return combinations
+}
+
+// menuProviders returns the providers with a menu, by ascending menu order
+func menuProviders() []llm.ProviderSpec {
+	var specs []llm.ProviderSpec
+	for _, spec := range llm.Providers() {
+		if spec.Menu != nil {
+			specs = append(specs, spec)
+		}
 	}
+	sort.SliceStable(specs, func(i, j int) bool { return specs[i].Menu.Order < specs[j].Menu.Order })
+	return specs
 }
 
+// combinationName names a combination, e.g. "All Qwen",
+// "Gemini Judge, DeepSeek Debate" or "Claude Judge, DeepSeek vs Qwen"
+func combinationName(judge, pro, con llm.ProviderSpec) string {
+	switch {
+	case pro.Name == con.Name && pro.Name == judge.Name:
+		return "All " + menuLabel(judge)
+	case pro.Name == con.Name:
+		return fmt.Sprintf("%s Judge, %s Debate", menuLabel(judge), menuLabel(pro))
+	}
+	return fmt.Sprintf("%s Judge, %s vs %s", menuLabel(judge), menuLabel(pro), menuLabel(con))
+}
+
+// menuLabel returns the provider's menu label, or its name without a menu
+func menuLabel(spec llm.ProviderSpec) string {
+	if spec.Menu != nil && spec.Menu.Label != "" {
+		return spec.Menu.Label
+	}
+	return string(spec.Name)
+}
+
+// judgeGroupColors tint the judge group headers of the menu, in turn
+var judgeGroupColors = []string{ColorBrightMagenta, ColorBrightBlue, ColorBrightCyan, ColorBrightRed}
+
 // SelectModelCombination prompts user to select a model combination
 func (r *InputReader) SelectModelCombination() (*ModelCombination, error) {
 	combinations := GetModelCombinations()
@@ -241,15 +289,16 @@ func (r *InputReader) SelectModelCombination() (*ModelCombination, error) {
 	fmt.Fprintf(r.out, "%s%s└──────────────────────────────────────────────────────────────┘%s\n", ColorBrightCyan, ColorBold, ColorReset)
 	fmt.Fprintln(r.out)
 
-	// Display combinations with Judge, Pro, Con info
+	// Display combinations grouped by judge
+	group := -1
 	for i, comb := range combinations {
-		// Add group headers
-		if i == 0 {
-			fmt.Fprintf(r.out, "  %s🌟 Gemini Judge:%s\n", ColorBrightMagenta, ColorReset)
-		} else if i == 4 {
-			fmt.Fprintf(r.out, "\n  %s⚡ DeepSeek Judge:%s\n", ColorBrightBlue, ColorReset)
-		} else if i == 7 {
-			fmt.Fprintf(r.out, "\n  %s🔷 Qwen Judge:%s\n", ColorBrightCyan, ColorReset)
+		if i == 0 || comb.JudgeProvider != combinations[i-1].JudgeProvider {
+			group++
+			if group > 0 {
+				fmt.Fprintln(r.out)
+			}
+			judge, _ := llm.LookupProvider(comb.JudgeProvider)
+			fmt.Fprintf(r.out, "  %s%s %s Judge:%s\n", judgeGroupColors[group%len(judgeGroupColors)], judge.Menu.Icon, menuLabel(judge), ColorReset)
 		}
 
 		fmt.Fprintf(r.out, "    %s%s[%s]%s %s%-40s%s\n",
@@ -258,7 +307,7 @@ func (r *InputReader) SelectModelCombination() (*ModelCombination, error) {
 	}
 
 	fmt.Fprintln(r.out)
-	fmt.Fprintf(r.out, "%s%s▸ Enter your choice (1-8): %s", ColorBrightGreen, ColorBold, ColorReset)
+	fmt.Fprintf(r.out, "%s%s▸ Enter your choice (1-%d): %s", ColorBrightGreen, ColorBold, len(combinations), ColorReset)
 
 	scanner := bufio.NewScanner(r.stdin)
 	if !scanner.Scan() {
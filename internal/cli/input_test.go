@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/hrygo/dialecta/internal/llm"
 )
 
 func TestNewInputReader(t *testing.T) {
@@ -208,3 +211,65 @@ func TestValidateMaterial(t *testing.T) {
 		})
 	}
 }
+
+func TestGetModelCombinations(t *testing.T) {
+	combinations := GetModelCombinations()
+
+	seen := make(map[string]bool)
+	hasClaudeJudge := false
+	for _, comb := range combinations {
+		if seen[comb.ID] {
+			t.Errorf("duplicate combination ID %q", comb.ID)
+		}
+		seen[comb.ID] = true
+
+		for _, p := range []string{comb.JudgeProvider, comb.ProProvider, comb.ConProvider} {
+			if _, err := llm.ParseProvider(p); err != nil {
+				t.Errorf("combination %q uses invalid provider %q", comb.Name, p)
+			}
+		}
+		if comb.JudgeProvider == "anthropic" {
+			hasClaudeJudge = true
+		}
+	}
+
+	if !hasClaudeJudge {
+		t.Error("GetModelCombinations() should offer a Claude judge")
+	}
+}
+
+func TestInputReader_SelectModelCombination(t *testing.T) {
+	stdin := strings.NewReader("10\n")
+	out := &bytes.Buffer{}
+	reader := NewInputReader(stdin, out)
+
+	comb, err := reader.SelectModelCombination()
+	if err != nil {
+		t.Fatalf("SelectModelCombination() error = %v", err)
+	}
+	if comb.Name != "All Claude" {
+		t.Errorf("SelectModelCombination() = %q, want %q", comb.Name, "All Claude")
+	}
+	if !strings.Contains(out.String(), "Claude Judge") {
+		t.Error("menu should list the Claude Judge group")
+	}
+}
+
+func TestGetModelCombinations_FromRegistry(t *testing.T) {
+	want := []ModelCombination{
+		{ID: "1", Name: "All Gemini", JudgeProvider: "gemini", ProProvider: "gemini", ConProvider: "gemini"},
+		{ID: "2", Name: "Gemini Judge, DeepSeek Debate", JudgeProvider: "gemini", ProProvider: "deepseek", ConProvider: "deepseek"},
+		{ID: "3", Name: "Gemini Judge, DeepSeek vs Qwen", JudgeProvider: "gemini", ProProvider: "deepseek", ConProvider: "dashscope"},
+		{ID: "4", Name: "Gemini Judge, Qwen Debate", JudgeProvider: "gemini", ProProvider: "dashscope", ConProvider: "dashscope"},
+		{ID: "5", Name: "All DeepSeek", JudgeProvider: "deepseek", ProProvider: "deepseek", ConProvider: "deepseek"},
+		{ID: "6", Name: "DeepSeek Judge, DeepSeek vs Qwen", JudgeProvider: "deepseek", ProProvider: "deepseek", ConProvider: "dashscope"},
+		{ID: "7", Name: "DeepSeek Judge, Qwen Debate", JudgeProvider: "deepseek", ProProvider: "dashscope", ConProvider: "dashscope"},
+		{ID: "8", Name: "All Qwen", JudgeProvider: "dashscope", ProProvider: "dashscope", ConProvider: "dashscope"},
+		{ID: "9", Name: "Claude Judge, DeepSeek vs Qwen", JudgeProvider: "anthropic", ProProvider: "deepseek", ConProvider: "dashscope"},
+		{ID: "10", Name: "All Claude", JudgeProvider: "anthropic", ProProvider: "anthropic", ConProvider: "anthropic"},
+	}
+	got := GetModelCombinations()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetModelCombinations() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -2,15 +2,18 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/hrygo/dialecta/internal/config"
 	"github.com/hrygo/dialecta/internal/debate"
+	"github.com/hrygo/dialecta/internal/llm"
 )
 
 // Runner orchestrates the CLI application execution
@@ -20,6 +23,8 @@ type Runner struct {
 	cfg      *config.Config
 	stream   bool
 	executor *debate.Executor
+
+	showReasoning bool
 }
 
 // NewRunner creates a new CLI runner
@@ -44,6 +49,36 @@ func NewRunnerWithOptions(cfg *config.Config, stream bool, ui *UI, input *InputR
 	}
 }
 
+// SetShowReasoning controls whether reasoning from thinking models is
+// printed after the debate
+func (r *Runner) SetShowReasoning(show bool) {
+	r.showReasoning = show
+}
+
+// SetStructured makes every role answer in schema-validated JSON
+// (see debate.Executor.SetStructured)
+func (r *Runner) SetStructured(structured bool) {
+	r.executor.SetStructured(structured)
+}
+
+// SetTools lets Pro and Con call the toolbox's tools
+// (see debate.Executor.SetTools)
+func (r *Runner) SetTools(toolbox *debate.Toolbox) {
+	r.executor.SetTools(toolbox)
+}
+
+// SetRounds sets how many rounds the debaters debate
+// (see debate.Executor.SetRounds)
+func (r *Runner) SetRounds(rounds int) {
+	r.executor.SetRounds(rounds)
+}
+
+// SetAggregation sets how a judge panel's scores are combined
+// (see debate.Executor.SetAggregation)
+func (r *Runner) SetAggregation(aggregation debate.Aggregation) {
+	r.executor.SetAggregation(aggregation)
+}
+
 // Run executes the debate with the given material
 func (r *Runner) Run(ctx context.Context, material string) error {
 	// Validate material
@@ -63,40 +98,48 @@ func (r *Runner) Run(ctx context.Context, material string) error {
 
 // runStreaming executes the debate in streaming mode using sequential display
 func (r *Runner) runStreaming(ctx context.Context, material string) error {
-	r.ui.PrintDebating()
-
-	// Status tracking
+	r.printDebating()
+
+	// Status tracking, one entry per debater: Pro and Con, or the panel's
+	// personas in order
+	sides := []debate.Side{debate.SidePro, debate.SideCon}
+	labels := []string{"🔵 Pro", "🔴 Con"}
+	if len(r.cfg.Personas) > 0 {
+		sides, labels = nil, nil
+		for _, p := range r.cfg.Personas {
+			sides = append(sides, debate.Side(p.Name))
+			labels = append(labels, p.Name)
+		}
+	}
 	var (
-		mu        sync.Mutex
-		proStatus = "Thinking"
-		conStatus = "Thinking"
-		proDone   bool
-		conDone   bool
+		mu   sync.Mutex
+		done = make([]bool, len(sides))
 	)
+	allDone := func() bool {
+		for _, d := range done {
+			if !d {
+				return false
+			}
+		}
+		return true
+	}
 
 	// Spinner control
 	var (
 		stopDebateSpinner func()
 		stopJudgeSpinner  func()
-		debateSpinnerOnce sync.Once
-		judgeSpinnerOnce  sync.Once
 	)
 
-	// Start debate phase spinner (for Pro/Con thinking)
+	// Start debate phase spinner (for the debaters thinking). It is stopped
+	// and restarted with mu held, so it checks for stop under mu before drawing.
 	startDebateSpinner := func() {
 		stop := make(chan struct{})
-		var wg sync.WaitGroup
-		wg.Add(1)
-
+		var once sync.Once
 		stopDebateSpinner = func() {
-			debateSpinnerOnce.Do(func() {
-				close(stop)
-				wg.Wait()
-			})
+			once.Do(func() { close(stop) })
 		}
 
 		go func() {
-			defer wg.Done()
 			chars := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 			i := 0
 			ticker := time.NewTicker(100 * time.Millisecond)
@@ -108,17 +151,23 @@ func (r *Runner) runStreaming(ctx context.Context, material string) error {
 					return
 				case <-ticker.C:
 					mu.Lock()
-					if !proDone || !conDone {
+					select {
+					case <-stop:
+						mu.Unlock()
+						return
+					default:
+					}
+					if !allDone() {
 						// Build status display with spinner
-						proDisplay := proStatus
-						conDisplay := conStatus
-						if !proDone && proStatus == "Thinking" {
-							proDisplay = fmt.Sprintf("Thinking... %s", chars[i%len(chars)])
-						}
-						if !conDone && conStatus == "Thinking" {
-							conDisplay = fmt.Sprintf("Thinking... %s", chars[i%len(chars)])
+						statuses := make([]string, len(sides))
+						for n, label := range labels {
+							status := "Done"
+							if !done[n] {
+								status = fmt.Sprintf("Thinking... %s", chars[i%len(chars)])
+							}
+							statuses[n] = fmt.Sprintf("%s [%s]", label, status)
 						}
-						fmt.Printf("\r\033[K⏳ Status: 🔵 Pro [%s] | 🔴 Con [%s]", proDisplay, conDisplay)
+						fmt.Printf("\r\033[K⏳ Status: %s", strings.Join(statuses, " | "))
 					}
 					mu.Unlock()
 					i++
@@ -127,6 +176,8 @@ func (r *Runner) runStreaming(ctx context.Context, material string) error {
 		}()
 	}
 
+	// Start judge phase spinner. A judge panel's verdicts stop it while they
+	// are printed and restart it, so each start gets its own stop.
 	startJudgeSpinner := func() {
 		// Stop debate spinner first
 		if stopDebateSpinner != nil {
@@ -134,11 +185,14 @@ func (r *Runner) runStreaming(ctx context.Context, material string) error {
 		}
 
 		stop := make(chan struct{})
-		var wg sync.WaitGroup
+		var (
+			wg   sync.WaitGroup
+			once sync.Once
+		)
 		wg.Add(1)
 
 		stopJudgeSpinner = func() {
-			judgeSpinnerOnce.Do(func() {
+			once.Do(func() {
 				close(stop)
 				wg.Wait()
 				fmt.Printf("\r\033[K") // Final clear
@@ -172,14 +226,13 @@ func (r *Runner) runStreaming(ctx context.Context, material string) error {
 	// Start debate spinner
 	startDebateSpinner()
 
-	r.executor.SetStream(
-		// Pro Callback
-		func(chunk string, done bool) {
+	// onDebater streams the n-th debater's One-Liner
+	onDebater := func(n int) func(string, bool) {
+		return func(chunk string, finished bool) {
 			mu.Lock()
 			defer mu.Unlock()
-			if done {
-				proDone = true
-				proStatus = "Done"
+			if finished {
+				done[n] = true
 				return
 			}
 
@@ -190,65 +243,68 @@ func (r *Runner) runStreaming(ctx context.Context, material string) error {
 			}
 
 			fmt.Printf("\r\033[K")
-			r.ui.PrintProHeader()
+			r.ui.PrintDebaterHeader(n, sides[n])
 			fmt.Println(chunk)
 			fmt.Println("") // Spacing
 
-			// Keep status as "Thinking" - still generating full argument
-			// proStatus remains unchanged
-
-			// Restart spinner if con is still thinking
-			if !conDone {
+			// Still generating the full argument, so the debater keeps
+			// thinking; restart the spinner if anyone is
+			if !allDone() {
 				startDebateSpinner()
 			}
-		},
-		// Con Callback
-		func(chunk string, done bool) {
-			mu.Lock()
-			defer mu.Unlock()
-			if done {
-				conDone = true
-				conStatus = "Done"
-				return
-			}
-
-			if stopDebateSpinner != nil {
-				stopDebateSpinner()
-			}
+		}
+	}
+	onJudge := func(chunk string, finished bool) {
+		mu.Lock()
+		defer mu.Unlock()
 
-			fmt.Printf("\r\033[K")
-			r.ui.PrintConHeader()
-			fmt.Println(chunk)
-			fmt.Println("")
+		// Stop spinner on first activity
+		if stopJudgeSpinner != nil {
+			stopJudgeSpinner()
+		}
 
-			// Keep status as "Thinking" - still generating full argument
-			// conStatus remains unchanged
+		if finished {
+			return
+		}
 
-			// Restart spinner if pro is still thinking
-			if !proDone {
-				startDebateSpinner()
-			}
-		},
-		// Judge Callback
-		func(chunk string, done bool) {
-			mu.Lock()
-			defer mu.Unlock()
+		fmt.Printf("\r\033[K")
+		r.ui.PrintJudgeHeader()
+		fmt.Println(chunk)
+		fmt.Println("")
+	}
 
-			// Stop spinner on first activity
-			if stopJudgeSpinner != nil {
-				stopJudgeSpinner()
-			}
+	if len(r.cfg.Personas) > 0 {
+		index := make(map[string]int, len(sides))
+		for n, side := range sides {
+			index[string(side)] = n
+		}
+		r.executor.SetPanelStream(func(name, chunk string, finished bool) {
+			onDebater(index[name])(chunk, finished)
+		}, onJudge)
+	} else {
+		r.executor.SetStream(onDebater(0), onDebater(1), onJudge)
+	}
 
-			if done {
-				return
-			}
+	// Label each round of a multi-round debate; every debater thinks again
+	r.executor.SetRoundStartCallback(func(round, rounds int) {
+		mu.Lock()
+		defer mu.Unlock()
+		stopDebateSpinner()
+		fmt.Printf("\r\033[K")
+		r.ui.PrintRoundHeader(round, rounds)
+		clear(done)
+		startDebateSpinner()
+	})
 
-			fmt.Printf("\r\033[K")
-			r.ui.PrintJudgeHeader()
-			fmt.Println(chunk)
-			fmt.Println("")
-		},
-	)
+	// Print each verdict of a judge panel as it arrives; the others, or the
+	// chief judge, are still deliberating
+	r.executor.SetJudgeVerdictCallback(func(i int, verdict debate.JudgeVerdict) {
+		mu.Lock()
+		defer mu.Unlock()
+		stopJudgeSpinner()
+		r.ui.PrintJudgeVerdict(i, verdict)
+		startJudgeSpinner()
+	})
 
 	// Set judge start callback to trigger spinner at the right moment
 	r.executor.SetJudgeStartCallback(func() {
@@ -264,9 +320,15 @@ func (r *Runner) runStreaming(ctx context.Context, material string) error {
 
 	if err != nil {
 		r.ui.PrintError(err.Error())
+		r.printErrorHint(err)
 		return err
 	}
 
+	r.printFallbackNotice(result)
+	r.printTruncationNotice(result)
+	r.printReasoning(result)
+	r.ui.PrintUsage(result)
+
 	// Final Summary
 	fmt.Println()
 	r.ui.PrintDivider()
@@ -280,19 +342,159 @@ func (r *Runner) runStreaming(ctx context.Context, material string) error {
 
 // runNonStreaming executes the debate in non-streaming mode
 func (r *Runner) runNonStreaming(ctx context.Context, material string) error {
-	r.ui.PrintDebating()
+	r.printDebating()
 
 	result, err := r.executor.Execute(ctx, material)
 	if err != nil {
+		r.printErrorHint(err)
 		return fmt.Errorf("执行失败: %w", err)
 	}
 
+	r.printFallbackNotice(result)
+	r.printTruncationNotice(result)
+	r.printReasoning(result)
 	r.ui.PrintResult(result)
 	r.ui.PrintComplete()
 
 	return nil
 }
 
+// printDebating prints the debating status of Pro and Con, or of the panel
+func (r *Runner) printDebating() {
+	if len(r.cfg.Personas) > 0 {
+		r.ui.PrintPanelDebating(r.cfg.Personas)
+		return
+	}
+	r.ui.PrintDebating()
+}
+
+// printFallbackNotice warns when a role was answered by a fallback provider
+func (r *Runner) printFallbackNotice(result *debate.Result) {
+	for _, d := range result.Debaters() {
+		if d.Model.Fallback {
+			r.ui.PrintWarning(fmt.Sprintf("%s主模型不可用，已切换至备用模型 %s/%s", d.Side.Name(), d.Model.Provider, d.Model.Model))
+		}
+	}
+	for i, v := range result.Verdicts {
+		if v.Model.Fallback {
+			r.ui.PrintWarning(fmt.Sprintf("裁判 %d 主模型不可用，已切换至备用模型 %s/%s", i+1, v.Model.Provider, v.Model.Model))
+		}
+	}
+	if result.JudgeModel.Fallback {
+		r.ui.PrintWarning(fmt.Sprintf("裁决方主模型不可用，已切换至备用模型 %s/%s", result.JudgeModel.Provider, result.JudgeModel.Model))
+	}
+}
+
+// printTruncationNotice warns when a role's answer was cut off at max tokens,
+// in any round
+func (r *Runner) printTruncationNotice(result *debate.Result) {
+	truncated := make(map[debate.Side]bool)
+	for _, d := range result.Debaters() {
+		truncated[d.Side] = d.Truncated
+	}
+	for _, turn := range result.Turns {
+		truncated[turn.Side] = truncated[turn.Side] || turn.Truncated
+	}
+	var names []string
+	for _, d := range result.Debaters() {
+		if truncated[d.Side] {
+			names = append(names, d.Side.Name())
+		}
+	}
+	for i, v := range result.Verdicts {
+		if v.Truncated {
+			names = append(names, fmt.Sprintf("裁判 %d ", i+1))
+		}
+	}
+	if result.JudgeTruncated {
+		names = append(names, "裁决方")
+	}
+	for _, name := range names {
+		r.ui.PrintWarning(fmt.Sprintf("%s输出达到 max tokens 上限被截断，内容不完整（可用 --continuations 自动续写）", name))
+	}
+}
+
+// printReasoning prints each role's reasoning when enabled
+func (r *Runner) printReasoning(result *debate.Result) {
+	if !r.showReasoning {
+		return
+	}
+	for i, d := range result.Debaters() {
+		label, color := debaterLabel(i, d.Side)
+		r.ui.PrintReasoning(label, color, d.Reasoning)
+	}
+	for i, v := range result.Verdicts {
+		label, color := judgeLabel(i)
+		r.ui.PrintReasoning(label, color, v.Reasoning)
+	}
+	r.ui.PrintReasoning("ADJ", ColorBrightYellow, result.JudgeReasoning)
+}
+
+// printErrorHint prints a tailored suggestion for typed provider errors
+func (r *Runner) printErrorHint(err error) {
+	if hint := ErrorHint(err); hint != "" {
+		r.ui.PrintHint(hint)
+	}
+}
+
+// ErrorHint returns an actionable suggestion for a provider error,
+// or an empty string if the error is not a recognised provider failure
+func ErrorHint(err error) string {
+	var (
+		authErr    *llm.AuthError
+		rateErr    *llm.RateLimitError
+		ctxErr     *llm.ContextLengthError
+		filterErr  *llm.ContentFilterError
+		serverErr  *llm.ServerError
+		timeoutErr *llm.TimeoutError
+		apiErr     *llm.APIError
+		providerID string
+	)
+	if errors.As(err, &apiErr) {
+		providerID = string(apiErr.Provider)
+		if apiErr.Model != "" {
+			providerID += "/" + apiErr.Model
+		}
+	}
+
+	switch {
+	case errors.As(err, &timeoutErr):
+		return timeoutHint(timeoutErr)
+	case errors.As(err, &authErr):
+		return fmt.Sprintf("认证失败 (%s)：请检查该 provider 的 API Key 是否正确、是否已过期，以及是否有权访问该模型", providerID)
+	case errors.As(err, &rateErr):
+		if rateErr.Code == "insufficient_quota" {
+			return fmt.Sprintf("额度已用尽 (%s)：请充值或为该角色换用其他 provider", providerID)
+		}
+		if rateErr.RetryAfter > 0 {
+			return fmt.Sprintf("触发限流 (%s)：provider 建议 %s 后重试，或为该角色换用其他 provider", providerID, rateErr.RetryAfter)
+		}
+		return fmt.Sprintf("触发限流 (%s)：请稍后重试，或为该角色换用其他 provider", providerID)
+	case errors.As(err, &ctxErr):
+		return fmt.Sprintf("输入超出模型上下文长度 (%s)：请精简材料，或通过 --*-model 换用上下文更长的模型", providerID)
+	case errors.As(err, &filterErr):
+		return fmt.Sprintf("内容被安全策略拦截 (%s)：请调整材料措辞，或为该角色换用其他 provider", providerID)
+	case errors.As(err, &serverErr):
+		return fmt.Sprintf("服务端错误 (%s)：provider 可能暂时不可用，请稍后重试或切换 provider", providerID)
+	}
+	return ""
+}
+
+// timeoutHint suggests the flag that adjusts the timeout err ran into
+func timeoutHint(err *llm.TimeoutError) string {
+	providerID := string(err.Provider)
+	if err.Model != "" {
+		providerID += "/" + err.Model
+	}
+	switch err.Kind {
+	case llm.TimeoutConnect:
+		return fmt.Sprintf("连接超时 (%s)：请检查网络、代理或 base URL，或通过 --connect-timeout 放宽限制", providerID)
+	case llm.TimeoutIdle:
+		return fmt.Sprintf("流式输出停滞 (%s)：provider 在 %s 内没有返回任何内容，可通过 --idle-timeout 放宽限制，或为该角色配置 fallback", providerID, err.Timeout)
+	}
+	return fmt.Sprintf("请求超时 (%s)：%s 内未完成回答，可通过 --timeout 放宽限制，或降低 max tokens", providerID, err.Timeout)
+}
+
 // SetupContext creates a context that can be cancelled by interrupt signals
 func SetupContext() (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -2,9 +2,14 @@ package cli
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hrygo/dialecta/internal/config"
+	"github.com/hrygo/dialecta/internal/llm"
 )
 
 func TestNewRunner(t *testing.T) {
@@ -94,3 +99,42 @@ func TestSetupContext(t *testing.T) {
 		t.Error("Context should be done after cancel")
 	}
 }
+
+func TestErrorHint(t *testing.T) {
+	base := func() *llm.APIError {
+		return &llm.APIError{Provider: llm.ProviderDeepSeek, Model: "deepseek-chat", StatusCode: 400}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		contains string
+	}{
+		{"auth", &llm.AuthError{APIError: base()}, "API Key"},
+		{"rate limit", &llm.RateLimitError{APIError: base()}, "限流"},
+		{"rate limit with retry-after", &llm.RateLimitError{APIError: &llm.APIError{Provider: llm.ProviderGemini, RetryAfter: 30 * time.Second}}, "30s"},
+		{"quota", &llm.RateLimitError{APIError: &llm.APIError{Code: "insufficient_quota"}}, "额度"},
+		{"context length", &llm.ContextLengthError{APIError: base()}, "上下文"},
+		{"content filter", &llm.ContentFilterError{APIError: base()}, "安全策略"},
+		{"server", &llm.ServerError{APIError: base()}, "服务端"},
+		{"wrapped", fmt.Errorf("affirmative: %w", &llm.AuthError{APIError: base()}), "deepseek/deepseek-chat"},
+		{"idle timeout", fmt.Errorf("negative: %w", &llm.TimeoutError{Provider: llm.ProviderDashScope, Model: "qwen-plus", Kind: llm.TimeoutIdle, Timeout: time.Minute}), "--idle-timeout"},
+		{"total timeout", &llm.TimeoutError{Provider: llm.ProviderGemini, Kind: llm.TimeoutTotal, Timeout: time.Minute}, "--timeout"},
+		{"connect timeout", &llm.TimeoutError{Provider: llm.ProviderOllama, Kind: llm.TimeoutConnect, Timeout: time.Second}, "--connect-timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorHint(tt.err); !strings.Contains(got, tt.contains) {
+				t.Errorf("ErrorHint() = %q, want it to contain %q", got, tt.contains)
+			}
+		})
+	}
+
+	if got := ErrorHint(errors.New("boom")); got != "" {
+		t.Errorf("ErrorHint(plain error) = %q, want empty", got)
+	}
+	if got := ErrorHint(base()); got != "" {
+		t.Errorf("ErrorHint(untyped APIError) = %q, want empty", got)
+	}
+}
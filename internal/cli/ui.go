@@ -8,11 +8,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/hrygo/dialecta/internal/config"
 	"github.com/hrygo/dialecta/internal/debate"
+	"github.com/hrygo/dialecta/internal/llm"
 )
 
 // ANSI color and style codes for terminal output
@@ -138,30 +140,97 @@ func (u *UI) PrintBanner() {
 func (u *UI) PrintConfig(cfg *config.Config) {
 	fmt.Fprintf(u.out, "%s%s┌─ 🧠 AI Configuration ─────────────────────────────────────────┐%s\n", ColorBrightBlue, ColorBold, ColorReset)
 
-	// Pro role
-	fmt.Fprintf(u.out, "%s│%s  %s▹ PRO%s  %s%-12s%s │ %s%s%s\n",
-		ColorBrightBlue, ColorReset,
-		ColorBrightGreen, ColorReset,
-		ColorBold, cfg.ProRole.Provider, ColorReset,
-		ColorDim, cfg.ProRole.Model, ColorReset)
+	if len(cfg.Personas) > 0 {
+		// Panel personas
+		for i, p := range cfg.Personas {
+			fmt.Fprintf(u.out, "%s│%s  %s▹ %s%s  %s%-12s%s │ %s%s%s\n",
+				ColorBrightBlue, ColorReset,
+				PersonaColor(i), p.Name, ColorReset,
+				ColorBold, p.Role.Provider, ColorReset,
+				ColorDim, p.Role.Model, ColorReset)
+			u.printFallbacks(p.Role.Fallbacks)
+		}
+	} else {
+		// Pro role
+		fmt.Fprintf(u.out, "%s│%s  %s▹ PRO%s  %s%-12s%s │ %s%s%s\n",
+			ColorBrightBlue, ColorReset,
+			ColorBrightGreen, ColorReset,
+			ColorBold, cfg.ProRole.Provider, ColorReset,
+			ColorDim, cfg.ProRole.Model, ColorReset)
+		u.printFallbacks(cfg.ProRole.Fallbacks)
+
+		// Con role
+		fmt.Fprintf(u.out, "%s│%s  %s▹ CON%s  %s%-12s%s │ %s%s%s\n",
+			ColorBrightBlue, ColorReset,
+			ColorBrightRed, ColorReset,
+			ColorBold, cfg.ConRole.Provider, ColorReset,
+			ColorDim, cfg.ConRole.Model, ColorReset)
+		u.printFallbacks(cfg.ConRole.Fallbacks)
+	}
 
-	// Con role
-	fmt.Fprintf(u.out, "%s│%s  %s▹ CON%s  %s%-12s%s │ %s%s%s\n",
-		ColorBrightBlue, ColorReset,
-		ColorBrightRed, ColorReset,
-		ColorBold, cfg.ConRole.Provider, ColorReset,
-		ColorDim, cfg.ConRole.Model, ColorReset)
+	// Judge panel
+	for i, j := range cfg.Judges {
+		label, color := judgeLabel(i)
+		fmt.Fprintf(u.out, "%s│%s  %s▹ %-3s%s  %s%-12s%s │ %s%s%s\n",
+			ColorBrightBlue, ColorReset,
+			color, label, ColorReset,
+			ColorBold, j.Provider, ColorReset,
+			ColorDim, j.Model, ColorReset)
+		u.printFallbacks(j.Fallbacks)
+	}
 
-	// Judge role
-	fmt.Fprintf(u.out, "%s│%s  %s▹ ADJ%s  %s%-12s%s │ %s%s%s\n",
-		ColorBrightBlue, ColorReset,
-		ColorBrightYellow, ColorReset,
-		ColorBold, cfg.JudgeRole.Provider, ColorReset,
-		ColorDim, cfg.JudgeRole.Model, ColorReset)
+	// Judge role, or the judge panel's chief judge
+	if cfg.UsesJudgeRole() {
+		fmt.Fprintf(u.out, "%s│%s  %s▹ ADJ%s  %s%-12s%s │ %s%s%s\n",
+			ColorBrightBlue, ColorReset,
+			ColorBrightYellow, ColorReset,
+			ColorBold, cfg.JudgeRole.Provider, ColorReset,
+			ColorDim, cfg.JudgeRole.Model, ColorReset)
+		u.printFallbacks(cfg.JudgeRole.Fallbacks)
+	}
+
+	if cfg.JudgeRole.Cache != nil {
+		fmt.Fprintf(u.out, "%s│%s  %s💾 cache%s %s%s%s\n",
+			ColorBrightBlue, ColorReset,
+			ColorBold, ColorReset,
+			ColorDim, cfg.JudgeRole.Cache.Dir(), ColorReset)
+	}
+	u.printRateLimits(cfg.JudgeRole.RateLimits)
 
 	fmt.Fprintf(u.out, "%s%s└───────────────────────────────────────────────────────────────┘%s\n\n", ColorBrightBlue, ColorBold, ColorReset)
 }
 
+// printFallbacks prints a role's fallback chain below its config line
+func (u *UI) printFallbacks(fallbacks []config.Fallback) {
+	if len(fallbacks) == 0 {
+		return
+	}
+	chain := make([]string, len(fallbacks))
+	for i, f := range fallbacks {
+		chain[i] = f.String()
+	}
+	fmt.Fprintf(u.out, "%s│%s         %s↳ fallback: %s%s\n",
+		ColorBrightBlue, ColorReset,
+		ColorDim, strings.Join(chain, " → "), ColorReset)
+}
+
+// printRateLimits prints one line per rate-limited provider, sorted by name
+func (u *UI) printRateLimits(limits map[llm.Provider]llm.RateLimit) {
+	providers := make([]string, 0, len(limits))
+	for p, limit := range limits {
+		if !limit.IsZero() {
+			providers = append(providers, string(p))
+		}
+	}
+	sort.Strings(providers)
+	for _, p := range providers {
+		fmt.Fprintf(u.out, "%s│%s  %s⏱ limit%s %s%s: %s%s\n",
+			ColorBrightBlue, ColorReset,
+			ColorBold, ColorReset,
+			ColorDim, p, limits[llm.Provider(p)], ColorReset)
+	}
+}
+
 // PrintDebating prints the debating status with animated-style indicators
 func (u *UI) PrintDebating() {
 	fmt.Fprintf(u.out, "%s%s◉ INITIATING PARALLEL DEBATE SEQUENCE...%s\n", ColorBrightYellow, ColorBold, ColorReset)
@@ -169,6 +238,20 @@ func (u *UI) PrintDebating() {
 	fmt.Fprintf(u.out, "%s  └─ 🔴 反方 Agent: Generating counter-arguments...%s\n\n", ColorDim, ColorReset)
 }
 
+// PrintPanelDebating prints the debating status of a panel debate, one line
+// per persona
+func (u *UI) PrintPanelDebating(personas []config.Persona) {
+	fmt.Fprintf(u.out, "%s%s◉ INITIATING PARALLEL PANEL SEQUENCE...%s\n", ColorBrightYellow, ColorBold, ColorReset)
+	for i, p := range personas {
+		branch := "├─"
+		if i == len(personas)-1 {
+			branch = "└─"
+		}
+		fmt.Fprintf(u.out, "%s  %s %s%s%s%s: Generating opinion...%s\n", ColorDim, branch, PersonaColor(i), p.Name, ColorReset, ColorDim, ColorReset)
+	}
+	fmt.Fprintln(u.out)
+}
+
 // PrintComplete prints the completion message with a success indicator
 func (u *UI) PrintComplete() {
 	fmt.Fprintln(u.out)
@@ -190,6 +273,11 @@ func (u *UI) PrintWarning(message string) {
 	fmt.Fprintf(u.err, "\n%s%s⚡ %s%s\n", ColorBrightYellow, ColorBold, message, ColorReset)
 }
 
+// PrintHint prints an actionable suggestion, typically after an error
+func (u *UI) PrintHint(message string) {
+	fmt.Fprintf(u.err, "%s%s💡 %s%s\n\n", ColorBrightCyan, ColorBold, message, ColorReset)
+}
+
 // PrintSectionHeader prints a section header with the given title, icon and color
 func (u *UI) PrintSectionHeader(title, icon, color string) {
 	fmt.Fprintln(u.out)
@@ -214,6 +302,47 @@ func (u *UI) PrintConHeader() {
 	fmt.Fprintf(u.out, "%s━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━%s\n", ColorRed, ColorReset)
 }
 
+// personaColors tell panel personas apart, in turn; yellow is the Judge's
+var personaColors = []string{ColorBrightCyan, ColorBrightMagenta, ColorBrightBlue, ColorBrightGreen, ColorBrightRed, ColorBrightWhite}
+
+// PersonaColor returns the color of the i-th panel persona
+func PersonaColor(i int) string {
+	return personaColors[i%len(personaColors)]
+}
+
+// PrintPersonaHeader prints a panel persona's section header
+func (u *UI) PrintPersonaHeader(name, color string) {
+	fmt.Fprintln(u.out)
+	fmt.Fprintf(u.out, "%s%s━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━%s\n", color, ColorBold, ColorReset)
+	fmt.Fprintf(u.out, "%s%s   PANEL OPINION │ %s%s\n", color, ColorBold, name, ColorReset)
+	fmt.Fprintf(u.out, "%s━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━%s\n", color, ColorReset)
+}
+
+// PrintDebaterHeader prints the section header of the i-th debater:
+// Pro, Con or a panel persona
+func (u *UI) PrintDebaterHeader(i int, side debate.Side) {
+	switch side {
+	case debate.SidePro:
+		u.PrintProHeader()
+	case debate.SideCon:
+		u.PrintConHeader()
+	default:
+		u.PrintPersonaHeader(side.Name(), PersonaColor(i))
+	}
+}
+
+// debaterLabel returns the short label and color of the i-th debater for
+// usage rows and reasoning
+func debaterLabel(i int, side debate.Side) (label, color string) {
+	switch side {
+	case debate.SidePro:
+		return "PRO", ColorBrightGreen
+	case debate.SideCon:
+		return "CON", ColorBrightRed
+	}
+	return side.Name(), PersonaColor(i)
+}
+
 // PrintJudgeHeader prints the adjudicator (judge) section header
 func (u *UI) PrintJudgeHeader() {
 	fmt.Fprintln(u.out)
@@ -222,16 +351,153 @@ func (u *UI) PrintJudgeHeader() {
 	fmt.Fprintf(u.out, "%s━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━%s\n", ColorYellow, ColorReset)
 }
 
-// PrintResult prints the final debate result
+// judgeLabel returns the short label and color of the i-th judge of a judge
+// panel for config lines, usage rows and reasoning
+func judgeLabel(i int) (label, color string) {
+	return fmt.Sprintf("J%d", i+1), ColorYellow
+}
+
+// hasJudge reports whether the Judge fields of result hold a verdict of
+// their own: the Judge's, or a judge panel's chief judge's
+func hasJudge(result *debate.Result) bool {
+	return len(result.Verdicts) == 0 || result.JudgeModel.Provider != ""
+}
+
+// PrintJudgeVerdict prints the i-th verdict of a judge panel: the judge's
+// model and One-Liner, or its error when it failed
+func (u *UI) PrintJudgeVerdict(i int, verdict debate.JudgeVerdict) {
+	label, color := judgeLabel(i)
+	fmt.Fprintf(u.out, "%s%s⚖️  %s%s %s%s%s\n", color, ColorBold, label, ColorReset, ColorDim, verdict.Model, ColorReset)
+	if verdict.Err != nil {
+		fmt.Fprintf(u.out, "   %s❌ %v%s\n", ColorRed, verdict.Err, ColorReset)
+		return
+	}
+	fmt.Fprintf(u.out, "   %s\n", verdict.OneLiner)
+}
+
+// PrintJudgePanel prints every verdict of a judge panel and their
+// aggregate, or nothing without a panel
+func (u *UI) PrintJudgePanel(result *debate.Result) {
+	if result.Consensus == nil {
+		return
+	}
+	u.PrintSectionHeader("JUDGE PANEL │ 裁判团", "⚖️", ColorYellow)
+	for i, v := range result.Verdicts {
+		u.PrintJudgeVerdict(i, v)
+	}
+	u.PrintConsensus(result.Consensus)
+}
+
+// PrintConsensus prints the aggregate of a judge panel's verdicts
+func (u *UI) PrintConsensus(consensus *debate.Consensus) {
+	fmt.Fprintf(u.out, "%s%s∑  %s%s\n", ColorBrightYellow, ColorBold, consensus.Summary(), ColorReset)
+}
+
+// PrintRoundHeader labels a round of a multi-round debate: the opening
+// statements in round 1, rebuttals after that
+func (u *UI) PrintRoundHeader(round, rounds int) {
+	stage := "OPENING STATEMENTS │ 立论"
+	if round > 1 {
+		stage = "REBUTTALS │ 交叉反驳"
+	}
+	fmt.Fprintln(u.out)
+	fmt.Fprintf(u.out, "%s%s◉ ROUND %d/%d · %s%s\n", ColorBrightMagenta, ColorBold, round, rounds, stage, ColorReset)
+}
+
+// PrintResult prints the final debate result, round by round when there
+// were rebuttals
 func (u *UI) PrintResult(result *debate.Result) {
-	u.PrintProHeader()
-	fmt.Fprintln(u.out, result.ProFullBody)
+	debaters := result.Debaters()
+	rounds := result.Rounds()
+	if rounds > 1 {
+		u.PrintRoundHeader(1, rounds)
+	}
+	for i, d := range debaters {
+		u.PrintDebaterHeader(i, d.Side)
+		fmt.Fprintln(u.out, d.FullBody)
+	}
 
-	u.PrintConHeader()
-	fmt.Fprintln(u.out, result.ConFullBody)
+	for i, turn := range result.Turns {
+		if turn.Round == 1 {
+			continue
+		}
+		n := i % len(debaters)
+		if n == 0 {
+			u.PrintRoundHeader(turn.Round, rounds)
+		}
+		u.PrintDebaterHeader(n, turn.Side)
+		fmt.Fprintln(u.out, turn.Body)
+	}
 
+	u.PrintJudgePanel(result)
 	u.PrintJudgeHeader()
 	fmt.Fprintln(u.out, result.VerdictFullBody)
+
+	u.PrintUsage(result)
+}
+
+// PrintReasoning prints a role's reasoning dimmed, so it reads as secondary
+// to the argument itself. Empty reasoning prints nothing.
+func (u *UI) PrintReasoning(label, color, reasoning string) {
+	reasoning = strings.TrimSpace(reasoning)
+	if reasoning == "" {
+		return
+	}
+	fmt.Fprintln(u.out)
+	fmt.Fprintf(u.out, "%s%s🧠 %s Reasoning%s\n", color, ColorBold, label, ColorReset)
+	for _, line := range strings.Split(reasoning, "\n") {
+		fmt.Fprintf(u.out, "%s  │ %s%s\n", ColorDim, line, ColorReset)
+	}
+}
+
+// PrintUsage prints per-role token usage and estimated cost
+func (u *UI) PrintUsage(result *debate.Result) {
+	fmt.Fprintln(u.out)
+	fmt.Fprintf(u.out, "%s%s┌─ 📊 Token Usage ──────────────────────────────────────────────┐%s\n", ColorBrightBlue, ColorBold, ColorReset)
+	row := func(label, color string, usage debate.RoleUsage) {
+		fmt.Fprintf(u.out, "%s│%s  %s%-5s%s  in %s%7d%s (cached %d)  out %s%7d%s  %s%s%s\n",
+			ColorBrightBlue, ColorReset,
+			color, label, ColorReset,
+			ColorBold, usage.PromptTokens, ColorReset, usage.CachedTokens,
+			ColorBold, usage.CompletionTokens, ColorReset,
+			ColorDim, usage.CostString(), ColorReset)
+	}
+	for i, d := range result.Debaters() {
+		label, color := debaterLabel(i, d.Side)
+		row(label, color, d.Usage)
+	}
+	for i, v := range result.Verdicts {
+		label, color := judgeLabel(i)
+		row(label, color, v.Usage)
+	}
+	if hasJudge(result) {
+		row("ADJ", ColorBrightYellow, result.JudgeUsage)
+	}
+	row("TOTAL", ColorBrightWhite, result.TotalUsage())
+	fmt.Fprintf(u.out, "%s%s└───────────────────────────────────────────────────────────────┘%s\n", ColorBrightBlue, ColorBold, ColorReset)
+}
+
+// PrintCachePruned prints the outcome of a cache prune
+func (u *UI) PrintCachePruned(dir string, stats llm.PruneStats) {
+	fmt.Fprintf(u.out, "%s%s✓ Cache pruned%s %s%s%s\n", ColorBrightGreen, ColorBold, ColorReset, ColorDim, dir, ColorReset)
+	fmt.Fprintf(u.out, "  removed %d entries (%.1f MB), kept %d entries (%.1f MB)\n",
+		stats.Removed, float64(stats.Freed)/(1024*1024),
+		stats.Remaining, float64(stats.Size)/(1024*1024))
+}
+
+// PrintOllamaModels prints the locally installed Ollama models
+func (u *UI) PrintOllamaModels(models []llm.OllamaModel) {
+	fmt.Fprintf(u.out, "%s%s┌─ 🦙 Local Ollama Models ──────────────────────────────────────┐%s\n", ColorBrightBlue, ColorBold, ColorReset)
+	if len(models) == 0 {
+		fmt.Fprintf(u.out, "%s│%s  %sNo models installed. Run: ollama pull llama3.1%s\n", ColorBrightBlue, ColorReset, ColorDim, ColorReset)
+	}
+	for _, m := range models {
+		fmt.Fprintf(u.out, "%s│%s  %s▹ %-40s%s %s%.1f GB%s\n",
+			ColorBrightBlue, ColorReset,
+			ColorBold, m.Name, ColorReset,
+			ColorDim, float64(m.Size)/1e9, ColorReset)
+	}
+	fmt.Fprintf(u.out, "%s%s└───────────────────────────────────────────────────────────────┘%s\n", ColorBrightBlue, ColorBold, ColorReset)
 }
 
 // Print writes content to the output
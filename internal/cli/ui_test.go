@@ -7,6 +7,7 @@ import (
 
 	"github.com/hrygo/dialecta/internal/config"
 	"github.com/hrygo/dialecta/internal/debate"
+	"github.com/hrygo/dialecta/internal/llm"
 )
 
 func TestNewUI(t *testing.T) {
@@ -65,6 +66,43 @@ func TestUI_PrintConfig(t *testing.T) {
 	if !strings.Contains(output, "ADJ") && !strings.Contains(output, "裁决") {
 		t.Error("PrintConfig() should contain 'ADJ' or '裁决'")
 	}
+	if strings.Contains(output, "limit") {
+		t.Error("PrintConfig() should not show rate limits by default")
+	}
+
+	out.Reset()
+	cfg.SetRateLimit(llm.ProviderDashScope, llm.RateLimit{RequestsPerMinute: 60})
+	ui.PrintConfig(cfg)
+	if !strings.Contains(out.String(), "dashscope: 60 rpm") {
+		t.Errorf("PrintConfig() should show the dashscope rate limit, got %q", out.String())
+	}
+}
+
+func TestUI_PrintConfig_Panel(t *testing.T) {
+	var out bytes.Buffer
+	ui := NewUI(&out, &bytes.Buffer{})
+	cfg := config.New()
+	cfg.Personas = []config.Persona{
+		{Name: "CFO", Role: config.DefaultProRole},
+		{Name: "Security", Role: config.RoleConfig{Provider: llm.ProviderOpenAI, Model: "gpt-4o"}},
+	}
+
+	ui.PrintConfig(cfg)
+	output := out.String()
+	for _, want := range []string{"▹ CFO", "▹ Security", "gpt-4o", "ADJ"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PrintConfig() missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "PRO") || strings.Contains(output, "CON") {
+		t.Errorf("PrintConfig() shows Pro/Con in a panel debate:\n%s", output)
+	}
+
+	out.Reset()
+	ui.PrintPanelDebating(cfg.Personas)
+	if output := out.String(); !strings.Contains(output, "├─ "+PersonaColor(0)+"CFO") || !strings.Contains(output, "└─ "+PersonaColor(1)+"Security") {
+		t.Errorf("PrintPanelDebating() = %q, want a line per persona", output)
+	}
 }
 
 func TestUI_PrintDebating(t *testing.T) {
@@ -119,6 +157,17 @@ func TestUI_PrintWarning(t *testing.T) {
 	}
 }
 
+func TestUI_PrintHint(t *testing.T) {
+	var errOut bytes.Buffer
+	ui := NewUI(&bytes.Buffer{}, &errOut)
+
+	ui.PrintHint("hint message")
+
+	if !strings.Contains(errOut.String(), "hint message") {
+		t.Error("PrintHint() should write the message to the error writer")
+	}
+}
+
 func TestUI_PrintSectionHeader(t *testing.T) {
 	var out bytes.Buffer
 	ui := NewUI(&out, &bytes.Buffer{})
@@ -197,6 +246,158 @@ func TestUI_PrintResult(t *testing.T) {
 	}
 }
 
+func TestUI_PrintResult_Rounds(t *testing.T) {
+	var out bytes.Buffer
+	ui := NewUI(&out, &bytes.Buffer{})
+
+	ui.PrintResult(&debate.Result{
+		ProFullBody: "Pro opening",
+		ConFullBody: "Con opening",
+		Turns: []debate.Turn{
+			{Round: 1, Side: debate.SidePro, Body: "Pro opening"},
+			{Round: 1, Side: debate.SideCon, Body: "Con opening"},
+			{Round: 2, Side: debate.SidePro, Body: "Pro rebuttal"},
+			{Round: 2, Side: debate.SideCon, Body: "Con rebuttal"},
+		},
+	})
+
+	output := out.String()
+	last := 0
+	for _, want := range []string{"ROUND 1/2", "Pro opening", "Con opening", "ROUND 2/2", "Pro rebuttal", "Con rebuttal"} {
+		i := strings.Index(output, want)
+		if i < last {
+			t.Errorf("PrintResult() is missing %q or has it out of order:\n%s", want, output)
+		}
+		last = i
+	}
+	if strings.Count(output, "ROUND") != 2 {
+		t.Errorf("PrintResult() should label each round once:\n%s", output)
+	}
+}
+
+func TestUI_PrintResult_Panel(t *testing.T) {
+	var out bytes.Buffer
+	ui := NewUI(&out, &bytes.Buffer{})
+
+	result := &debate.Result{
+		Panel: []debate.DebaterResult{
+			{Side: "CFO", FullBody: "CFO opinion", Usage: debate.RoleUsage{Usage: llm.Usage{PromptTokens: 1234}}},
+			{Side: "Security", FullBody: "Security opinion"},
+		},
+		Turns: []debate.Turn{
+			{Round: 1, Side: "CFO", Body: "CFO opinion"},
+			{Round: 1, Side: "Security", Body: "Security opinion"},
+			{Round: 2, Side: "CFO", Body: "CFO rebuttal"},
+			{Round: 2, Side: "Security", Body: "Security rebuttal"},
+		},
+		VerdictFullBody: "verdict",
+	}
+	ui.PrintResult(result)
+
+	output := out.String()
+	last := 0
+	for _, want := range []string{
+		PersonaColor(0) + ColorBold + "   PANEL OPINION │ CFO", "CFO opinion",
+		PersonaColor(1) + ColorBold + "   PANEL OPINION │ Security", "Security opinion",
+		"ROUND 2/2", "CFO rebuttal", "Security rebuttal", "verdict", "1234",
+	} {
+		i := strings.Index(output, want)
+		if i < last {
+			t.Errorf("PrintResult() is missing %q or has it out of order:\n%s", want, output)
+		}
+		last = i
+	}
+	if strings.Contains(output, "AFFIRMATIVE") || strings.Contains(output, "PRO ") {
+		t.Errorf("PrintResult() shows Pro in a panel debate:\n%s", output)
+	}
+}
+
+func TestUI_PrintUsage(t *testing.T) {
+	var out bytes.Buffer
+	ui := NewUI(&out, &bytes.Buffer{})
+
+	result := &debate.Result{
+		ProUsage:   debate.RoleUsage{Usage: llm.Usage{PromptTokens: 1500, CompletionTokens: 800}, Cost: 0.0012, Priced: true},
+		JudgeUsage: debate.RoleUsage{Usage: llm.Usage{PromptTokens: 4000, CompletionTokens: 900}},
+	}
+	ui.PrintUsage(result)
+
+	output := out.String()
+	for _, want := range []string{"Token Usage", "1500", "$0.0012", "n/a", "TOTAL"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PrintUsage() should contain %q", want)
+		}
+	}
+}
+
+func TestUI_PrintJudgePanel(t *testing.T) {
+	var out bytes.Buffer
+	ui := NewUI(&out, &bytes.Buffer{})
+	cfg := config.New()
+	cfg.Judges = []config.RoleConfig{{Provider: llm.ProviderDeepSeek, Model: "deepseek-chat"}, {Provider: llm.ProviderAnthropic, Model: "claude-sonnet-4-5"}}
+
+	ui.PrintConfig(cfg)
+	output := out.String()
+	for _, want := range []string{"▹ J1", "▹ J2", "claude-sonnet-4-5"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PrintConfig() missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "ADJ") {
+		t.Errorf("PrintConfig() shows the adjudicator without a chief judge:\n%s", output)
+	}
+	out.Reset()
+	cfg.ChiefJudge = true
+	if ui.PrintConfig(cfg); !strings.Contains(out.String(), "ADJ") {
+		t.Errorf("PrintConfig() should show the chief judge as ADJ:\n%s", out.String())
+	}
+
+	result := &debate.Result{
+		Verdicts: []debate.JudgeVerdict{
+			{OneLiner: "first verdict", Model: debate.ModelInfo{Provider: llm.ProviderDeepSeek, Model: "deepseek-chat"}, Usage: debate.RoleUsage{Usage: llm.Usage{PromptTokens: 1234}}},
+			{OneLiner: "second verdict", Model: debate.ModelInfo{Provider: llm.ProviderAnthropic, Model: "claude-sonnet-4-5"}},
+		},
+		Consensus: &debate.Consensus{Method: debate.AggregateMean},
+	}
+	out.Reset()
+	ui.PrintJudgePanel(result)
+	output = out.String()
+	for _, want := range []string{"JUDGE PANEL", "J1", "deepseek/deepseek-chat", "first verdict", "J2", "second verdict", result.Consensus.Summary()} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PrintJudgePanel() missing %q:\n%s", want, output)
+		}
+	}
+
+	// Without a chief judge there is no ADJ usage row
+	out.Reset()
+	ui.PrintUsage(result)
+	if output := out.String(); !strings.Contains(output, "J1") || !strings.Contains(output, "1234") || strings.Contains(output, "ADJ") {
+		t.Errorf("PrintUsage() = %q, want judge rows and no ADJ row", output)
+	}
+
+	out.Reset()
+	ui.PrintJudgePanel(&debate.Result{})
+	if out.Len() != 0 {
+		t.Errorf("PrintJudgePanel() without a panel printed %q", out.String())
+	}
+}
+
+func TestUI_PrintReasoning(t *testing.T) {
+	var out bytes.Buffer
+	ui := NewUI(&out, &bytes.Buffer{})
+
+	ui.PrintReasoning("ADJ", ColorBrightYellow, "")
+	if out.Len() != 0 {
+		t.Errorf("PrintReasoning() with empty reasoning should print nothing, got %q", out.String())
+	}
+
+	ui.PrintReasoning("ADJ", ColorBrightYellow, "first\nsecond")
+	output := out.String()
+	if !strings.Contains(output, "ADJ Reasoning") || !strings.Contains(output, ColorDim+"  │ second") {
+		t.Errorf("PrintReasoning() = %q, want dimmed lines under a header", output)
+	}
+}
+
 func TestUI_Print(t *testing.T) {
 	var out bytes.Buffer
 	ui := NewUI(&out, &bytes.Buffer{})
@@ -276,6 +477,27 @@ func TestUI_PrintThinking(t *testing.T) {
 	}
 }
 
+func TestUI_PrintOllamaModels(t *testing.T) {
+	var out bytes.Buffer
+	ui := NewUI(&out, &bytes.Buffer{})
+
+	ui.PrintOllamaModels([]llm.OllamaModel{{Name: "llama3.1:latest", Size: 4_661_224_676}})
+
+	output := out.String()
+	if !strings.Contains(output, "llama3.1:latest") {
+		t.Error("PrintOllamaModels() should contain the model name")
+	}
+	if !strings.Contains(output, "4.7 GB") {
+		t.Error("PrintOllamaModels() should contain the model size")
+	}
+
+	out.Reset()
+	ui.PrintOllamaModels(nil)
+	if !strings.Contains(out.String(), "ollama pull") {
+		t.Error("PrintOllamaModels() should hint at ollama pull when empty")
+	}
+}
+
 func TestColorConstants(t *testing.T) {
 	// Verify color constants are defined
 	if ColorReset == "" {
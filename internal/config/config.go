@@ -1,7 +1,10 @@
 package config
 
 import (
-	"os"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/hrygo/dialecta/internal/llm"
 )
@@ -12,6 +15,58 @@ type RoleConfig struct {
 	Model       string
 	Temperature float64
 	MaxTokens   int
+	TopP        float64
+	TopK        int
+
+	// Gemini generation settings (see llm.Config)
+	ThinkingBudget int
+	SafetySettings []llm.SafetySetting
+
+	ResponseFormat *llm.ResponseFormat // JSON output; nil for free text
+
+	// MaxContinuations caps the follow-up requests sent when an answer stops
+	// at MaxTokens; with 0 the answer is only marked as truncated
+	MaxContinuations int
+
+	// OpenAI-compatible endpoint overrides (see llm.Config)
+	BaseURL   string
+	APIKeyEnv string
+	Headers   map[string]string
+
+	// Azure OpenAI settings (see llm.Config); BaseURL is the resource endpoint
+	Deployment string
+	APIVersion string
+
+	Command []string // exec provider plugin and arguments (see llm.Config)
+
+	// Credentials supplies API keys besides the environment; shared by the
+	// primary provider and fallbacks
+	Credentials *llm.CredentialSources
+
+	Retry    llm.RetryPolicy // retry policy for transient provider errors
+	Timeouts llm.Timeouts    // connect, total and stream-idle limits; zero waits forever
+	Cache    *llm.Cache      // response cache; nil disables caching
+
+	HTTPClient *http.Client // nil uses a default client (see llm.Config)
+
+	// RateLimits caps each provider account; they apply to the primary
+	// provider and to fallbacks alike (see llm.WithRateLimit)
+	RateLimits map[llm.Provider]llm.RateLimit
+
+	// Fallbacks are tried in order when the primary provider fails with a
+	// non-retryable error or exhausts its retries
+	Fallbacks []Fallback
+}
+
+// Fallback names an alternative provider/model for a role
+type Fallback struct {
+	Provider llm.Provider
+	Model    string // empty uses GetDefaultModel(Provider)
+}
+
+// String returns the "provider/model" form used in logs and reports
+func (f Fallback) String() string {
+	return fmt.Sprintf("%s/%s", f.Provider, f.Model)
 }
 
 // Config holds the application configuration
@@ -19,6 +74,17 @@ type Config struct {
 	ProRole   RoleConfig // 正方配置
 	ConRole   RoleConfig // 反方配置
 	JudgeRole RoleConfig // 裁决方配置
+
+	// Personas turns the debate into a panel: they speak instead of Pro
+	// and Con, all at once, and the Judge weighs every view. Empty debates
+	// Pro against Con.
+	Personas []Persona
+
+	// Judges rule concurrently on the same transcript instead of
+	// JudgeRole, and their verdicts are aggregated. With ChiefJudge set,
+	// JudgeRole then reconciles their verdicts into the final one.
+	Judges     []RoleConfig
+	ChiefJudge bool
 }
 
 // Default role configurations
@@ -28,18 +94,24 @@ var (
 		Model:       "deepseek-chat",
 		Temperature: 0.8,
 		MaxTokens:   4096,
+		Retry:       llm.DefaultRetryPolicy,
+		Timeouts:    llm.DefaultTimeouts,
 	}
 	DefaultConRole = RoleConfig{
 		Provider:    llm.ProviderDashScope,
 		Model:       "qwen-plus",
 		Temperature: 0.8,
 		MaxTokens:   4096,
+		Retry:       llm.DefaultRetryPolicy,
+		Timeouts:    llm.DefaultTimeouts,
 	}
 	DefaultJudgeRole = RoleConfig{
 		Provider:    llm.ProviderGemini,
 		Model:       "gemini-3-pro-preview",
 		Temperature: 0.1,
 		MaxTokens:   8192,
+		Retry:       llm.DefaultRetryPolicy,
+		Timeouts:    llm.DefaultTimeouts,
 	}
 )
 
@@ -52,27 +124,74 @@ func New() *Config {
 	}
 }
 
-// Validate checks if required API keys are set
-func (c *Config) Validate() error {
-	providers := map[llm.Provider]bool{
-		c.ProRole.Provider:   true,
-		c.ConRole.Provider:   true,
-		c.JudgeRole.Provider: true,
+// Roles returns the roles of the debate: Pro and Con, or the personas of a
+// panel, then the Judge and the judge panel
+func (c *Config) Roles() []*RoleConfig {
+	roles := []*RoleConfig{&c.ProRole, &c.ConRole}
+	if len(c.Personas) > 0 {
+		roles = make([]*RoleConfig, 0, len(c.Personas)+1+len(c.Judges))
+		for i := range c.Personas {
+			roles = append(roles, &c.Personas[i].Role)
+		}
 	}
+	roles = append(roles, &c.JudgeRole)
+	for i := range c.Judges {
+		roles = append(roles, &c.Judges[i])
+	}
+	return roles
+}
 
-	for p := range providers {
-		switch p {
-		case llm.ProviderDeepSeek:
-			if os.Getenv("DEEPSEEK_API_KEY") == "" {
-				return ConfigError("DEEPSEEK_API_KEY environment variable is required")
-			}
-		case llm.ProviderGemini:
-			if os.Getenv("GEMINI_API_KEY") == "" && os.Getenv("GOOGLE_API_KEY") == "" {
-				return ConfigError("GEMINI_API_KEY or GOOGLE_API_KEY environment variable is required")
+// UsesJudgeRole reports whether JudgeRole rules: alone, or as the chief
+// judge of a judge panel
+func (c *Config) UsesJudgeRole() bool {
+	return len(c.Judges) == 0 || c.ChiefJudge
+}
+
+// SetCache enables the response cache for every role; nil disables it
+func (c *Config) SetCache(cache *llm.Cache) {
+	for _, role := range c.Roles() {
+		role.Cache = cache
+	}
+}
+
+// SetCredentials sets where every role looks up API keys; nil reads the
+// environment only
+func (c *Config) SetCredentials(sources *llm.CredentialSources) {
+	for _, role := range c.Roles() {
+		role.Credentials = sources
+	}
+}
+
+// SetRateLimit limits provider for every role
+func (c *Config) SetRateLimit(provider llm.Provider, limit llm.RateLimit) {
+	for _, role := range c.Roles() {
+		if role.RateLimits == nil {
+			role.RateLimits = make(map[llm.Provider]llm.RateLimit)
+		}
+		role.RateLimits[provider] = limit
+	}
+}
+
+// Validate checks that every role, and every fallback of a role, uses a
+// registered provider whose API key and settings are in place
+func (c *Config) Validate() error {
+	if err := validatePersonas(c.Personas); err != nil {
+		return err
+	}
+	if err := c.validateJudges(); err != nil {
+		return err
+	}
+	for _, role := range c.Roles() {
+		if role == &c.JudgeRole && !c.UsesJudgeRole() {
+			continue
+		}
+		for _, cfg := range role.Chain() {
+			spec, ok := llm.LookupProvider(string(cfg.Provider))
+			if !ok {
+				return ConfigError(fmt.Sprintf("unsupported provider: %s", cfg.Provider))
 			}
-		case llm.ProviderDashScope:
-			if os.Getenv("DASHSCOPE_API_KEY") == "" {
-				return ConfigError("DASHSCOPE_API_KEY environment variable is required")
+			if _, _, err := spec.Credentials(cfg); err != nil {
+				return ConfigError(err.Error())
 			}
 		}
 	}
@@ -82,25 +201,134 @@ func (c *Config) Validate() error {
 // ToLLMConfig converts RoleConfig to llm.Config
 func (r *RoleConfig) ToLLMConfig() llm.Config {
 	return llm.Config{
-		Provider:    r.Provider,
-		Model:       r.Model,
-		Temperature: r.Temperature,
-		MaxTokens:   r.MaxTokens,
+		Provider:       r.Provider,
+		Model:          r.Model,
+		Temperature:    r.Temperature,
+		MaxTokens:      r.MaxTokens,
+		TopP:           r.TopP,
+		TopK:           r.TopK,
+		ThinkingBudget: r.ThinkingBudget,
+		SafetySettings: r.SafetySettings,
+		ResponseFormat: r.ResponseFormat,
+		BaseURL:        r.BaseURL,
+		APIKeyEnv:      r.APIKeyEnv,
+		Headers:        r.Headers,
+		Deployment:     r.Deployment,
+		APIVersion:     r.APIVersion,
+		Credentials:    r.Credentials,
+		Command:        r.Command,
+		Retry:          r.Retry,
+		Timeouts:       r.Timeouts,
+		Cache:          r.Cache,
+		HTTPClient:     r.HTTPClient,
+		RateLimit:      r.RateLimits[r.Provider],
 	}
 }
 
+// Chain returns the primary llm.Config followed by one per fallback.
+// Fallbacks inherit the role's sampling, generation, retry, timeout and
+// credential settings and the Azure api-version; endpoint overrides and the
+// Azure deployment apply to the primary provider only.
+func (r *RoleConfig) Chain() []llm.Config {
+	chain := []llm.Config{r.ToLLMConfig()}
+	for _, f := range r.Fallbacks {
+		model := f.Model
+		if model == "" {
+			model = GetDefaultModel(f.Provider)
+		}
+		chain = append(chain, llm.Config{
+			Provider:       f.Provider,
+			Model:          model,
+			Temperature:    r.Temperature,
+			MaxTokens:      r.MaxTokens,
+			TopP:           r.TopP,
+			TopK:           r.TopK,
+			ThinkingBudget: r.ThinkingBudget,
+			SafetySettings: r.SafetySettings,
+			ResponseFormat: r.ResponseFormat,
+			APIVersion:     r.APIVersion,
+			Credentials:    r.Credentials,
+			Retry:          r.Retry,
+			Timeouts:       r.Timeouts,
+			Cache:          r.Cache,
+			HTTPClient:     r.HTTPClient,
+			RateLimit:      r.RateLimits[f.Provider],
+		})
+	}
+	return chain
+}
+
+// ParseFallbacks parses a comma-separated "provider[:model]" list,
+// e.g. "deepseek,openai:gpt-4o-mini"
+func ParseFallbacks(s string) ([]Fallback, error) {
+	return parseProviderModels(s, "fallback")
+}
+
+// parseProviderModels parses a comma-separated "provider[:model]" list;
+// kind names an entry in errors
+func parseProviderModels(s, kind string) ([]Fallback, error) {
+	var entries []Fallback
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, model, _ := strings.Cut(item, ":")
+		provider, err := llm.ParseProvider(strings.TrimSpace(name))
+		if err != nil {
+			return nil, ConfigError(fmt.Sprintf("invalid %s %q: %v", kind, item, err))
+		}
+		model = strings.TrimSpace(model)
+		if model == "" {
+			model = GetDefaultModel(provider)
+		}
+		entries = append(entries, Fallback{Provider: provider, Model: model})
+	}
+	return entries, nil
+}
+
+// ParseRateLimits parses a comma-separated list of
+// "provider:key=value[:key=value...]" limits with keys rpm, tpm and inflight,
+// e.g. "dashscope:rpm=60:tpm=100000:inflight=2,deepseek:rpm=300"
+func ParseRateLimits(s string) (map[llm.Provider]llm.RateLimit, error) {
+	limits := make(map[llm.Provider]llm.RateLimit)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		fields := strings.Split(item, ":")
+		provider, err := llm.ParseProvider(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, ConfigError(fmt.Sprintf("invalid rate limit %q: %v", item, err))
+		}
+		limit := limits[provider]
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return nil, ConfigError(fmt.Sprintf("invalid rate limit %q: bad value for %s", item, key))
+			}
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "rpm":
+				limit.RequestsPerMinute = n
+			case "tpm":
+				limit.TokensPerMinute = n
+			case "inflight", "in-flight", "concurrency":
+				limit.MaxInFlight = n
+			default:
+				return nil, ConfigError(fmt.Sprintf("invalid rate limit %q: unknown key %q (want rpm, tpm, inflight)", item, key))
+			}
+		}
+		limits[provider] = limit
+	}
+	return limits, nil
+}
+
 // GetDefaultModel returns the default model for a given provider
 func GetDefaultModel(provider llm.Provider) string {
-	switch provider {
-	case llm.ProviderDeepSeek:
-		return "deepseek-chat"
-	case llm.ProviderGemini:
-		return "gemini-3-pro-preview"
-	case llm.ProviderDashScope:
-		return "qwen-plus"
-	default:
-		return ""
-	}
+	spec, _ := llm.LookupProvider(string(provider))
+	return spec.DefaultModel
 }
 
 // Custom errors
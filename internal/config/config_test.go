@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hrygo/dialecta/internal/llm"
@@ -69,6 +70,28 @@ func TestRoleConfig_ToLLMConfig(t *testing.T) {
 	}
 }
 
+func TestRoleConfig_ToLLMConfig_OpenAIEndpoint(t *testing.T) {
+	role := RoleConfig{
+		Provider:  llm.ProviderOpenAI,
+		Model:     "meta-llama/llama-3-70b",
+		BaseURL:   "https://openrouter.ai/api/v1",
+		APIKeyEnv: "OPENROUTER_API_KEY",
+		Headers:   map[string]string{"HTTP-Referer": "https://github.com/hrygo/dialecta"},
+	}
+
+	cfg := role.ToLLMConfig()
+
+	if cfg.BaseURL != role.BaseURL {
+		t.Errorf("BaseURL = %v, want %v", cfg.BaseURL, role.BaseURL)
+	}
+	if cfg.APIKeyEnv != role.APIKeyEnv {
+		t.Errorf("APIKeyEnv = %v, want %v", cfg.APIKeyEnv, role.APIKeyEnv)
+	}
+	if cfg.Headers["HTTP-Referer"] != role.Headers["HTTP-Referer"] {
+		t.Errorf("Headers = %v, want %v", cfg.Headers, role.Headers)
+	}
+}
+
 func TestConfigError_Error(t *testing.T) {
 	err := ConfigError("test error message")
 	if err.Error() != "test error message" {
@@ -82,11 +105,15 @@ func TestConfig_Validate(t *testing.T) {
 	origGemini := os.Getenv("GEMINI_API_KEY")
 	origGoogle := os.Getenv("GOOGLE_API_KEY")
 	origDashScope := os.Getenv("DASHSCOPE_API_KEY")
+	origOpenRouter := os.Getenv("OPENROUTER_API_KEY")
+	origAnthropic := os.Getenv("ANTHROPIC_API_KEY")
 	defer func() {
+		os.Setenv("ANTHROPIC_API_KEY", origAnthropic)
 		os.Setenv("DEEPSEEK_API_KEY", origDeepSeek)
 		os.Setenv("GEMINI_API_KEY", origGemini)
 		os.Setenv("GOOGLE_API_KEY", origGoogle)
 		os.Setenv("DASHSCOPE_API_KEY", origDashScope)
+		os.Setenv("OPENROUTER_API_KEY", origOpenRouter)
 	}()
 
 	tests := []struct {
@@ -151,6 +178,113 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr:     true,
 			errContains: "DASHSCOPE_API_KEY",
 		},
+		{
+			name: "openai provider against local server - no key needed",
+			setup: func() {
+				os.Setenv("DEEPSEEK_API_KEY", "test-key")
+			},
+			cfg: &Config{
+				ProRole:   RoleConfig{Provider: llm.ProviderOpenAI, BaseURL: "http://localhost:8000/v1"},
+				ConRole:   RoleConfig{Provider: llm.ProviderDeepSeek},
+				JudgeRole: RoleConfig{Provider: llm.ProviderDeepSeek},
+			},
+			wantErr: false,
+		},
+		{
+			name: "openai provider with custom key env - missing key",
+			setup: func() {
+				os.Setenv("DEEPSEEK_API_KEY", "test-key")
+			},
+			cfg: &Config{
+				ProRole:   RoleConfig{Provider: llm.ProviderDeepSeek},
+				ConRole:   RoleConfig{Provider: llm.ProviderOpenAI, BaseURL: "https://openrouter.ai/api/v1", APIKeyEnv: "OPENROUTER_API_KEY"},
+				JudgeRole: RoleConfig{Provider: llm.ProviderDeepSeek},
+			},
+			wantErr:     true,
+			errContains: "OPENROUTER_API_KEY",
+		},
+		{
+			name: "Anthropic judge - missing key",
+			setup: func() {
+				os.Setenv("DEEPSEEK_API_KEY", "test-key")
+			},
+			cfg: &Config{
+				ProRole:   RoleConfig{Provider: llm.ProviderDeepSeek},
+				ConRole:   RoleConfig{Provider: llm.ProviderDeepSeek},
+				JudgeRole: RoleConfig{Provider: llm.ProviderAnthropic},
+			},
+			wantErr:     true,
+			errContains: "ANTHROPIC_API_KEY",
+		},
+		{
+			name: "fallback - missing key",
+			setup: func() {
+				os.Setenv("DEEPSEEK_API_KEY", "test-key")
+				os.Unsetenv("ANTHROPIC_API_KEY")
+			},
+			cfg: &Config{
+				ProRole:   RoleConfig{Provider: llm.ProviderDeepSeek, Fallbacks: []Fallback{{Provider: llm.ProviderAnthropic}}},
+				ConRole:   RoleConfig{Provider: llm.ProviderDeepSeek},
+				JudgeRole: RoleConfig{Provider: llm.ProviderDeepSeek},
+			},
+			wantErr:     true,
+			errContains: "ANTHROPIC_API_KEY",
+		},
+		{
+			name:  "fallback - unregistered provider",
+			setup: func() {},
+			cfg: &Config{
+				ProRole:   RoleConfig{Provider: llm.ProviderMock},
+				ConRole:   RoleConfig{Provider: llm.ProviderMock},
+				JudgeRole: RoleConfig{Provider: llm.ProviderMock, Fallbacks: []Fallback{{Provider: llm.ProviderMock}, {Provider: "nope"}}},
+			},
+			wantErr:     true,
+			errContains: "unsupported provider: nope",
+		},
+		{
+			name:  "all Ollama - no keys needed",
+			setup: func() {},
+			cfg: &Config{
+				ProRole:   RoleConfig{Provider: llm.ProviderOllama},
+				ConRole:   RoleConfig{Provider: llm.ProviderOllama},
+				JudgeRole: RoleConfig{Provider: llm.ProviderOllama},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "all mock - no keys needed",
+			setup: func() {},
+			cfg: &Config{
+				ProRole:   RoleConfig{Provider: llm.ProviderMock},
+				ConRole:   RoleConfig{Provider: llm.ProviderMock},
+				JudgeRole: RoleConfig{Provider: llm.ProviderMock},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "unregistered provider",
+			setup: func() {},
+			cfg: &Config{
+				ProRole:   RoleConfig{Provider: "nope"},
+				ConRole:   RoleConfig{Provider: llm.ProviderMock},
+				JudgeRole: RoleConfig{Provider: llm.ProviderMock},
+			},
+			wantErr:     true,
+			errContains: "unsupported provider: nope",
+		},
+		{
+			name: "mock - invalid error injection",
+			setup: func() {
+				os.Setenv("DIALECTA_MOCK_ERROR", "bogus")
+			},
+			cfg: &Config{
+				ProRole:   RoleConfig{Provider: llm.ProviderMock},
+				ConRole:   RoleConfig{Provider: llm.ProviderMock},
+				JudgeRole: RoleConfig{Provider: llm.ProviderMock},
+			},
+			wantErr:     true,
+			errContains: "DIALECTA_MOCK_ERROR",
+		},
 		{
 			name: "only DeepSeek provider - only needs DeepSeek key",
 			setup: func() {
@@ -175,6 +309,9 @@ func TestConfig_Validate(t *testing.T) {
 			os.Unsetenv("GEMINI_API_KEY")
 			os.Unsetenv("GOOGLE_API_KEY")
 			os.Unsetenv("DASHSCOPE_API_KEY")
+			os.Unsetenv("OPENROUTER_API_KEY")
+			os.Unsetenv("ANTHROPIC_API_KEY")
+			os.Unsetenv("DIALECTA_MOCK_ERROR")
 
 			tt.setup()
 
@@ -207,3 +344,179 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGetDefaultModel(t *testing.T) {
+	tests := []struct {
+		provider llm.Provider
+		want     string
+	}{
+		{llm.ProviderDeepSeek, "deepseek-chat"},
+		{llm.ProviderGemini, "gemini-3-pro-preview"},
+		{llm.ProviderDashScope, "qwen-plus"},
+		{llm.ProviderOpenAI, "gpt-4o-mini"},
+		{llm.ProviderAnthropic, "claude-sonnet-4-5"},
+		{llm.ProviderOllama, "llama3.1"},
+		{llm.ProviderMock, "mock"},
+		{"unknown", ""},
+	}
+
+	for _, tt := range tests {
+		if got := GetDefaultModel(tt.provider); got != tt.want {
+			t.Errorf("GetDefaultModel(%q) = %q, want %q", tt.provider, got, tt.want)
+		}
+	}
+}
+
+func TestRoleConfig_RetryPolicy(t *testing.T) {
+	cfg := New()
+	if !cfg.ProRole.Retry.Enabled() || !cfg.JudgeRole.Retry.Enabled() {
+		t.Error("default roles should retry transient errors")
+	}
+
+	role := RoleConfig{Provider: llm.ProviderDeepSeek, Retry: llm.RetryPolicy{MaxAttempts: 5}}
+	if got := role.ToLLMConfig().Retry.MaxAttempts; got != 5 {
+		t.Errorf("Retry.MaxAttempts = %d, want 5", got)
+	}
+}
+
+func TestRoleConfig_Chain(t *testing.T) {
+	role := RoleConfig{
+		Provider:    llm.ProviderGemini,
+		Model:       "gemini-3-pro-preview",
+		Temperature: 0.1,
+		MaxTokens:   8192,
+		BaseURL:     "http://primary-only",
+		Retry:       llm.DefaultRetryPolicy,
+		Fallbacks: []Fallback{
+			{Provider: llm.ProviderDeepSeek},
+			{Provider: llm.ProviderOpenAI, Model: "gpt-4o"},
+		},
+	}
+
+	chain := role.Chain()
+	if len(chain) != 3 {
+		t.Fatalf("len(Chain()) = %d, want 3", len(chain))
+	}
+	if chain[0].Provider != llm.ProviderGemini || chain[0].BaseURL != "http://primary-only" {
+		t.Errorf("Chain()[0] = %+v, want primary config", chain[0])
+	}
+	if chain[1].Model != "deepseek-chat" {
+		t.Errorf("Chain()[1].Model = %q, want provider default", chain[1].Model)
+	}
+	if chain[2].Model != "gpt-4o" || chain[2].BaseURL != "" {
+		t.Errorf("Chain()[2] = %+v, want gpt-4o without endpoint override", chain[2])
+	}
+	for i, cfg := range chain {
+		if cfg.Temperature != 0.1 || cfg.MaxTokens != 8192 || cfg.Retry != llm.DefaultRetryPolicy {
+			t.Errorf("Chain()[%d] should inherit role sampling and retry settings, got %+v", i, cfg)
+		}
+	}
+}
+
+func TestParseFallbacks(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []Fallback
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "deepseek", want: []Fallback{{llm.ProviderDeepSeek, "deepseek-chat"}}},
+		{
+			input: "claude:claude-opus-4-1, openai",
+			want: []Fallback{
+				{llm.ProviderAnthropic, "claude-opus-4-1"},
+				{llm.ProviderOpenAI, "gpt-4o-mini"},
+			},
+		},
+		{input: "deepseek,unknown", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFallbacks(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFallbacks(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseFallbacks(%q) = %v, want %v", tt.input, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseFallbacks(%q)[%d] = %v, want %v", tt.input, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseRateLimits(t *testing.T) {
+	got, err := ParseRateLimits("qwen:rpm=60:tpm=100000:inflight=2, deepseek:rpm=300")
+	if err != nil {
+		t.Fatalf("ParseRateLimits() error = %v", err)
+	}
+	want := map[llm.Provider]llm.RateLimit{
+		llm.ProviderDashScope: {RequestsPerMinute: 60, TokensPerMinute: 100000, MaxInFlight: 2},
+		llm.ProviderDeepSeek:  {RequestsPerMinute: 300},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseRateLimits() = %v, want %v", got, want)
+	}
+	for p, limit := range want {
+		if got[p] != limit {
+			t.Errorf("ParseRateLimits()[%s] = %+v, want %+v", p, got[p], limit)
+		}
+	}
+
+	if got, err := ParseRateLimits(""); err != nil || len(got) != 0 {
+		t.Errorf("ParseRateLimits(\"\") = %v, %v, want empty", got, err)
+	}
+	for _, input := range []string{"unknown:rpm=1", "deepseek:rpm=fast", "deepseek:qps=1", "deepseek:rpm=-1"} {
+		if _, err := ParseRateLimits(input); err == nil {
+			t.Errorf("ParseRateLimits(%q) should fail", input)
+		}
+	}
+}
+
+func TestConfig_SetRateLimit(t *testing.T) {
+	cfg := New()
+	cfg.JudgeRole.Fallbacks = []Fallback{{Provider: llm.ProviderDashScope}}
+	limit := llm.RateLimit{RequestsPerMinute: 60, MaxInFlight: 1}
+	cfg.SetRateLimit(llm.ProviderDashScope, limit)
+
+	if got := cfg.ConRole.ToLLMConfig().RateLimit; got != limit {
+		t.Errorf("ConRole RateLimit = %+v, want %+v", got, limit)
+	}
+	if got := cfg.ProRole.ToLLMConfig().RateLimit; !got.IsZero() {
+		t.Errorf("ProRole (deepseek) RateLimit = %+v, want none", got)
+	}
+	chain := cfg.JudgeRole.Chain()
+	if !chain[0].RateLimit.IsZero() || chain[1].RateLimit != limit {
+		t.Errorf("JudgeRole chain limits = %+v / %+v, want none then %+v", chain[0].RateLimit, chain[1].RateLimit, limit)
+	}
+}
+
+func TestConfig_SetCredentials(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	t.Setenv("DASHSCOPE_API_KEY", "")
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte("[team]\ndeepseek = ds-1, ds-2\ndashscope = qw\ngemini = gm\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := New()
+	cfg.JudgeRole.Fallbacks = []Fallback{{Provider: llm.ProviderDeepSeek}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("Validate() = nil without any key")
+	}
+
+	sources := llm.NewCredentialSources(path, nil, "team")
+	cfg.SetCredentials(sources)
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want the profile's keys accepted", err)
+	}
+	if chain := cfg.JudgeRole.Chain(); chain[0].Credentials != sources || chain[1].Credentials != sources {
+		t.Error("Chain() does not pass the credential sources to every entry")
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+// ParseJudges parses a judge panel from a comma-separated "provider[:model]"
+// list, e.g. "deepseek,anthropic,gemini:gemini-2.5-pro". Each judge starts
+// from DefaultJudgeRole.
+func ParseJudges(s string) ([]RoleConfig, error) {
+	entries, err := parseProviderModels(s, "judge")
+	if err != nil {
+		return nil, err
+	}
+	var judges []RoleConfig
+	for _, entry := range entries {
+		judge := DefaultJudgeRole
+		judge.Provider, judge.Model = entry.Provider, entry.Model
+		judges = append(judges, judge)
+	}
+	return judges, nil
+}
+
+// validateJudges checks that a judge panel has at least two judges, and
+// that a chief judge has a panel to reconcile
+func (c *Config) validateJudges() error {
+	switch {
+	case len(c.Judges) == 1:
+		return ConfigError("a judge panel needs at least two judges")
+	case c.ChiefJudge && len(c.Judges) == 0:
+		return ConfigError("a chief judge needs a judge panel")
+	}
+	return nil
+}
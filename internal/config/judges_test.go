@@ -0,0 +1,77 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+func TestParseJudges(t *testing.T) {
+	judges, err := ParseJudges("deepseek, openai:gpt-4o-mini,")
+	if err != nil {
+		t.Fatalf("ParseJudges() error = %v", err)
+	}
+	if len(judges) != 2 {
+		t.Fatalf("ParseJudges() = %d judges, want 2", len(judges))
+	}
+	if judges[0].Provider != llm.ProviderDeepSeek || judges[0].Model != GetDefaultModel(llm.ProviderDeepSeek) {
+		t.Errorf("judges[0] = %s/%s, want deepseek's default model", judges[0].Provider, judges[0].Model)
+	}
+	if judges[1].Model != "gpt-4o-mini" || judges[1].Temperature != DefaultJudgeRole.Temperature || judges[1].MaxTokens != DefaultJudgeRole.MaxTokens {
+		t.Errorf("judges[1] = %+v, want gpt-4o-mini with the Judge's settings", judges[1])
+	}
+
+	if judges, err := ParseJudges(""); judges != nil || err != nil {
+		t.Errorf("ParseJudges(\"\") = %v, %v, want no panel", judges, err)
+	}
+	if _, err := ParseJudges("deepseek,nope"); err == nil || !strings.Contains(err.Error(), `invalid judge "nope"`) {
+		t.Errorf("ParseJudges() error = %v, want the unknown provider named", err)
+	}
+}
+
+func TestConfig_Validate_Judges(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "ds")
+	t.Setenv("DASHSCOPE_API_KEY", "qw")
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+	judges, _ := ParseJudges("deepseek,dashscope")
+
+	cfg := New()
+	cfg.Judges = judges[:1]
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "at least two judges") {
+		t.Errorf("Validate() error = %v, want a one-judge panel rejected", err)
+	}
+
+	// Without a chief judge, JudgeRole (gemini) needs no key
+	cfg.Judges = judges
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	cfg.ChiefJudge = true
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "GEMINI_API_KEY") {
+		t.Errorf("Validate() error = %v, want the chief judge's key required", err)
+	}
+
+	cfg.Judges = nil
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "chief judge needs a judge panel") {
+		t.Errorf("Validate() error = %v, want a chief judge without a panel rejected", err)
+	}
+}
+
+func TestConfig_Roles_Judges(t *testing.T) {
+	cfg := New()
+	cfg.Judges, _ = ParseJudges("deepseek,dashscope")
+	roles := cfg.Roles()
+	if len(roles) != 5 || roles[2] != &cfg.JudgeRole || roles[3] != &cfg.Judges[0] || roles[4] != &cfg.Judges[1] {
+		t.Errorf("Roles() = %v, want Pro, Con, Judge, then the judges", roles)
+	}
+	if cfg.UsesJudgeRole() {
+		t.Error("UsesJudgeRole() = true for a panel without a chief judge")
+	}
+
+	cfg.SetCache(llm.NewCache(t.TempDir()))
+	if cfg.Judges[1].Cache == nil {
+		t.Error("SetCache() does not reach the judges")
+	}
+}
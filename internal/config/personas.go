@@ -0,0 +1,102 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+// Persona is a named debater of a panel debate, such as CFO, Security,
+// Customer or Ops, with its own model settings and system prompt
+type Persona struct {
+	Name   string     // shown in the UI, the report and the Judge's input
+	Prompt string     // who the persona is and what it cares about
+	Role   RoleConfig // provider, model, temperature, ...
+}
+
+// personaFile is one entry of a panel file (see LoadPersonas)
+type personaFile struct {
+	Name        string   `json:"name"`
+	Prompt      string   `json:"prompt"`
+	Provider    string   `json:"provider"`
+	Model       string   `json:"model"`
+	Temperature *float64 `json:"temperature"`
+	MaxTokens   int      `json:"max_tokens"`
+	BaseURL     string   `json:"base_url"`
+	Fallback    string   `json:"fallback"`
+}
+
+// LoadPersonas reads a panel file: a JSON array of personas, e.g.
+//
+//	[
+//	  {"name": "CFO", "provider": "deepseek", "temperature": 0.3,
+//	   "prompt": "你是公司的首席财务官，关注成本、现金流和投资回报。"},
+//	  {"name": "Security", "provider": "anthropic", "model": "claude-sonnet-4-5",
+//	   "prompt": "你是安全负责人，关注数据安全、合规与攻击面。"}
+//	]
+//
+// Settings left out come from DefaultProRole; without a model the
+// provider's default model is used. fallback is a ParseFallbacks list.
+func LoadPersonas(path string) ([]Persona, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("panel file: %w", err)
+	}
+	var entries []personaFile
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("panel file %s: %w", path, err)
+	}
+
+	personas := make([]Persona, len(entries))
+	for i, entry := range entries {
+		role := DefaultProRole
+		if entry.Provider != "" {
+			provider, err := llm.ParseProvider(entry.Provider)
+			if err != nil {
+				return nil, fmt.Errorf("panel file %s: persona %q: %w", path, entry.Name, err)
+			}
+			role.Provider = provider
+			role.Model = GetDefaultModel(provider)
+		}
+		if entry.Model != "" {
+			role.Model = entry.Model
+		}
+		if entry.Temperature != nil {
+			role.Temperature = *entry.Temperature
+		}
+		if entry.MaxTokens > 0 {
+			role.MaxTokens = entry.MaxTokens
+		}
+		role.BaseURL = entry.BaseURL
+		if role.Fallbacks, err = ParseFallbacks(entry.Fallback); err != nil {
+			return nil, fmt.Errorf("panel file %s: persona %q: %w", path, entry.Name, err)
+		}
+		personas[i] = Persona{Name: strings.TrimSpace(entry.Name), Prompt: entry.Prompt, Role: role}
+	}
+	return personas, nil
+}
+
+// validatePersonas checks that a panel has at least two personas with
+// distinct names. "pro" and "con" are reserved: they name the sides of a
+// Pro/Con debate.
+func validatePersonas(personas []Persona) error {
+	if len(personas) == 1 {
+		return ConfigError("a panel needs at least two personas")
+	}
+	seen := make(map[string]bool)
+	for i, p := range personas {
+		switch {
+		case p.Name == "":
+			return ConfigError(fmt.Sprintf("persona %d has no name", i+1))
+		case seen[p.Name]:
+			return ConfigError(fmt.Sprintf("duplicate persona %q", p.Name))
+		case strings.EqualFold(p.Name, "pro"), strings.EqualFold(p.Name, "con"):
+			return ConfigError(fmt.Sprintf("persona name %q is reserved", p.Name))
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+func writePanel(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "panel.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPersonas(t *testing.T) {
+	path := writePanel(t, `[
+		{"name": " CFO ", "prompt": "cares about cost", "temperature": 0},
+		{"name": "Security", "prompt": "cares about risk", "provider": "openai", "max_tokens": 2048, "fallback": "deepseek"},
+		{"name": "Ops", "provider": "anthropic", "model": "claude-sonnet-4-5", "base_url": "http://localhost:9000"}
+	]`)
+
+	personas, err := LoadPersonas(path)
+	if err != nil {
+		t.Fatalf("LoadPersonas() error = %v", err)
+	}
+	if len(personas) != 3 {
+		t.Fatalf("LoadPersonas() = %d personas, want 3", len(personas))
+	}
+
+	cfo := personas[0]
+	if cfo.Name != "CFO" || cfo.Prompt != "cares about cost" {
+		t.Errorf("personas[0] = %q / %q, want the trimmed name and the prompt", cfo.Name, cfo.Prompt)
+	}
+	if cfo.Role.Provider != DefaultProRole.Provider || cfo.Role.Model != DefaultProRole.Model || cfo.Role.Temperature != 0 {
+		t.Errorf("personas[0].Role = %+v, want DefaultProRole with temperature 0", cfo.Role)
+	}
+
+	security := personas[1].Role
+	if security.Provider != llm.ProviderOpenAI || security.Model != GetDefaultModel(llm.ProviderOpenAI) || security.MaxTokens != 2048 {
+		t.Errorf("personas[1].Role = %+v, want openai's default model and 2048 max tokens", security)
+	}
+	if len(security.Fallbacks) != 1 || security.Fallbacks[0].Provider != llm.ProviderDeepSeek {
+		t.Errorf("personas[1] fallbacks = %+v, want deepseek", security.Fallbacks)
+	}
+
+	if ops := personas[2].Role; ops.Model != "claude-sonnet-4-5" || ops.BaseURL != "http://localhost:9000" || ops.Temperature != DefaultProRole.Temperature {
+		t.Errorf("personas[2].Role = %+v, want the given model and base URL", ops)
+	}
+}
+
+func TestLoadPersonas_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		content string
+		want    string
+	}{
+		"invalid json":     {`{"name": "CFO"}`, "panel file"},
+		"unknown provider": {`[{"name": "CFO", "provider": "nope"}]`, `persona "CFO"`},
+		"bad fallback":     {`[{"name": "CFO", "fallback": "nope"}]`, `persona "CFO"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadPersonas(writePanel(t, tc.content)); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("LoadPersonas() error = %v, want one mentioning %q", err, tc.want)
+			}
+		})
+	}
+	if _, err := LoadPersonas(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadPersonas() = nil error for a missing file")
+	}
+}
+
+func TestConfig_Roles(t *testing.T) {
+	cfg := New()
+	if roles := cfg.Roles(); len(roles) != 3 || roles[0] != &cfg.ProRole || roles[2] != &cfg.JudgeRole {
+		t.Errorf("Roles() = %v, want Pro, Con and Judge", roles)
+	}
+
+	cfg.Personas = []Persona{{Name: "CFO", Role: DefaultProRole}, {Name: "Ops", Role: DefaultConRole}}
+	roles := cfg.Roles()
+	if len(roles) != 3 || roles[0] != &cfg.Personas[0].Role || roles[1] != &cfg.Personas[1].Role || roles[2] != &cfg.JudgeRole {
+		t.Errorf("Roles() = %v, want the personas then Judge", roles)
+	}
+
+	limit := llm.RateLimit{RequestsPerMinute: 30}
+	cfg.SetRateLimit(llm.ProviderDashScope, limit)
+	if got := cfg.Personas[1].Role.ToLLMConfig().RateLimit; got != limit {
+		t.Errorf("persona RateLimit = %+v, want %+v", got, limit)
+	}
+}
+
+func TestConfig_Validate_Personas(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "ds")
+	t.Setenv("GEMINI_API_KEY", "gm")
+	t.Setenv("DASHSCOPE_API_KEY", "")
+	for name, tc := range map[string]struct {
+		personas []Persona
+		want     string
+	}{
+		"one persona": {[]Persona{{Name: "CFO", Role: DefaultProRole}}, "at least two"},
+		"no name":     {[]Persona{{Name: "CFO", Role: DefaultProRole}, {Role: DefaultProRole}}, "persona 2 has no name"},
+		"duplicate":   {[]Persona{{Name: "CFO", Role: DefaultProRole}, {Name: "CFO", Role: DefaultProRole}}, `duplicate persona "CFO"`},
+		"reserved":    {[]Persona{{Name: "CFO", Role: DefaultProRole}, {Name: "Con", Role: DefaultProRole}}, `persona name "Con" is reserved`},
+		"missing key": {[]Persona{{Name: "CFO", Role: DefaultProRole}, {Name: "Ops", Role: DefaultConRole}}, "DASHSCOPE_API_KEY"},
+		"valid":       {[]Persona{{Name: "CFO", Role: DefaultProRole}, {Name: "Ops", Role: DefaultProRole}}, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := New()
+			cfg.Personas = tc.personas
+			err := cfg.Validate()
+			if tc.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Validate() error = %v, want one mentioning %q", err, tc.want)
+			}
+		})
+	}
+}
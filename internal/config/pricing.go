@@ -0,0 +1,60 @@
+package config
+
+import "github.com/hrygo/dialecta/internal/llm"
+
+// Price is a model's list price in USD per million tokens
+type Price struct {
+	Input       float64
+	Output      float64
+	CachedInput float64 // zero means cached tokens are billed at Input
+}
+
+// Cost returns the USD cost of usage at this price
+func (p Price) Cost(u llm.Usage) float64 {
+	cachedRate := p.CachedInput
+	if cachedRate == 0 {
+		cachedRate = p.Input
+	}
+	uncached := u.PromptTokens - u.CachedTokens
+	return (float64(uncached)*p.Input +
+		float64(u.CachedTokens)*cachedRate +
+		float64(u.CompletionTokens)*p.Output) / 1e6
+}
+
+// Prices holds list prices for known models. Entries can be added or
+// overridden at startup; unknown models are reported without a cost.
+var Prices = map[string]Price{
+	// DeepSeek
+	"deepseek-chat":     {Input: 0.28, Output: 0.42, CachedInput: 0.028},
+	"deepseek-reasoner": {Input: 0.28, Output: 0.42, CachedInput: 0.028},
+
+	// Gemini
+	"gemini-3-pro-preview": {Input: 2.00, Output: 12.00, CachedInput: 0.20},
+	"gemini-2.5-pro":       {Input: 1.25, Output: 10.00, CachedInput: 0.125},
+	"gemini-2.5-flash":     {Input: 0.30, Output: 2.50, CachedInput: 0.03},
+
+	// DashScope (international list prices)
+	"qwen-plus":  {Input: 0.40, Output: 1.20},
+	"qwen-max":   {Input: 1.60, Output: 6.40},
+	"qwen-turbo": {Input: 0.05, Output: 0.20},
+
+	// OpenAI
+	"gpt-4o":      {Input: 2.50, Output: 10.00, CachedInput: 1.25},
+	"gpt-4o-mini": {Input: 0.15, Output: 0.60, CachedInput: 0.075},
+
+	// Anthropic
+	"claude-sonnet-4-5": {Input: 3.00, Output: 15.00, CachedInput: 0.30},
+	"claude-opus-4-1":   {Input: 15.00, Output: 75.00, CachedInput: 1.50},
+	"claude-haiku-4-5":  {Input: 1.00, Output: 5.00, CachedInput: 0.10},
+}
+
+// LookupPrice returns the price for a provider/model. Providers registered
+// as free (local Ollama, mock) cost nothing; other models must be listed in
+// Prices.
+func LookupPrice(provider llm.Provider, model string) (Price, bool) {
+	if spec, ok := llm.LookupProvider(string(provider)); ok && spec.Free {
+		return Price{}, true
+	}
+	p, ok := Prices[model]
+	return p, ok
+}
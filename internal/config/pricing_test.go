@@ -0,0 +1,44 @@
+package config
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+func TestPrice_Cost(t *testing.T) {
+	price := Price{Input: 2.00, Output: 12.00, CachedInput: 0.20}
+	usage := llm.Usage{PromptTokens: 1_000_000, CompletionTokens: 500_000, CachedTokens: 500_000}
+
+	// 0.5M uncached * 2 + 0.5M cached * 0.2 + 0.5M out * 12
+	want := 1.0 + 0.1 + 6.0
+	if got := price.Cost(usage); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost() = %v, want %v", got, want)
+	}
+
+	// Without a cached rate, cached tokens are billed at the input rate
+	price.CachedInput = 0
+	want = 2.0 + 6.0
+	if got := price.Cost(usage); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost() without cached rate = %v, want %v", got, want)
+	}
+}
+
+func TestLookupPrice(t *testing.T) {
+	for _, role := range []RoleConfig{DefaultProRole, DefaultConRole, DefaultJudgeRole} {
+		if _, ok := LookupPrice(role.Provider, role.Model); !ok {
+			t.Errorf("LookupPrice(%s, %s) missing; default models should be priced", role.Provider, role.Model)
+		}
+	}
+
+	if p, ok := LookupPrice(llm.ProviderOllama, "anything"); !ok || p != (Price{}) {
+		t.Errorf("LookupPrice(ollama) = %+v, %v; want free", p, ok)
+	}
+	if p, ok := LookupPrice(llm.ProviderMock, "mock"); !ok || p != (Price{}) {
+		t.Errorf("LookupPrice(mock) = %+v, %v; want free", p, ok)
+	}
+	if _, ok := LookupPrice(llm.ProviderOpenAI, "my-finetune"); ok {
+		t.Error("LookupPrice() should not price unknown models")
+	}
+}
@@ -0,0 +1,132 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+// ToolHandler runs a tool with the JSON arguments chosen by the model and
+// returns the result shown to it
+type ToolHandler func(ctx context.Context, arguments string) (string, error)
+
+// Toolbox holds the tools debaters may call, with their Go handlers
+type Toolbox struct {
+	tools    []llm.Tool
+	handlers map[string]ToolHandler
+}
+
+// NewToolbox creates an empty toolbox
+func NewToolbox() *Toolbox {
+	return &Toolbox{handlers: make(map[string]ToolHandler)}
+}
+
+// Register adds a tool; registering a name again replaces its handler
+func (b *Toolbox) Register(tool llm.Tool, handler ToolHandler) {
+	if _, ok := b.handlers[tool.Name]; !ok {
+		b.tools = append(b.tools, tool)
+	} else {
+		for i := range b.tools {
+			if b.tools[i].Name == tool.Name {
+				b.tools[i] = tool
+			}
+		}
+	}
+	b.handlers[tool.Name] = handler
+}
+
+// Tools returns the registered tool definitions in registration order
+func (b *Toolbox) Tools() []llm.Tool {
+	return b.tools
+}
+
+// Len returns the number of registered tools
+func (b *Toolbox) Len() int {
+	return len(b.tools)
+}
+
+// call runs the handler for call. Unknown tools and handler failures are
+// reported to the model as the result, so it can correct itself.
+func (b *Toolbox) call(ctx context.Context, call llm.ToolCall) ToolCallRecord {
+	record := ToolCallRecord{Name: call.Name, Arguments: call.Arguments}
+	handler, ok := b.handlers[call.Name]
+	if !ok {
+		record.Error = fmt.Sprintf("unknown tool %q", call.Name)
+		return record
+	}
+	result, err := handler(ctx, call.Arguments)
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+	record.Result = result
+	return record
+}
+
+// ToolCallRecord is one tool call made during a debate, kept for the report
+type ToolCallRecord struct {
+	Name      string
+	Arguments string // JSON object from the model
+	Result    string
+	Error     string // set instead of Result when the call failed
+}
+
+// content is the text returned to the model
+func (r ToolCallRecord) content() string {
+	if r.Error != "" {
+		return "error: " + r.Error
+	}
+	return r.Result
+}
+
+// DefaultMaxToolSteps bounds the model round trips of one agent run
+const DefaultMaxToolSteps = 8
+
+// RunAgent sends messages with the toolbox's tools and runs every requested
+// tool, feeding the results back, until the model answers without tool
+// calls. It returns that answer and the tool calls made. maxSteps bounds
+// the round trips (DefaultMaxToolSteps when <= 0).
+func RunAgent(ctx context.Context, client llm.Client, messages []llm.Message, toolbox *Toolbox, maxSteps int) (string, []ToolCallRecord, error) {
+	if maxSteps <= 0 {
+		maxSteps = DefaultMaxToolSteps
+	}
+	messages = append([]llm.Message(nil), messages...)
+
+	var records []ToolCallRecord
+	for step := 0; step < maxSteps; step++ {
+		reply, err := client.ChatTools(ctx, messages, toolbox.Tools())
+		if err != nil {
+			return "", records, err
+		}
+		if len(reply.ToolCalls) == 0 {
+			return reply.Content, records, nil
+		}
+
+		messages = append(messages, reply)
+		for _, call := range reply.ToolCalls {
+			if err := ctx.Err(); err != nil {
+				return "", records, err
+			}
+			record := toolbox.call(ctx, call)
+			records = append(records, record)
+			messages = append(messages, llm.ToolResultMessage(call, record.content()))
+		}
+	}
+	return "", records, fmt.Errorf("no final answer after %d tool steps", maxSteps)
+}
+
+// toolSection renders a role's tool calls as a collapsed block, or nothing
+func toolSection(records []ToolCallRecord) string {
+	if len(records) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n<details>\n<summary>🔧 Tool Calls</summary>\n\n")
+	for _, r := range records {
+		fmt.Fprintf(&b, "- `%s(%s)` → %s\n", r.Name, r.Arguments, r.content())
+	}
+	b.WriteString("\n</details>\n")
+	return b.String()
+}
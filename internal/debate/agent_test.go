@@ -0,0 +1,164 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+// toolClient replays scripted ChatTools replies and records the requests
+type toolClient struct {
+	replies  []llm.Message
+	requests [][]llm.Message
+}
+
+func (c *toolClient) Chat(ctx context.Context, messages []llm.Message) (string, error) {
+	return "", errors.New("unexpected Chat")
+}
+
+func (c *toolClient) ChatStream(ctx context.Context, messages []llm.Message, onChunk func(string)) (string, error) {
+	return "", errors.New("unexpected ChatStream")
+}
+
+func (c *toolClient) ChatTools(ctx context.Context, messages []llm.Message, tools []llm.Tool) (llm.Message, error) {
+	c.requests = append(c.requests, messages)
+	if len(c.requests) > len(c.replies) {
+		return llm.Message{}, errors.New("no scripted reply")
+	}
+	return c.replies[len(c.requests)-1], nil
+}
+
+func (c *toolClient) Usage() llm.Usage { return llm.Usage{} }
+func (c *toolClient) Close() error     { return nil }
+
+func echoToolbox() *Toolbox {
+	toolbox := NewToolbox()
+	toolbox.Register(llm.Tool{Name: "echo"}, func(ctx context.Context, arguments string) (string, error) {
+		return "echo " + arguments, nil
+	})
+	toolbox.Register(llm.Tool{Name: "fail"}, func(ctx context.Context, arguments string) (string, error) {
+		return "", errors.New("backend down")
+	})
+	return toolbox
+}
+
+func TestRunAgent(t *testing.T) {
+	client := &toolClient{replies: []llm.Message{
+		{Role: "assistant", ToolCalls: []llm.ToolCall{
+			{ID: "1", Name: "echo", Arguments: `{"x":1}`},
+			{ID: "2", Name: "fail", Arguments: `{}`},
+		}},
+		{Role: "assistant", ToolCalls: []llm.ToolCall{{ID: "3", Name: "missing", Arguments: `{}`}}},
+		{Role: "assistant", Content: "final answer"},
+	}}
+
+	messages := []llm.Message{{Role: "user", Content: "question"}}
+	answer, records, err := RunAgent(context.Background(), client, messages, echoToolbox(), 0)
+	if err != nil {
+		t.Fatalf("RunAgent() error = %v", err)
+	}
+	if answer != "final answer" {
+		t.Errorf("answer = %q", answer)
+	}
+	if len(messages) != 1 {
+		t.Error("RunAgent() should not modify the caller's messages")
+	}
+
+	if len(records) != 3 || records[0].Result != `echo {"x":1}` || records[1].Error != "backend down" || !strings.Contains(records[2].Error, "unknown tool") {
+		t.Errorf("records = %+v", records)
+	}
+
+	// The second request carries the first reply and one result per call
+	second := client.requests[1]
+	if len(second) != 4 || second[1].Role != "assistant" {
+		t.Fatalf("second request = %+v", second)
+	}
+	if second[2].ToolCallID != "1" || second[2].Content != `echo {"x":1}` {
+		t.Errorf("first result = %+v", second[2])
+	}
+	if second[3].ToolCallID != "2" || second[3].Content != "error: backend down" {
+		t.Errorf("failed result = %+v", second[3])
+	}
+}
+
+func TestRunAgent_MaxSteps(t *testing.T) {
+	loop := llm.Message{Role: "assistant", ToolCalls: []llm.ToolCall{{ID: "1", Name: "echo", Arguments: `{}`}}}
+	client := &toolClient{replies: []llm.Message{loop, loop, loop}}
+	_, records, err := RunAgent(context.Background(), client, []llm.Message{{Role: "user", Content: "q"}}, echoToolbox(), 2)
+	if err == nil || !strings.Contains(err.Error(), "no final answer after 2 tool steps") {
+		t.Errorf("RunAgent() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("records = %d, want 2", len(records))
+	}
+}
+
+func TestToolbox_RegisterReplaces(t *testing.T) {
+	toolbox := echoToolbox()
+	toolbox.Register(llm.Tool{Name: "echo", Description: "v2"}, func(ctx context.Context, arguments string) (string, error) {
+		return "v2", nil
+	})
+	if toolbox.Len() != 2 || toolbox.Tools()[0].Description != "v2" {
+		t.Errorf("Tools() = %+v", toolbox.Tools())
+	}
+	if r := toolbox.call(context.Background(), llm.ToolCall{Name: "echo"}); r.Result != "v2" {
+		t.Errorf("call() = %+v, want the new handler", r)
+	}
+}
+
+func TestExecutor_Execute_Tools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+			Tools []json.RawMessage `json:"tools"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		last := req.Messages[len(req.Messages)-1]
+
+		switch {
+		case len(req.Tools) == 0: // judge
+			io.WriteString(w, `{"choices":[{"message":{"content":"## 💡 One-Liner\n通过\n## 📝 Full Verdict\n理由"}}]}`)
+		case last.Role == "tool":
+			content, _ := json.Marshal("## 💡 One-Liner\n增长 " + last.Content + "%\n## 📝 Full Argument\n论证")
+			fmt.Fprintf(w, `{"choices":[{"message":{"content":%s}}]}`, content)
+		default:
+			io.WriteString(w, `{"choices":[{"message":{"content":"","tool_calls":[
+				{"id":"c1","type":"function","function":{"name":"calculator","arguments":"{\"expression\":\"(1200-800)/800*100\"}"}}
+			]}}]}`)
+		}
+	}))
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	toolbox, err := BuiltinToolbox([]string{"calculator"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor := NewExecutor(openAITestConfig(server.URL + "/v1"))
+	executor.SetTools(toolbox)
+
+	result, err := executor.Execute(context.Background(), "material")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.ProOneLiner != "增长 50%" || result.ConFullBody != "论证" {
+		t.Errorf("pro = %q, con body = %q", result.ProOneLiner, result.ConFullBody)
+	}
+	if len(result.ProToolCalls) != 1 || result.ProToolCalls[0].Result != "50" {
+		t.Errorf("ProToolCalls = %+v", result.ProToolCalls)
+	}
+	if result.VerdictOneLiner != "通过" {
+		t.Errorf("VerdictOneLiner = %q", result.VerdictOneLiner)
+	}
+}
@@ -0,0 +1,38 @@
+package debate
+
+import (
+	"context"
+
+	"github.com/hrygo/dialecta/internal/llm"
+	"github.com/hrygo/dialecta/internal/prompt"
+)
+
+// askFunc sends messages and returns the reply, e.g. client.Chat or a
+// ChatStream call feeding a StreamParser
+type askFunc func(ctx context.Context, messages []llm.Message) (string, error)
+
+// continueAnswer asks for an answer and, while the model stops at its token
+// limit, asks it up to maxContinuations times to continue where it stopped.
+// The parts are joined in order; when ask streams into a parser, the parser
+// sees them as one answer. It reports whether the answer is still cut off.
+func continueAnswer(ctx context.Context, messages []llm.Message, maxContinuations int, ask askFunc) (string, bool, error) {
+	var finish llm.FinishReason
+	ctx = llm.WithFinishReason(ctx, func(reason llm.FinishReason) { finish = reason })
+
+	text, err := ask(ctx, messages)
+	for n := 0; err == nil && finish == llm.FinishLength && n < maxContinuations; n++ {
+		finish = ""
+		var more string
+		more, err = ask(ctx, prompt.BuildContinuationMessages(messages, text))
+		text += more
+	}
+	return text, err == nil && finish == llm.FinishLength, err
+}
+
+// truncationNote marks a report section whose answer is incomplete
+func truncationNote(truncated bool) string {
+	if !truncated {
+		return ""
+	}
+	return "\n> ⚠️ **Truncated**: this answer hit the model's output token limit and is incomplete.\n"
+}
@@ -0,0 +1,155 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/prompt"
+)
+
+// truncatingServer streams the first part of each role's answer with
+// finish_reason "length" and the rest once asked to continue. The Judge's
+// answer is cut off no matter how often it is continued.
+func truncatingServer(t *testing.T, continuations *int) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		judge := strings.Contains(req.Messages[0].Content, "首席裁决官")
+		continued := req.Messages[len(req.Messages)-1].Content == prompt.ContinuePrompt
+		if continued {
+			mu.Lock()
+			*continuations++
+			mu.Unlock()
+		}
+
+		var content, finish string
+		switch {
+		case judge:
+			content, finish = "## 💡 One-Liner\n裁决\n\n## 📝 Full Verdict\n未完", "length"
+		case continued:
+			content, finish = "的论述", "stop"
+		default:
+			content, finish = "## 💡 One-Liner\n观点\n\n## 📝 Full Argument\n未完", "length"
+		}
+		chunk, _ := json.Marshal(map[string]any{
+			"choices": []map[string]any{{"delta": map[string]string{"content": content}, "finish_reason": finish}},
+		})
+		fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", chunk)
+	}))
+}
+
+func TestExecutor_Execute_Continuations(t *testing.T) {
+	var continuations int
+	server := truncatingServer(t, &continuations)
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	cfg := openAITestConfig(server.URL + "/v1")
+	cfg.ProRole.MaxContinuations = 2
+	cfg.ConRole.MaxContinuations = 0
+	cfg.JudgeRole.MaxContinuations = 1
+	executor := NewExecutor(cfg)
+	noop := func(string, bool) {}
+	executor.SetStream(noop, noop, noop)
+
+	result, err := executor.Execute(context.Background(), "material")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.ProTruncated || result.ProOneLiner != "观点" || result.ProFullBody != "未完的论述" {
+		t.Errorf("pro = %q %q, truncated %v; want the continuation stitched on", result.ProOneLiner, result.ProFullBody, result.ProTruncated)
+	}
+	if !result.ConTruncated || result.ConFullBody != "未完" {
+		t.Errorf("con = %q, truncated %v; want it marked truncated without continuing", result.ConFullBody, result.ConTruncated)
+	}
+	if !result.JudgeTruncated {
+		t.Error("JudgeTruncated = false, want true after its only continuation was cut off too")
+	}
+	if continuations != 2 {
+		t.Errorf("sent %d continuations, want 1 for Pro and 1 for the Judge", continuations)
+	}
+
+	report, err := os.ReadFile(result.ReportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if n := strings.Count(string(report), "**Truncated**"); n != 2 {
+		t.Errorf("report marks %d sections as truncated, want 2", n)
+	}
+}
+
+func TestExecutor_Execute_StreamingJudgeError(t *testing.T) {
+	for name, tc := range map[string]struct {
+		failing string // system prompt marker of the role that fails
+		judges  int
+		want    string
+	}{
+		"judge":       {"首席裁决官", 0, "adjudicator: "},
+		"chief judge": {"主审裁决官", 2, "chief judge: "},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Stream   bool `json:"stream"`
+					Messages []struct {
+						Content string `json:"content"`
+					} `json:"messages"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				if strings.Contains(req.Messages[0].Content, tc.failing) {
+					http.Error(w, `{"error":{"message":"invalid api key"}}`, http.StatusUnauthorized)
+					return
+				}
+				// The judges of a judge panel do not stream
+				content := "## 💡 One-Liner\n观点\n\n## 📝 Full Argument\n论述"
+				if !req.Stream {
+					json.NewEncoder(w).Encode(map[string]any{
+						"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": content}, "finish_reason": "stop"}},
+					})
+					return
+				}
+				chunk, _ := json.Marshal(map[string]any{
+					"choices": []map[string]any{{"delta": map[string]string{"content": content}, "finish_reason": "stop"}},
+				})
+				fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", chunk)
+			}))
+			defer server.Close()
+			t.Chdir(t.TempDir())
+
+			cfg := openAITestConfig(server.URL + "/v1")
+			for range tc.judges {
+				cfg.Judges = append(cfg.Judges, cfg.JudgeRole)
+			}
+			cfg.ChiefJudge = tc.judges > 0
+			executor := NewExecutor(cfg)
+			noop := func(string, bool) {}
+			executor.SetStream(noop, noop, noop)
+
+			result, err := executor.Execute(context.Background(), "material")
+			if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+				t.Fatalf("Execute() = %v, %v; want an error starting with %q", result, err, tc.want)
+			}
+			if _, statErr := os.Stat("reports"); statErr == nil {
+				t.Error("a report was saved for a failed debate")
+			}
+		})
+	}
+}
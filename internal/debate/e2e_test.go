@@ -0,0 +1,192 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hrygo/dialecta/internal/config"
+	"github.com/hrygo/dialecta/internal/llm"
+	"github.com/hrygo/dialecta/internal/testutil"
+)
+
+func TestExecutor_Execute_Cassette(t *testing.T) {
+	for _, provider := range testutil.CassetteProviders {
+		t.Run(string(provider), func(t *testing.T) {
+			cassette := testutil.OpenCassette(t, provider)
+			cfg := testutil.CassetteConfig(provider, cassette)
+			t.Chdir(t.TempDir()) // reports/ is written to the working directory
+
+			var proLive, conLive, judgeLive string
+			var judgeStarted bool
+			executor := NewExecutor(cfg)
+			executor.SetStream(
+				func(s string, done bool) { proLive += s },
+				func(s string, done bool) { conLive += s },
+				func(s string, done bool) { judgeLive += s },
+			)
+			executor.SetJudgeStartCallback(func() { judgeStarted = true })
+
+			result, err := executor.Execute(context.Background(), testutil.CassetteMaterial)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			// One-Liners reach the UI live and match the parsed result
+			if result.ProOneLiner == "" || result.ProOneLiner != proLive {
+				t.Errorf("ProOneLiner = %q, streamed %q", result.ProOneLiner, proLive)
+			}
+			if result.ConOneLiner == "" || result.ConOneLiner != conLive {
+				t.Errorf("ConOneLiner = %q, streamed %q", result.ConOneLiner, conLive)
+			}
+			if result.VerdictOneLiner == "" || result.VerdictOneLiner != judgeLive {
+				t.Errorf("VerdictOneLiner = %q, streamed %q", result.VerdictOneLiner, judgeLive)
+			}
+			if !judgeStarted {
+				t.Error("judge start callback was not called")
+			}
+
+			// Full bodies exclude the One-Liner section
+			for name, body := range map[string]string{
+				"ProFullBody":     result.ProFullBody,
+				"ConFullBody":     result.ConFullBody,
+				"VerdictFullBody": result.VerdictFullBody,
+			} {
+				if body == "" || strings.Contains(body, "## 💡 One-Liner") {
+					t.Errorf("%s = %q, want the full section only", name, body)
+				}
+			}
+
+			if result.JudgeModel.Provider != provider || result.JudgeModel.Fallback {
+				t.Errorf("JudgeModel = %v, want primary %s", result.JudgeModel, provider)
+			}
+			for name, usage := range map[string]RoleUsage{
+				"ProUsage":   result.ProUsage,
+				"ConUsage":   result.ConUsage,
+				"JudgeUsage": result.JudgeUsage,
+			} {
+				if usage.PromptTokens == 0 || usage.CompletionTokens == 0 || !usage.Priced {
+					t.Errorf("%s = %+v, want priced non-zero usage", name, usage)
+				}
+			}
+
+			report, err := os.ReadFile(result.ReportPath)
+			if err != nil {
+				t.Fatalf("read report: %v", err)
+			}
+			for _, want := range []string{result.ProFullBody, result.VerdictOneLiner, "## 📊 Token Usage", string(provider) + "/"} {
+				if !strings.Contains(string(report), want) {
+					t.Errorf("report missing %q", want)
+				}
+			}
+
+			if n := cassette.Unused(); n != 0 {
+				t.Errorf("%d recorded interactions were not replayed", n)
+			}
+		})
+	}
+}
+
+func TestExecutor_Execute_Mock(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("DIALECTA_MOCK_CHUNK_SIZE", "3")
+	cfg := config.New()
+	for _, role := range []*config.RoleConfig{&cfg.ProRole, &cfg.ConRole, &cfg.JudgeRole} {
+		role.Provider = llm.ProviderMock
+		role.Model = ""
+	}
+	executor := NewExecutor(cfg)
+	noop := func(string, bool) {}
+	executor.SetStream(noop, noop, noop)
+
+	result, err := executor.Execute(context.Background(), testutil.CassetteMaterial)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for name, got := range map[string]string{
+		"ProOneLiner":     result.ProOneLiner,
+		"ConOneLiner":     result.ConOneLiner,
+		"VerdictOneLiner": result.VerdictOneLiner,
+		"VerdictFullBody": result.VerdictFullBody,
+	} {
+		if got == "" {
+			t.Errorf("%s is empty", name)
+		}
+	}
+	if result.ProOneLiner == result.ConOneLiner {
+		t.Errorf("Pro and Con gave the same answer %q", result.ProOneLiner)
+	}
+	if !strings.Contains(result.VerdictOneLiner, "68/100") {
+		t.Errorf("VerdictOneLiner = %q, want the built-in judge answer", result.VerdictOneLiner)
+	}
+	if result.JudgeModel.Provider != llm.ProviderMock {
+		t.Errorf("JudgeModel = %+v, want the mock provider", result.JudgeModel)
+	}
+}
+
+func TestExecutor_Execute_StalledStream(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("DIALECTA_MOCK_LATENCY", "1s")
+	cfg := config.New()
+	for _, role := range []*config.RoleConfig{&cfg.ProRole, &cfg.ConRole, &cfg.JudgeRole} {
+		role.Provider = llm.ProviderMock
+		role.Retry = llm.RetryPolicy{}
+		role.Timeouts.Idle = 20 * time.Millisecond
+	}
+	executor := NewExecutor(cfg)
+	noop := func(string, bool) {}
+	executor.SetStream(noop, noop, noop)
+
+	_, err := executor.Execute(context.Background(), testutil.CassetteMaterial)
+	var timeoutErr *llm.TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Kind != llm.TimeoutIdle {
+		t.Fatalf("Execute() error = %v, want an idle TimeoutError", err)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "affirmative: mock") {
+		t.Errorf("error = %q, want it to name the role and provider", msg)
+	}
+}
+
+func TestExecutor_Execute_StalledJudgeStream(t *testing.T) {
+	for name, tc := range map[string]struct {
+		judges int
+		want   string
+	}{
+		"judge":       {0, "adjudicator: mock"},
+		"chief judge": {2, "chief judge: mock"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			// One chunk per answer, so only a role with a shorter idle
+			// timeout than the latency stalls
+			t.Setenv("DIALECTA_MOCK_CHUNK_SIZE", "100000")
+			t.Setenv("DIALECTA_MOCK_LATENCY", "50ms")
+			cfg := config.New()
+			for _, role := range []*config.RoleConfig{&cfg.ProRole, &cfg.ConRole, &cfg.JudgeRole} {
+				role.Provider = llm.ProviderMock
+				role.Retry = llm.RetryPolicy{}
+				role.Timeouts.Idle = time.Second
+			}
+			for range tc.judges {
+				cfg.Judges = append(cfg.Judges, cfg.JudgeRole)
+			}
+			cfg.ChiefJudge = tc.judges > 0
+			cfg.JudgeRole.Timeouts.Idle = 10 * time.Millisecond
+			executor := NewExecutor(cfg)
+			noop := func(string, bool) {}
+			executor.SetStream(noop, noop, noop)
+
+			_, err := executor.Execute(context.Background(), testutil.CassetteMaterial)
+			var timeoutErr *llm.TimeoutError
+			if !errors.As(err, &timeoutErr) || timeoutErr.Kind != llm.TimeoutIdle {
+				t.Fatalf("Execute() error = %v, want an idle TimeoutError", err)
+			}
+			if msg := err.Error(); !strings.HasPrefix(msg, tc.want) {
+				t.Errorf("error = %q, want it to name the role and provider", msg)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package debate
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,6 +25,100 @@ type Result struct {
 	VerdictOneLiner string // 裁决一句话
 	VerdictFullBody string // 裁决完整报告
 	ReportPath      string // 报告文件路径
+
+	// Every statement in speaking order, round by round with Pro before
+	// Con; the Pro and Con fields above hold the opening statements
+	Turns []Turn
+
+	// Structured mode only (see Executor.SetStructured); Score and Decision
+	// are also set by a judge panel, from its chief judge or its Consensus
+	ProArguments     []string // 正方论据
+	ConArguments     []string // 反方论据
+	VerdictArguments []string // 裁决依据
+	Score            int      // 综合评分 0-100
+	Decision         string   // DecisionApprove, DecisionReject or DecisionRevise; empty on a judge panel tie
+
+	ProModel   ModelInfo // 正方实际使用的模型
+	ConModel   ModelInfo // 反方实际使用的模型
+	JudgeModel ModelInfo // 裁决方实际使用的模型
+
+	ProReasoning   string // 正方推理过程（推理模型）
+	ConReasoning   string // 反方推理过程
+	JudgeReasoning string // 裁决方推理过程
+
+	ProToolCalls []ToolCallRecord // 正方工具调用
+	ConToolCalls []ToolCallRecord // 反方工具调用
+
+	// Set when an answer still stopped at MaxTokens after the role's
+	// continuations (see config.RoleConfig.MaxContinuations)
+	ProTruncated   bool // 正方输出被截断
+	ConTruncated   bool // 反方输出被截断
+	JudgeTruncated bool // 裁决输出被截断
+
+	ProUsage   RoleUsage // 正方 token 用量与费用
+	ConUsage   RoleUsage // 反方 token 用量与费用
+	JudgeUsage RoleUsage // 裁决方 token 用量与费用
+
+	// Panel debates only (see config.Config.Personas): one entry per
+	// persona in configured order; the Pro and Con fields are then empty
+	Panel []DebaterResult
+
+	// Judge panels only (see config.Config.Judges): each judge's verdict in
+	// configured order, and their aggregate. The Verdict and Judge fields
+	// hold the chief judge's verdict, or without one the aggregate with
+	// an empty JudgeModel.
+	Verdicts  []JudgeVerdict
+	Consensus *Consensus
+}
+
+// RoleUsage records a role's token usage and its estimated cost
+type RoleUsage struct {
+	llm.Usage
+	Cost   float64 // USD, estimated from config.Prices
+	Priced bool    // false when the model has no known price
+}
+
+// CostString formats the cost, or "n/a" when the price is unknown
+func (u RoleUsage) CostString() string {
+	if !u.Priced {
+		return "n/a"
+	}
+	return fmt.Sprintf("$%.4f", u.Cost)
+}
+
+// TotalUsage sums usage across all roles. The total is only priced when
+// every role is.
+func (r *Result) TotalUsage() RoleUsage {
+	total := RoleUsage{Priced: true}
+	usages := []RoleUsage{r.JudgeUsage}
+	for _, d := range r.Debaters() {
+		usages = append(usages, d.Usage)
+	}
+	for _, v := range r.Verdicts {
+		usages = append(usages, v.Usage)
+	}
+	for _, u := range usages {
+		total.Usage = total.Usage.Add(u.Usage)
+		total.Cost += u.Cost
+		total.Priced = total.Priced && u.Priced
+	}
+	return total
+}
+
+// ModelInfo records the provider/model that actually produced a response
+type ModelInfo struct {
+	Provider llm.Provider
+	Model    string
+	Fallback bool // true when the primary provider failed and a fallback answered
+}
+
+// String returns "provider/model", marked when a fallback was used
+func (m ModelInfo) String() string {
+	s := fmt.Sprintf("%s/%s", m.Provider, m.Model)
+	if m.Fallback {
+		s += " (fallback)"
+	}
+	return s
 }
 
 // Executor orchestrates the debate process
@@ -31,7 +128,14 @@ type Executor struct {
 	onPro        func(string, bool) // (content, done)
 	onCon        func(string, bool)
 	onJudge      func(string, bool)
-	onJudgeStart func() // Called right before judge phase begins
+	onPersona    func(string, string, bool) // (name, content, done) for panel personas
+	onJudgeStart func()                     // Called right before judge phase begins
+	onRoundStart func(int, int)             // (round, rounds) Called before each round of a multi-round debate
+	structured   bool                       // roles answer in schema-validated JSON
+	tools        *Toolbox                   // tools the debaters may call; nil for none
+	rounds       int                        // debate rounds; 1 is opening statements only
+	aggregation  Aggregation                // how a judge panel's scores are combined
+	onVerdict    func(int, JudgeVerdict)    // (judge index, verdict) as each judge of a panel rules
 }
 
 // NewExecutor creates a new debate executor
@@ -50,170 +154,382 @@ func (e *Executor) SetStream(onPro, onCon, onJudge func(string, bool)) {
 	e.onJudge = onJudge
 }
 
+// SetPanelStream enables streaming mode for a panel debate (see
+// config.Config.Personas): onPersona receives each persona's output by name,
+// the way SetStream's onPro does for Pro
+func (e *Executor) SetPanelStream(onPersona func(name, content string, done bool), onJudge func(string, bool)) {
+	e.stream = true
+	e.onPersona = onPersona
+	e.onJudge = onJudge
+}
+
+// panel reports whether personas debate instead of Pro and Con
+func (e *Executor) panel() bool {
+	return len(e.cfg.Personas) > 0
+}
+
+// SetStructured switches every role from Markdown to schema-validated JSON
+// answers, decoded into the Result without header parsing. Stream callbacks
+// then receive each one-liner once the full answer has arrived.
+func (e *Executor) SetStructured(structured bool) {
+	e.structured = structured
+}
+
+// SetTools lets Pro and Con call the toolbox's tools. Their answers then go
+// through RunAgent instead of streaming; stream callbacks receive each
+// one-liner once the final answer has arrived. The Judge never calls tools.
+func (e *Executor) SetTools(toolbox *Toolbox) {
+	e.tools = toolbox
+}
+
+// SetRounds sets how many rounds Pro and Con debate. Round 1 is the opening
+// statements; in each further round both sides rebut the other's previous
+// statement, and the Judge then rules on the whole transcript. Values below
+// 1 mean a single round.
+func (e *Executor) SetRounds(rounds int) {
+	e.rounds = rounds
+}
+
+// Rounds returns the number of debate rounds (see SetRounds)
+func (e *Executor) Rounds() int {
+	return max(e.rounds, 1)
+}
+
+func (e *Executor) usesTools() bool {
+	return e.tools != nil && e.tools.Len() > 0
+}
+
+// answer gets a debater's complete answer: through the tool loop when tools
+// are set, otherwise with a single Chat call. An answer cut off at the token
+// limit is continued up to maxContinuations times; the bool reports whether
+// it is still cut off.
+func (e *Executor) answer(ctx context.Context, client llm.Client, messages []llm.Message, maxContinuations int) (string, []ToolCallRecord, bool, error) {
+	if !e.usesTools() {
+		text, truncated, err := continueAnswer(ctx, messages, maxContinuations, client.Chat)
+		return text, nil, truncated, err
+	}
+	var records []ToolCallRecord
+	text, truncated, err := continueAnswer(ctx, messages, maxContinuations, func(ctx context.Context, messages []llm.Message) (string, error) {
+		text, calls, err := RunAgent(ctx, client, messages, e.tools, 0)
+		records = append(records, calls...)
+		return text, err
+	})
+	return text, records, truncated, err
+}
+
+// roleConfig returns role with the response format for structured mode
+func (e *Executor) roleConfig(role config.RoleConfig, format *llm.ResponseFormat) config.RoleConfig {
+	if e.structured {
+		role.ResponseFormat = format
+	}
+	return role
+}
+
 // SetJudgeStartCallback sets callback for when judge phase begins
 func (e *Executor) SetJudgeStartCallback(onJudgeStart func()) {
 	e.onJudgeStart = onJudgeStart
 }
 
+// SetRoundStartCallback sets callback for when each round of a multi-round
+// debate begins, with the round number and the number of rounds
+func (e *Executor) SetRoundStartCallback(onRoundStart func(round, rounds int)) {
+	e.onRoundStart = onRoundStart
+}
+
+// SetAggregation sets how the scores of a judge panel (see
+// config.Config.Judges) are combined; the default is AggregateMean
+func (e *Executor) SetAggregation(aggregation Aggregation) {
+	e.aggregation = aggregation
+}
+
+// SetJudgeVerdictCallback sets callback for when each judge of a judge
+// panel has ruled, with its index; judges rule concurrently
+func (e *Executor) SetJudgeVerdictCallback(onVerdict func(judge int, verdict JudgeVerdict)) {
+	e.onVerdict = onVerdict
+}
+
 // Execute runs the full debate workflow
 func (e *Executor) Execute(ctx context.Context, material string) (*Result, error) {
 	result := &Result{Material: material}
 
-	// Phase 1: 并行执行正反方辩论
-	var wg sync.WaitGroup
-	var proErr, conErr error
+	// Phase 1: 正反方（或评审小组各成员）并行发言：首轮立论，之后回应其他人上一轮的论述
+	debaters, err := e.newDebaters(material)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range debaters {
+		defer d.client.Close()
+	}
 
-	proParser := NewStreamParser("## 📝 Full Argument")
-	conParser := NewStreamParser("## 📝 Full Argument")
+	rounds := e.Rounds()
+	last := make([]Turn, len(debaters))
+	for round := 1; round <= rounds; round++ {
+		if rounds > 1 && e.onRoundStart != nil {
+			e.onRoundStart(round, rounds)
+		}
 
-	wg.Add(2)
+		var wg sync.WaitGroup
+		turns := make([]Turn, len(debaters))
+		errs := make([]error, len(debaters))
+		for i, d := range debaters {
+			others := append(append([]Turn(nil), last[:i]...), last[i+1:]...)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				turns[i], errs[i] = e.speak(ctx, d, round, others)
+			}()
+		}
+		wg.Wait()
 
-	// 正方
-	go func() {
-		defer wg.Done()
-		client, err := llm.NewClient(e.cfg.ProRole.ToLLMConfig())
-		if err != nil {
-			proErr = fmt.Errorf("create pro client: %w", err)
-			return
+		for i, err := range errs {
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", debaters[i].label, err)
+			}
 		}
+		result.Turns = append(result.Turns, turns...)
+		last = turns
+	}
 
-		messages := prompt.BuildAffirmativeMessages(material)
-		if e.stream && e.onPro != nil {
-			// Stream Callback
-			_, err = client.ChatStream(ctx, messages, func(chunk string) {
-				// Feed parser
-				oneLiner, found := proParser.Feed(chunk)
-				if found {
-					// Notify CLI with the One-Liner ONLY once
-					e.onPro(oneLiner, false)
-				}
-			})
-			proParser.Finalize()
-			result.ProOneLiner = proParser.oneLiner
-			result.ProFullBody = proParser.fullBody
+	for i, d := range debaters {
+		result.addDebater(d.result(result.Turns[i]))
+	}
 
-			// Determine what to save if parsing failed (fallback)
-			if result.ProFullBody == "" {
-				result.ProFullBody = proParser.buffer.String()
-			}
+	// Notify that judge phase is starting (before any preparation work)
+	if e.onJudgeStart != nil {
+		e.onJudgeStart()
+	}
 
-			e.onPro("", true) // Signal done
-		} else {
-			// Non-streaming logic (Simplified for now, assumes streaming is primary)
-			full, err := client.Chat(ctx, messages)
-			if err == nil {
-				// We still parse for result structure
-				proParser.Feed(full)
-				proParser.Finalize()
-				result.ProOneLiner = proParser.oneLiner
-				result.ProFullBody = proParser.fullBody
-			}
-			proErr = err
+	// Phase 2: 裁决（单一裁决方，或裁判团并行裁决后汇总）
+	// Use Full Bodies for Judge context; after rebuttals, the whole transcript
+	var messages []llm.Message
+	switch {
+	case e.panel():
+		messages = prompt.BuildPanelAdjudicatorMessages(material, result.transcript())
+	case rounds > 1:
+		messages = prompt.BuildTranscriptAdjudicatorMessages(material, result.transcript())
+	default:
+		messages = prompt.BuildAdjudicatorMessages(material, result.ProFullBody, result.ConFullBody)
+	}
+
+	if len(e.cfg.Judges) > 0 {
+		if err := e.judgePanel(ctx, result, messages); err != nil {
+			return nil, err
 		}
+	} else {
+		judgeClient, err := newRoleClient(e.roleConfig(e.cfg.JudgeRole, verdictFormat))
 		if err != nil {
-			proErr = fmt.Errorf("affirmative: %w", err)
+			return nil, fmt.Errorf("create judge client: %w", err)
 		}
-	}()
+		defer judgeClient.Close()
 
-	// 反方
-	go func() {
-		defer wg.Done()
-		client, err := llm.NewClient(e.cfg.ConRole.ToLLMConfig())
+		verdict, err := e.judge(ctx, judgeClient, e.cfg.JudgeRole, messages, e.onJudge)
 		if err != nil {
-			conErr = fmt.Errorf("create con client: %w", err)
-			return
+			return nil, fmt.Errorf("adjudicator: %w", err)
 		}
+		result.setVerdict(verdict, e.structured)
+	}
+
+	// Generate Report
+	if err := e.saveReport(result); err != nil {
+		// Log error but don't fail the debate?
+		fmt.Printf("Warning: Failed to save report: %v\n", err)
+	}
+
+	return result, nil
+}
+
+// judge gets role's verdict on messages from client, streaming its
+// one-liner to onJudge when streaming is on. A Markdown verdict's score and
+// decision are parsed from its text.
+func (e *Executor) judge(ctx context.Context, client llm.Client, role config.RoleConfig, messages []llm.Message, onJudge func(string, bool)) (JudgeVerdict, error) {
+	var verdict JudgeVerdict
 
-		messages := prompt.BuildNegativeMessages(material)
-		if e.stream && e.onCon != nil {
-			_, err = client.ChatStream(ctx, messages, func(chunk string) {
-				oneLiner, found := conParser.Feed(chunk)
+	// Reasoning goes to its own buffer, never into the parser
+	var reasoning strings.Builder
+	ctx = llm.WithReasoning(ctx, func(s string) { reasoning.WriteString(s) })
+	judgeParser := NewStreamParser("## 📝 Full Verdict")
+
+	if e.structured {
+		var answer *structuredAnswer
+		text, truncated, err := continueAnswer(ctx, prompt.WithJSONOutput(messages, prompt.VerdictJSONFormat), role.MaxContinuations, client.Chat)
+		verdict.Truncated = truncated
+		if err == nil {
+			answer, err = decodeAnswer(text, verdictFormat.Schema)
+		}
+		if err == nil {
+			verdict.OneLiner = answer.headline()
+			verdict.Arguments = answer.Arguments
+			verdict.FullBody = answer.verdictBody()
+			verdict.Score = answer.Score
+			verdict.Decision = answer.Decision
+		}
+		if e.stream {
+			notifyAnswer(onJudge, verdict.OneLiner)
+		}
+		if err != nil {
+			return verdict, err
+		}
+	} else if e.stream && onJudge != nil {
+		_, truncated, err := continueAnswer(ctx, messages, role.MaxContinuations, func(ctx context.Context, messages []llm.Message) (string, error) {
+			return client.ChatStream(ctx, messages, func(chunk string) {
+				oneLiner, found := judgeParser.Feed(chunk)
 				if found {
-					e.onCon(oneLiner, false)
+					onJudge(oneLiner, false)
 				}
 			})
-			conParser.Finalize()
-			result.ConOneLiner = conParser.oneLiner
-			result.ConFullBody = conParser.fullBody
-			if result.ConFullBody == "" {
-				result.ConFullBody = conParser.buffer.String()
-			}
+		})
+		judgeParser.Finalize()
+		verdict.OneLiner = judgeParser.oneLiner
+		verdict.FullBody = judgeParser.fullBody
+		if verdict.FullBody == "" {
+			verdict.FullBody = judgeParser.buffer.String()
+		}
 
-			e.onCon("", true)
-		} else {
-			full, err := client.Chat(ctx, messages)
-			if err == nil {
-				conParser.Feed(full)
-				conParser.Finalize()
-				result.ConOneLiner = conParser.oneLiner
-				result.ConFullBody = conParser.fullBody
-			}
-			conErr = err
+		onJudge("", true)
+		verdict.Truncated = truncated
+		if err != nil {
+			return verdict, err
 		}
+	} else {
+		full, truncated, err := continueAnswer(ctx, messages, role.MaxContinuations, client.Chat)
+		verdict.Truncated = truncated
 		if err != nil {
-			conErr = fmt.Errorf("negative: %w", err)
+			return verdict, err
 		}
-	}()
-
-	wg.Wait()
-
-	if proErr != nil {
-		return nil, proErr
+		judgeParser.Feed(full)
+		judgeParser.Finalize()
+		verdict.OneLiner = judgeParser.oneLiner
+		verdict.FullBody = judgeParser.fullBody
 	}
-	if conErr != nil {
-		return nil, conErr
+	if !e.structured {
+		verdict.Score, verdict.Decision = parseVerdict(verdict.OneLiner, verdict.FullBody)
 	}
 
-	// Notify that judge phase is starting (before any preparation work)
-	if e.onJudgeStart != nil {
-		e.onJudgeStart()
-	}
+	verdict.Reasoning = reasoning.String()
+	verdict.Model = usedModel(client, role)
+	verdict.Usage = roleUsage(client, verdict.Model)
+	return verdict, nil
+}
 
-	// Phase 2: 裁决
-	judgeClient, err := llm.NewClient(e.cfg.JudgeRole.ToLLMConfig())
-	if err != nil {
-		return nil, fmt.Errorf("create judge client: %w", err)
+// setVerdict stores the final verdict in the Verdict and Judge fields; the
+// score and decision are kept when withScore is set
+func (r *Result) setVerdict(v JudgeVerdict, withScore bool) {
+	r.VerdictOneLiner, r.VerdictFullBody, r.VerdictArguments = v.OneLiner, v.FullBody, v.Arguments
+	r.JudgeModel, r.JudgeUsage, r.JudgeReasoning, r.JudgeTruncated = v.Model, v.Usage, v.Reasoning, v.Truncated
+	if withScore {
+		r.Score, r.Decision = v.Score, v.Decision
 	}
+}
 
-	// Use Full Bodies for Judge context
-	messages := prompt.BuildAdjudicatorMessages(material, result.ProFullBody, result.ConFullBody)
-	judgeParser := NewStreamParser("## 📝 Full Verdict")
-
-	if e.stream && e.onJudge != nil {
-		_, _ = judgeClient.ChatStream(ctx, messages, func(chunk string) {
-			oneLiner, found := judgeParser.Feed(chunk)
-			if found {
-				e.onJudge(oneLiner, false)
+// judgePanel has every judge of the panel rule on messages concurrently and
+// aggregates their verdicts; the chief judge, if any, then reconciles them
+// into the final verdict. A judge that fails is kept in Result.Verdicts
+// with its error and left out; the panel fails only when every judge does.
+func (e *Executor) judgePanel(ctx context.Context, result *Result, messages []llm.Message) error {
+	judges := e.cfg.Judges
+	verdicts := make([]JudgeVerdict, len(judges))
+	var wg sync.WaitGroup
+	for i, role := range judges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if e.onVerdict != nil {
+					e.onVerdict(i, verdicts[i])
+				}
+			}()
+			client, err := newRoleClient(e.roleConfig(role, verdictFormat))
+			if err != nil {
+				verdicts[i] = JudgeVerdict{Model: usedModel(nil, role), Err: fmt.Errorf("create judge %d client: %w", i+1, err)}
+				return
 			}
-		})
-		judgeParser.Finalize()
-		result.VerdictOneLiner = judgeParser.oneLiner
-		result.VerdictFullBody = judgeParser.fullBody
-		if result.VerdictFullBody == "" {
-			result.VerdictFullBody = judgeParser.buffer.String()
+			defer client.Close()
+			if verdicts[i], err = e.judge(ctx, client, role, messages, nil); err != nil {
+				verdicts[i].Model, verdicts[i].Err = usedModel(client, role), fmt.Errorf("judge %d: %w", i+1, err)
+			}
+		}()
+	}
+	wg.Wait()
+	var errs []error
+	for _, v := range verdicts {
+		if v.Err != nil {
+			errs = append(errs, v.Err)
 		}
+	}
+	if len(errs) == len(verdicts) {
+		return errors.Join(errs...)
+	}
 
-		e.onJudge("", true)
-	} else {
-		full, err := judgeClient.Chat(ctx, messages)
-		if err == nil {
-			judgeParser.Feed(full)
-			judgeParser.Finalize()
-			result.VerdictOneLiner = judgeParser.oneLiner
-			result.VerdictFullBody = judgeParser.fullBody
+	aggregation := e.aggregation
+	if aggregation == "" {
+		aggregation = AggregateMean
+	}
+	consensus := aggregate(verdicts, aggregation)
+	result.Verdicts, result.Consensus = verdicts, consensus
+	result.Score, result.Decision = int(math.Round(consensus.Score)), consensus.Decision
+
+	if !e.cfg.ChiefJudge {
+		result.VerdictOneLiner = consensus.headline()
+		result.VerdictFullBody = consensus.verdictBody(verdicts)
+		if e.stream {
+			notifyAnswer(e.onJudge, result.VerdictOneLiner)
 		}
-		if err != nil {
-			return nil, fmt.Errorf("adjudicator: %w", err)
+		return nil
+	}
+
+	var statements []prompt.Statement
+	for i, v := range verdicts {
+		if v.Err == nil {
+			statements = append(statements, prompt.Statement{Speaker: fmt.Sprintf("裁判 %d · %s", i+1, v.Model), Content: v.OneLiner + "\n\n" + v.FullBody})
 		}
 	}
+	client, err := newRoleClient(e.roleConfig(e.cfg.JudgeRole, verdictFormat))
+	if err != nil {
+		return fmt.Errorf("create chief judge client: %w", err)
+	}
+	defer client.Close()
+	chief, err := e.judge(ctx, client, e.cfg.JudgeRole, prompt.BuildChiefJudgeMessages(result.Material, statements, consensus.Summary()), e.onJudge)
+	if err != nil {
+		return fmt.Errorf("chief judge: %w", err)
+	}
+	result.setVerdict(chief, chief.Scored())
+	return nil
+}
 
-	// Generate Report
-	if err := e.saveReport(result); err != nil {
-		// Log error but don't fail the debate?
-		fmt.Printf("Warning: Failed to save report: %v\n", err)
+// newRoleClient creates the client for a role, wrapping it in a fallback
+// chain when the role lists fallbacks
+func newRoleClient(role config.RoleConfig) (llm.Client, error) {
+	if len(role.Fallbacks) == 0 {
+		return llm.NewClient(role.ToLLMConfig())
 	}
+	return llm.NewFallbackClient(role.Chain()), nil
+}
 
-	return result, nil
+// usedModel reports which provider/model served the role's last request
+func usedModel(client llm.Client, role config.RoleConfig) ModelInfo {
+	if fc, ok := client.(*llm.FallbackClient); ok {
+		provider, model, fallback := fc.Used()
+		info := ModelInfo{Provider: provider, Model: model, Fallback: fallback}
+		if info.Model == "" {
+			info.Model = config.GetDefaultModel(provider)
+		}
+		return info
+	}
+	model := role.Model
+	if model == "" {
+		model = config.GetDefaultModel(role.Provider)
+	}
+	return ModelInfo{Provider: role.Provider, Model: model}
+}
+
+// roleUsage reads the client's usage and prices it for the model that answered
+func roleUsage(client llm.Client, info ModelInfo) RoleUsage {
+	usage := RoleUsage{Usage: client.Usage()}
+	if price, ok := config.LookupPrice(info.Provider, info.Model); ok {
+		usage.Cost = price.Cost(usage.Usage)
+		usage.Priced = true
+	}
+	return usage
 }
 
 func (e *Executor) saveReport(r *Result) error {
@@ -232,36 +548,44 @@ func (e *Executor) saveReport(r *Result) error {
 	defer file.Close()
 
 	// Write Content
+	debaters := r.Debaters()
+	models := make([]string, 0, len(debaters)+1)
+	var sections strings.Builder
+	for i, d := range debaters {
+		short, mark, heading := d.reportTitles(i)
+		models = append(models, fmt.Sprintf("%s %s", short, d.Model))
+		fmt.Fprintf(&sections, "## 💡 %s One-Liner\n%s\n\n## %s %s Argument (Full)\n%s\n%s\n---\n\n",
+			short, d.OneLiner, mark, heading, d.FullBody,
+			truncationNote(d.Truncated)+reasoningSection(d.Reasoning)+toolSection(d.ToolCalls))
+	}
+	for i, v := range r.Verdicts {
+		models = append(models, fmt.Sprintf("Judge %d %s", i+1, v.Model))
+	}
+	if title, ok := r.judgeTitle(); ok {
+		models = append(models, fmt.Sprintf("%s %s", title, r.JudgeModel))
+	}
+
 	tmpl := `# Debate Report
 > Generated by Dialecta at %s
+> Models: %s
 
-## 💡 Pro One-Liner
+%s%s%s## 💡 Verdict
 %s
 
-## 🟢 Affirmative Argument (Full)
-%s
-
----
-
-## 💡 Con One-Liner
+## ⚖️ Full Adjudication
 %s
-
-## 🔴 Negative Argument (Full)
 %s
-
 ---
 
-## 💡 Verdict
-%s
-
-## ⚖️ Full Adjudication
-%s
-`
+%s`
 	content := fmt.Sprintf(tmpl,
 		time.Now().Format(time.RFC1123),
-		r.ProOneLiner, r.ProFullBody,
-		r.ConOneLiner, r.ConFullBody,
-		r.VerdictOneLiner, r.VerdictFullBody,
+		strings.Join(models, " · "),
+		sections.String(),
+		rebuttalSection(r),
+		judgePanelSection(r),
+		r.VerdictOneLiner, r.VerdictFullBody, truncationNote(r.JudgeTruncated)+reasoningSection(r.JudgeReasoning),
+		usageTable(r),
 	)
 
 	if _, err := file.WriteString(content); err != nil {
@@ -271,3 +595,37 @@ func (e *Executor) saveReport(r *Result) error {
 	r.ReportPath = filename
 	return nil
 }
+
+// reasoningSection renders a model's reasoning as a collapsed block, or
+// nothing when the model produced none
+func reasoningSection(reasoning string) string {
+	reasoning = strings.TrimSpace(reasoning)
+	if reasoning == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n<details>\n<summary>🧠 Reasoning</summary>\n\n%s\n\n</details>\n", reasoning)
+}
+
+// usageTable renders the report footer with per-role token usage and cost
+func usageTable(r *Result) string {
+	var b strings.Builder
+	b.WriteString("## 📊 Token Usage\n")
+	b.WriteString("| Role | Model | Prompt | Cached | Completion | Cost (USD) |\n")
+	b.WriteString("|------|-------|-------:|-------:|-----------:|-----------:|\n")
+	row := func(role, model string, u RoleUsage) {
+		fmt.Fprintf(&b, "| %s | %s | %d | %d | %d | %s |\n",
+			role, model, u.PromptTokens, u.CachedTokens, u.CompletionTokens, u.CostString())
+	}
+	for i, d := range r.Debaters() {
+		short, _, _ := d.reportTitles(i)
+		row(short, d.Model.String(), d.Usage)
+	}
+	for i, v := range r.Verdicts {
+		row(fmt.Sprintf("Judge %d", i+1), v.Model.String(), v.Usage)
+	}
+	if title, ok := r.judgeTitle(); ok {
+		row(title, r.JudgeModel.String(), r.JudgeUsage)
+	}
+	row("**Total**", "", r.TotalUsage())
+	return b.String()
+}
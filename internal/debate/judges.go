@@ -0,0 +1,289 @@
+package debate
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Aggregation is how a judge panel's scores are combined; decisions always
+// go by majority
+type Aggregation string
+
+const (
+	AggregateMean     Aggregation = "mean"     // 均值
+	AggregateMedian   Aggregation = "median"   // 中位数
+	AggregateMajority Aggregation = "majority" // 多数派均值: mean of the judges voting the majority decision
+)
+
+// ParseAggregation parses "mean", "median" or "majority"; empty is mean
+func ParseAggregation(s string) (Aggregation, error) {
+	switch a := Aggregation(strings.ToLower(strings.TrimSpace(s))); a {
+	case "":
+		return AggregateMean, nil
+	case AggregateMean, AggregateMedian, AggregateMajority:
+		return a, nil
+	}
+	return "", fmt.Errorf("unknown score aggregation %q (mean, median, majority)", s)
+}
+
+// JudgeVerdict is one judge's verdict in a judge panel, or the verdict of
+// its chief judge
+type JudgeVerdict struct {
+	OneLiner  string
+	FullBody  string
+	Arguments []string // 裁决依据, structured mode only
+	Score     int      // 0-100
+	Decision  string   // DecisionApprove, DecisionReject or DecisionRevise; empty when none was found
+	Model     ModelInfo
+	Usage     RoleUsage
+	Reasoning string
+	Truncated bool
+	Err       error // why a panel judge gave no verdict; only Model is set then
+}
+
+// Scored reports whether the verdict has a score and decision to aggregate
+func (v JudgeVerdict) Scored() bool {
+	return v.Decision != ""
+}
+
+// decisionLabel returns the verdict's decision as the prompts write it, "-"
+// when it has none, or "失败" when the judge failed
+func (v JudgeVerdict) decisionLabel() string {
+	switch {
+	case v.Err != nil:
+		return "失败"
+	case !v.Scored():
+		return "-"
+	}
+	return decisionLabels[v.Decision]
+}
+
+var (
+	// verdictScore matches "【评分: 72/100】" and "**综合评分**：72 / 100"
+	verdictScore = regexp.MustCompile(`评分\**\s*[:：]\s*\**\s*(\d{1,3})\s*/\s*100`)
+	// verdictDecision matches "【结论：需修改】" and "**裁决结论**：需修改"
+	verdictDecision = regexp.MustCompile(`结论\**\s*[:：]\s*\**\s*(通过|驳回|需修改)`)
+)
+
+// parseVerdict finds the score and decision a Markdown verdict states, in
+// its one-liner or else its body. Without both, the decision is empty.
+func parseVerdict(oneLiner, body string) (score int, decision string) {
+	text := oneLiner + "\n" + body
+	scoreMatch := verdictScore.FindStringSubmatch(text)
+	decisionMatch := verdictDecision.FindStringSubmatch(text)
+	if scoreMatch == nil || decisionMatch == nil {
+		return 0, ""
+	}
+	score, _ = strconv.Atoi(scoreMatch[1])
+	if score > 100 {
+		return 0, ""
+	}
+	for d, label := range decisionLabels {
+		if label == decisionMatch[1] {
+			decision = d
+		}
+	}
+	return score, decision
+}
+
+// Consensus aggregates the scored verdicts of a judge panel
+type Consensus struct {
+	Method    Aggregation
+	Judges    int            // verdicts with a score and decision
+	Unparsed  int            // verdicts without a score and decision, left out of the aggregate
+	Failed    int            // judges that failed to give a verdict, also left out
+	Score     float64        // mean, median or majority mean score
+	Decision  string         // majority decision; empty on a tie
+	Votes     map[string]int // judges per decision
+	Agreement float64        // share of judges voting the most voted decision, 0-1
+	StdDev    float64        // population standard deviation of the scores
+	MinScore  int
+	MaxScore  int
+}
+
+// aggregate combines the scored verdicts' scores by method and their
+// decisions by majority
+func aggregate(verdicts []JudgeVerdict, method Aggregation) *Consensus {
+	c := &Consensus{Method: method, Votes: make(map[string]int)}
+	var scores []float64
+	for _, v := range verdicts {
+		switch {
+		case v.Err != nil:
+			c.Failed++
+			continue
+		case !v.Scored():
+			c.Unparsed++
+			continue
+		}
+		scores = append(scores, float64(v.Score))
+		c.Votes[v.Decision]++
+	}
+	c.Judges = len(scores)
+	if c.Judges == 0 {
+		return c
+	}
+
+	sort.Float64s(scores)
+	c.MinScore, c.MaxScore = int(scores[0]), int(scores[len(scores)-1])
+	var sum float64
+	for _, s := range scores {
+		sum += s
+	}
+	mean := sum / float64(len(scores))
+	var variance float64
+	for _, s := range scores {
+		variance += (s - mean) * (s - mean)
+	}
+	c.StdDev = math.Sqrt(variance / float64(len(scores)))
+	c.Score = mean
+	if method == AggregateMedian {
+		mid := len(scores) / 2
+		c.Score = scores[mid]
+		if len(scores)%2 == 0 {
+			c.Score = (scores[mid-1] + scores[mid]) / 2
+		}
+	}
+
+	most, tie := 0, false
+	for _, d := range decisions {
+		switch n := c.Votes[d]; {
+		case n > most:
+			c.Decision, most, tie = d, n, false
+		case n == most && n > 0:
+			tie = true
+		}
+	}
+	if tie {
+		c.Decision = ""
+	}
+	c.Agreement = float64(most) / float64(c.Judges)
+
+	// On a tie there is no majority, so the majority mean is the mean
+	if method == AggregateMajority && !tie {
+		var sum float64
+		for _, v := range verdicts {
+			if v.Scored() && v.Decision == c.Decision {
+				sum += float64(v.Score)
+			}
+		}
+		c.Score = sum / float64(most)
+	}
+	return c
+}
+
+// decisions lists the decisions in the order votes are reported
+var decisions = []string{DecisionApprove, DecisionRevise, DecisionReject}
+
+// Tie reports whether the judges' votes are split with no majority decision
+func (c *Consensus) Tie() bool {
+	return c.Judges > 0 && c.Decision == ""
+}
+
+// decisionLabel returns the majority decision as the prompts write it, or
+// "平票" on a tie
+func (c *Consensus) decisionLabel() string {
+	if c.Tie() {
+		return "平票"
+	}
+	return decisionLabels[c.Decision]
+}
+
+// voteSummary renders the decision with its votes, like "需修改（2/3 位裁判）",
+// or every decision voted for on a tie
+func (c *Consensus) voteSummary() string {
+	if !c.Tie() {
+		return fmt.Sprintf("%s（%d/%d 位裁判）", decisionLabels[c.Decision], c.Votes[c.Decision], c.Judges)
+	}
+	var votes []string
+	for _, d := range decisions {
+		if n := c.Votes[d]; n > 0 {
+			votes = append(votes, fmt.Sprintf("%s %d", decisionLabels[d], n))
+		}
+	}
+	return fmt.Sprintf("平票（%s，共 %d 位裁判）", strings.Join(votes, "、"), c.Judges)
+}
+
+// Summary renders the aggregate in one line, as given to the chief judge
+// and shown in reports
+func (c *Consensus) Summary() string {
+	summary := "没有裁判给出可解析的评分与结论"
+	if c.Judges > 0 {
+		method := "均值"
+		switch c.Method {
+		case AggregateMedian:
+			method = "中位数"
+		case AggregateMajority:
+			method = "多数派均值"
+		}
+		summary = fmt.Sprintf("%s评分 %.1f（标准差 %.1f，区间 %d-%d）；多数结论：%s",
+			method, c.Score, c.StdDev, c.MinScore, c.MaxScore, c.voteSummary())
+		if c.Unparsed > 0 {
+			summary += fmt.Sprintf("；%d 份裁决无可解析的评分与结论，未计入", c.Unparsed)
+		}
+	}
+	if c.Failed > 0 {
+		summary += fmt.Sprintf("；%d 位裁判未能给出裁决", c.Failed)
+	}
+	return summary
+}
+
+// headline is the panel's verdict one-liner when there is no chief judge,
+// in the format the Judge's one-liner uses
+func (c *Consensus) headline() string {
+	if c.Judges == 0 {
+		return c.Summary()
+	}
+	return fmt.Sprintf("【评分: %d/100】 【结论：%s】 %s", int(math.Round(c.Score)), c.decisionLabel(), c.Summary())
+}
+
+// verdictBody renders the panel's verdicts and their aggregate as the full
+// verdict when there is no chief judge
+func (c *Consensus) verdictBody(verdicts []JudgeVerdict) string {
+	var b strings.Builder
+	b.WriteString("## ⚖️ 裁判团裁决\n\n| 裁判 | 模型 | 评分 | 结论 |\n|------|------|-----:|------|\n")
+	for i, v := range verdicts {
+		score := "-"
+		if v.Scored() {
+			score = strconv.Itoa(v.Score)
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s | %s |\n", i+1, v.Model, score, v.decisionLabel())
+	}
+	fmt.Fprintf(&b, "\n**汇总**：%s", c.Summary())
+	return b.String()
+}
+
+// judgeTitle names the role behind the Judge fields: the Judge, or the chief
+// judge of a judge panel. It reports false for a panel without one.
+func (r *Result) judgeTitle() (string, bool) {
+	switch {
+	case len(r.Verdicts) == 0:
+		return "Judge", true
+	case r.JudgeModel.Provider != "":
+		return "Chief Judge", true
+	}
+	return "", false
+}
+
+// judgePanelSection renders the report section with every verdict of a
+// judge panel and their aggregate, or nothing without a panel
+func judgePanelSection(r *Result) string {
+	if r.Consensus == nil {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "## ⚖️ Judge Panel\n> %s\n\n", r.Consensus.Summary())
+	for i, v := range r.Verdicts {
+		if v.Err != nil {
+			fmt.Fprintf(&b, "### ⚖️ Judge %d · %s\n> ❌ 未能给出裁决：%v\n\n", i+1, v.Model, v.Err)
+			continue
+		}
+		fmt.Fprintf(&b, "### ⚖️ Judge %d · %s\n> %s\n\n%s\n%s\n",
+			i+1, v.Model, v.OneLiner, v.FullBody, truncationNote(v.Truncated)+reasoningSection(v.Reasoning))
+	}
+	b.WriteString("---\n\n")
+	return b.String()
+}
@@ -0,0 +1,372 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/config"
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+func TestParseVerdict(t *testing.T) {
+	tests := []struct {
+		name, oneLiner, body string
+		score                int
+		decision             string
+	}{
+		{"one-liner", "【评分: 72/100】 【结论：需修改】 理由", "", 72, DecisionRevise},
+		{"body", "正方胜出", "### 3. 最终裁决\n* **综合评分**：85 / 100\n* **裁决结论**：通过", 85, DecisionApprove},
+		{"full-width colon", "【评分：40/100】【结论: 驳回】", "", 40, DecisionReject},
+		{"no decision", "【评分: 72/100】", "", 0, ""},
+		{"out of range", "【评分: 720/100】 【结论：通过】", "", 0, ""},
+		{"nothing", "正方胜出", "", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, decision := parseVerdict(tt.oneLiner, tt.body)
+			if score != tt.score || decision != tt.decision {
+				t.Errorf("parseVerdict() = %d, %q, want %d, %q", score, decision, tt.score, tt.decision)
+			}
+		})
+	}
+}
+
+func TestParseAggregation(t *testing.T) {
+	for in, want := range map[string]Aggregation{"": AggregateMean, "mean": AggregateMean, " Median ": AggregateMedian, "majority": AggregateMajority} {
+		if got, err := ParseAggregation(in); got != want || err != nil {
+			t.Errorf("ParseAggregation(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseAggregation("mode"); err == nil {
+		t.Error("ParseAggregation() should reject unknown methods")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	verdict := func(score int, decision string) JudgeVerdict {
+		return JudgeVerdict{Score: score, Decision: decision}
+	}
+	verdicts := []JudgeVerdict{
+		verdict(60, DecisionRevise),
+		verdict(90, DecisionApprove),
+		verdict(70, DecisionRevise),
+		{OneLiner: "unparsed"},
+	}
+
+	mean := aggregate(verdicts, AggregateMean)
+	if mean.Judges != 3 || mean.Unparsed != 1 || mean.Score != 220.0/3 || mean.Decision != DecisionRevise || mean.MinScore != 60 || mean.MaxScore != 90 {
+		t.Errorf("aggregate(mean) = %+v", mean)
+	}
+	if math.Abs(mean.StdDev-12.472) > 0.001 || math.Abs(mean.Agreement-2.0/3) > 1e-9 {
+		t.Errorf("StdDev = %.3f, Agreement = %.3f; want 12.472 and 2/3", mean.StdDev, mean.Agreement)
+	}
+	if want := "均值评分 73.3（标准差 12.5，区间 60-90）；多数结论：需修改（2/3 位裁判）；1 份裁决无可解析的评分与结论，未计入"; mean.Summary() != want {
+		t.Errorf("Summary() = %q, want %q", mean.Summary(), want)
+	}
+	if got := mean.headline(); !strings.HasPrefix(got, "【评分: 73/100】 【结论：需修改】") {
+		t.Errorf("headline() = %q", got)
+	}
+
+	if median := aggregate(verdicts, AggregateMedian); median.Score != 70 {
+		t.Errorf("aggregate(median).Score = %v, want 70", median.Score)
+	}
+	if even := aggregate(verdicts[:2], AggregateMedian); even.Score != 75 {
+		t.Errorf("aggregate(median) of two = %v, want their mean", even.Score)
+	}
+	// The majority mean leaves out the approving judge
+	majority := aggregate(verdicts, AggregateMajority)
+	if majority.Score != 65 || majority.Decision != DecisionRevise {
+		t.Errorf("aggregate(majority) = %v, %q, want 65 and revise", majority.Score, majority.Decision)
+	}
+	if !strings.HasPrefix(majority.Summary(), "多数派均值评分 65.0") {
+		t.Errorf("Summary() = %q", majority.Summary())
+	}
+	if split := aggregate([]JudgeVerdict{verdict(80, DecisionApprove), verdict(30, DecisionReject)}, AggregateMajority); split.Score != 55 {
+		t.Errorf("aggregate(majority) of a tie = %v, want the mean of all", split.Score)
+	}
+
+	// A split vote has no majority decision; the agreement is the top share
+	ties := []struct {
+		name      string
+		verdicts  []JudgeVerdict
+		agreement float64
+		summary   string
+	}{
+		{"approve and reject", []JudgeVerdict{verdict(80, DecisionApprove), verdict(30, DecisionReject)},
+			0.5, "多数结论：平票（通过 1、驳回 1，共 2 位裁判）"},
+		{"two against two", []JudgeVerdict{verdict(80, DecisionApprove), verdict(90, DecisionApprove), verdict(30, DecisionReject), verdict(20, DecisionReject)},
+			0.5, "多数结论：平票（通过 2、驳回 2，共 4 位裁判）"},
+		{"two, one, two", []JudgeVerdict{verdict(80, DecisionApprove), verdict(90, DecisionApprove), verdict(50, DecisionRevise), verdict(30, DecisionReject), verdict(20, DecisionReject)},
+			0.4, "多数结论：平票（通过 2、需修改 1、驳回 2，共 5 位裁判）"},
+	}
+	for _, tt := range ties {
+		tie := aggregate(tt.verdicts, AggregateMean)
+		if !tie.Tie() || tie.Decision != "" || tie.Agreement != tt.agreement {
+			t.Errorf("%s: decision = %q (tie %v, agreement %v), want a tie at %v", tt.name, tie.Decision, tie.Tie(), tie.Agreement, tt.agreement)
+		}
+		if !strings.HasSuffix(tie.Summary(), tt.summary) {
+			t.Errorf("%s: Summary() = %q, want suffix %q", tt.name, tie.Summary(), tt.summary)
+		}
+		if !strings.Contains(tie.headline(), "【结论：平票】") {
+			t.Errorf("%s: headline() = %q", tt.name, tie.headline())
+		}
+	}
+	// A plurality decides without ties
+	if two := aggregate([]JudgeVerdict{verdict(80, DecisionApprove), verdict(40, DecisionRevise), verdict(45, DecisionRevise)}, AggregateMean); two.Tie() || two.Decision != DecisionRevise {
+		t.Errorf("aggregate() = %q, want revise by 2/3", two.Decision)
+	}
+
+	none := aggregate([]JudgeVerdict{{OneLiner: "unparsed"}}, AggregateMean)
+	if none.Judges != 0 || none.Decision != "" || none.Tie() || !strings.Contains(none.headline(), "没有裁判") {
+		t.Errorf("aggregate() without scores = %+v, %q", none, none.headline())
+	}
+}
+
+// judgesServer answers debaters like roundsServer and each judge with
+// verdicts[model]; the chief judge's model is "chief". It records the
+// judges' and the chief judge's requests by model.
+func judgesServer(t *testing.T, verdicts map[string]string, requests map[string][]llm.Message) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model    string        `json:"model"`
+			Messages []llm.Message `json:"messages"`
+			Stream   bool          `json:"stream"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		content, judging := verdicts[req.Model]
+		if judging {
+			mu.Lock()
+			requests[req.Model] = req.Messages
+			mu.Unlock()
+		} else {
+			content = "## 💡 One-Liner\n观点\n\n## 📝 Full Argument\n论述"
+		}
+		if !req.Stream {
+			answer, _ := json.Marshal(content)
+			fmt.Fprintf(w, `{"choices":[{"message":{"content":%s},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":5}}`, answer)
+			return
+		}
+		chunk, _ := json.Marshal(map[string]any{
+			"choices": []map[string]any{{"delta": map[string]string{"content": content}, "finish_reason": "stop"}},
+		})
+		fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", chunk)
+	}))
+}
+
+// judgeVerdict is a Markdown verdict with score and decision
+func judgeVerdict(score int, decision, reason string) string {
+	return fmt.Sprintf("## 💡 One-Liner\n【评分: %d/100】 【结论：%s】 %s\n\n## 📝 Full Verdict\n%s 全文", score, decision, reason, reason)
+}
+
+// judgePanelConfig is openAITestConfig with a judge panel of models
+func judgePanelConfig(baseURL string, models ...string) *config.Config {
+	cfg := openAITestConfig(baseURL)
+	for _, model := range models {
+		judge := cfg.JudgeRole
+		judge.Model = model
+		cfg.Judges = append(cfg.Judges, judge)
+	}
+	return cfg
+}
+
+func TestExecutor_Execute_JudgePanel(t *testing.T) {
+	requests := make(map[string][]llm.Message)
+	server := judgesServer(t, map[string]string{
+		"judge-a": judgeVerdict(60, "需修改", "成本不明"),
+		"judge-b": judgeVerdict(90, "通过", "收益清晰"),
+		"judge-c": judgeVerdict(70, "需修改", "需要试点"),
+	}, requests)
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	executor := NewExecutor(judgePanelConfig(server.URL+"/v1", "judge-a", "judge-b", "judge-c"))
+	executor.SetAggregation(AggregateMedian)
+	var mu sync.Mutex
+	ruled := make(map[int]string)
+	executor.SetJudgeVerdictCallback(func(i int, v JudgeVerdict) {
+		mu.Lock()
+		defer mu.Unlock()
+		ruled[i] = v.Model.Model
+	})
+	var streamed []string
+	collect := func(s string, done bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !done {
+			streamed = append(streamed, s)
+		}
+	}
+	executor.SetStream(collect, collect, collect)
+
+	result, err := executor.Execute(context.Background(), "material")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(result.Verdicts) != 3 || result.Verdicts[1].Score != 90 || result.Verdicts[1].Decision != DecisionApprove || result.Verdicts[2].Model.Model != "judge-c" {
+		t.Errorf("Verdicts = %+v, want each judge's verdict in order", result.Verdicts)
+	}
+	if len(ruled) != 3 || ruled[0] != "judge-a" {
+		t.Errorf("verdict callback got %v, want every judge once", ruled)
+	}
+	for model, messages := range requests {
+		if !strings.Contains(messages[0].Content, "首席裁决官") || !strings.Contains(messages[1].Content, "【正方观点】") {
+			t.Errorf("%s did not get the Judge's input", model)
+		}
+	}
+
+	c := result.Consensus
+	if c == nil || c.Score != 70 || c.Decision != DecisionRevise || c.MinScore != 60 || c.MaxScore != 90 {
+		t.Fatalf("Consensus = %+v, want median 70 and revise", c)
+	}
+	if result.Score != 70 || result.Decision != DecisionRevise {
+		t.Errorf("Score, Decision = %d, %q; want the aggregate", result.Score, result.Decision)
+	}
+	if !strings.HasPrefix(result.VerdictOneLiner, "【评分: 70/100】 【结论：需修改】 中位数评分 70.0") || !strings.Contains(result.VerdictFullBody, "| 2 | openai/judge-b | 90 | 通过 |") {
+		t.Errorf("verdict = %q\n%s", result.VerdictOneLiner, result.VerdictFullBody)
+	}
+	// The streamed debater answers report no usage, so the total is the judges'
+	if result.JudgeModel.Provider != "" || result.TotalUsage().PromptTokens != 30 {
+		t.Errorf("JudgeModel = %v, total usage = %+v; want no Judge and every judge counted", result.JudgeModel, result.TotalUsage())
+	}
+	if !strings.Contains(strings.Join(streamed, "\n"), result.VerdictOneLiner) {
+		t.Errorf("streamed = %q, want the aggregate verdict", streamed)
+	}
+
+	report, err := os.ReadFile(result.ReportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	for _, want := range []string{
+		"· Judge 1 openai/judge-a · Judge 2 openai/judge-b · Judge 3 openai/judge-c\n",
+		"## ⚖️ Judge Panel\n> 中位数评分 70.0",
+		"### ⚖️ Judge 2 · openai/judge-b\n> 【评分: 90/100】 【结论：通过】 收益清晰",
+		"| Judge 3 | openai/judge-c | 10 |",
+	} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+	if strings.Contains(string(report), "| Judge |") || strings.Contains(string(report), "Chief Judge") {
+		t.Error("report lists a Judge that did not rule")
+	}
+}
+
+func TestExecutor_Execute_JudgePanelFailures(t *testing.T) {
+	requests := make(map[string][]llm.Message)
+	server := judgesServer(t, map[string]string{
+		"judge-a": judgeVerdict(60, "需修改", "成本不明"),
+		"judge-c": judgeVerdict(80, "通过", "收益清晰"),
+		"chief":   judgeVerdict(70, "需修改", "先试点"),
+	}, requests)
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	// Nothing listens on port 1, so judge-b cannot connect
+	cfg := judgePanelConfig(server.URL+"/v1", "judge-a", "judge-b", "judge-c")
+	cfg.Judges[1].BaseURL = "http://127.0.0.1:1/v1"
+	cfg.JudgeRole.Model = "chief"
+	cfg.ChiefJudge = true
+	executor := NewExecutor(cfg)
+	var mu sync.Mutex
+	var failed []int
+	executor.SetJudgeVerdictCallback(func(i int, v JudgeVerdict) {
+		mu.Lock()
+		defer mu.Unlock()
+		if v.Err != nil {
+			failed = append(failed, i)
+		}
+	})
+
+	result, err := executor.Execute(context.Background(), "material")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if v := result.Verdicts[1]; v.Err == nil || !strings.HasPrefix(v.Err.Error(), "judge 2: ") || v.Model.Model != "judge-b" {
+		t.Errorf("Verdicts[1] = %+v, want judge-b's error", v)
+	}
+	if len(failed) != 1 || failed[0] != 1 {
+		t.Errorf("verdict callback reported failures %v, want judge 2", failed)
+	}
+	c := result.Consensus
+	if c.Judges != 2 || c.Failed != 1 || c.Score != 70 || !strings.HasSuffix(c.Summary(), "；1 位裁判未能给出裁决") {
+		t.Errorf("Consensus = %+v (%q), want the two verdicts and one failure", c, c.Summary())
+	}
+	chief := requests["chief"][1].Content
+	if strings.Contains(chief, "裁判 2 · ") || !strings.Contains(chief, "裁判 3 · openai/judge-c") {
+		t.Errorf("chief input should leave out the failed judge:\n%s", chief)
+	}
+	report, err := os.ReadFile(result.ReportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if !strings.Contains(string(report), "### ⚖️ Judge 2 · openai/judge-b\n> ❌ 未能给出裁决：judge 2: ") {
+		t.Errorf("report does not show the failed judge:\n%s", report)
+	}
+
+	// The panel fails only when every judge does
+	for i := range cfg.Judges {
+		cfg.Judges[i].BaseURL = "http://127.0.0.1:1/v1"
+	}
+	_, err = NewExecutor(cfg).Execute(context.Background(), "material")
+	if err == nil || !strings.Contains(err.Error(), "judge 1: ") || !strings.Contains(err.Error(), "judge 3: ") {
+		t.Errorf("Execute() error = %v, want every judge's error", err)
+	}
+}
+
+func TestExecutor_Execute_ChiefJudge(t *testing.T) {
+	requests := make(map[string][]llm.Message)
+	server := judgesServer(t, map[string]string{
+		"judge-a": judgeVerdict(40, "驳回", "风险过高"),
+		"judge-b": judgeVerdict(80, "通过", "收益清晰"),
+		"chief":   judgeVerdict(65, "需修改", "分歧在风险评估"),
+	}, requests)
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	cfg := judgePanelConfig(server.URL+"/v1", "judge-a", "judge-b")
+	cfg.JudgeRole.Model = "chief"
+	cfg.ChiefJudge = true
+	result, err := NewExecutor(cfg).Execute(context.Background(), "material")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	chief := requests["chief"]
+	if !strings.Contains(chief[0].Content, "主审裁决官") {
+		t.Errorf("chief system prompt = %q", chief[0].Content)
+	}
+	for _, want := range []string{"material", "均值评分 60.0（标准差 20.0，区间 40-80）；多数结论：平票（通过 1、驳回 1，共 2 位裁判）", "**【裁判 1 · openai/judge-a】**：\n【评分: 40/100】", "风险过高 全文", "收益清晰 全文"} {
+		if !strings.Contains(chief[1].Content, want) {
+			t.Errorf("chief input is missing %q:\n%s", want, chief[1].Content)
+		}
+	}
+
+	if !strings.Contains(result.VerdictOneLiner, "分歧在风险评估") || result.JudgeModel.Model != "chief" {
+		t.Errorf("verdict = %q by %v, want the chief judge's", result.VerdictOneLiner, result.JudgeModel)
+	}
+	if result.Score != 65 || result.Decision != DecisionRevise || result.Consensus.Score != 60 {
+		t.Errorf("Score = %d, Decision = %q, Consensus = %+v; want the chief's score and the aggregate kept", result.Score, result.Decision, result.Consensus)
+	}
+
+	report, err := os.ReadFile(result.ReportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	for _, want := range []string{"· Chief Judge openai/chief\n", "| Chief Judge | openai/chief |", "## ⚖️ Full Adjudication\n分歧在风险评估 全文"} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package debate
+
+// DebaterResult is what one debater produced: its opening statement, and
+// the model, usage, reasoning and tool calls of all its turns
+type DebaterResult struct {
+	Side      Side     // SidePro, SideCon or the persona's name
+	OneLiner  string   // 一句话观点
+	FullBody  string   // 完整论述
+	Arguments []string // 论据, structured mode only
+	Model     ModelInfo
+	Usage     RoleUsage
+	Reasoning string
+	ToolCalls []ToolCallRecord
+	Truncated bool // the opening statement is cut off
+}
+
+// Debaters returns every debater's result in speaking order: the panel's
+// personas, or Pro and Con from their fields
+func (r *Result) Debaters() []DebaterResult {
+	if len(r.Panel) > 0 {
+		return r.Panel
+	}
+	return []DebaterResult{
+		{
+			Side: SidePro, OneLiner: r.ProOneLiner, FullBody: r.ProFullBody, Arguments: r.ProArguments,
+			Model: r.ProModel, Usage: r.ProUsage, Reasoning: r.ProReasoning, ToolCalls: r.ProToolCalls, Truncated: r.ProTruncated,
+		},
+		{
+			Side: SideCon, OneLiner: r.ConOneLiner, FullBody: r.ConFullBody, Arguments: r.ConArguments,
+			Model: r.ConModel, Usage: r.ConUsage, Reasoning: r.ConReasoning, ToolCalls: r.ConToolCalls, Truncated: r.ConTruncated,
+		},
+	}
+}
+
+// addDebater stores d in the Pro or Con fields, or appends it to the panel
+func (r *Result) addDebater(d DebaterResult) {
+	switch d.Side {
+	case SidePro:
+		r.ProOneLiner, r.ProFullBody, r.ProArguments = d.OneLiner, d.FullBody, d.Arguments
+		r.ProModel, r.ProUsage, r.ProReasoning, r.ProToolCalls, r.ProTruncated = d.Model, d.Usage, d.Reasoning, d.ToolCalls, d.Truncated
+	case SideCon:
+		r.ConOneLiner, r.ConFullBody, r.ConArguments = d.OneLiner, d.FullBody, d.Arguments
+		r.ConModel, r.ConUsage, r.ConReasoning, r.ConToolCalls, r.ConTruncated = d.Model, d.Usage, d.Reasoning, d.ToolCalls, d.Truncated
+	default:
+		r.Panel = append(r.Panel, d)
+	}
+}
+
+// personaMarks tell panel personas apart in the report, in turn
+var personaMarks = []string{"🔵", "🟣", "🟠", "🟢", "🔴", "🟤"}
+
+// reportTitles names the i-th debater in the report: a short name for the
+// one-liner and the usage table, its mark, and its argument heading
+func (d DebaterResult) reportTitles(i int) (short, mark, heading string) {
+	switch d.Side {
+	case SidePro:
+		return "Pro", "🟢", "Affirmative"
+	case SideCon:
+		return "Con", "🔴", "Negative"
+	}
+	return string(d.Side), personaMarks[i%len(personaMarks)], string(d.Side)
+}
@@ -0,0 +1,125 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/config"
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+// panelTestConfig is openAITestConfig with personas debating
+func panelTestConfig(baseURL string, names ...string) *config.Config {
+	cfg := openAITestConfig(baseURL)
+	for _, name := range names {
+		cfg.Personas = append(cfg.Personas, config.Persona{Name: name, Prompt: "关注" + name, Role: cfg.ProRole})
+	}
+	return cfg
+}
+
+func TestExecutor_Execute_Panel(t *testing.T) {
+	requests := make(map[string][][]llm.Message)
+	server := roundsServer(t, requests)
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	executor := NewExecutor(panelTestConfig(server.URL+"/v1", "CFO", "Security", "Ops"))
+	executor.SetRounds(2)
+	var mu sync.Mutex
+	var streamed []string
+	executor.SetPanelStream(func(name, content string, done bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !done {
+			streamed = append(streamed, name+": "+content)
+		}
+	}, func(string, bool) {})
+
+	result, err := executor.Execute(context.Background(), "material")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(requests["pro"]) != 0 || len(requests["con"]) != 0 {
+		t.Errorf("Pro/Con were asked %d/%d times in a panel debate", len(requests["pro"]), len(requests["con"]))
+	}
+	var opinions []string
+	for _, d := range result.Debaters() {
+		opinions = append(opinions, fmt.Sprintf("%s %s", d.Side, d.OneLiner))
+	}
+	if got, want := strings.Join(opinions, ", "), "CFO CFO 1, Security Security 1, Ops Ops 1"; got != want {
+		t.Errorf("Debaters() = %s, want %s", got, want)
+	}
+	if len(result.Turns) != 6 || result.Turns[4].Side != "Security" || result.Turns[4].OneLiner != "Security 2" {
+		t.Errorf("Turns = %+v, want two rounds of three", result.Turns)
+	}
+	if result.ProOneLiner != "" || result.ProUsage.PromptTokens != 0 {
+		t.Error("Pro fields are set in a panel debate")
+	}
+
+	sort.Strings(streamed)
+	if got, want := strings.Join(streamed, ", "), "CFO: CFO 1, CFO: CFO 2, Ops: Ops 1, Ops: Ops 2, Security: Security 1, Security: Security 2"; got != want {
+		t.Errorf("streamed = %s, want %s", got, want)
+	}
+
+	// Rebuttals answer every other persona, not a single opponent
+	rebuttal := requests["Ops"][1][3].Content
+	if !strings.Contains(rebuttal, "**【CFO】**：\nCFO body 1") || !strings.Contains(rebuttal, "**【Security】**：\nSecurity body 1") || strings.Contains(rebuttal, "Ops body") {
+		t.Errorf("Ops rebuttal prompt = %q, want CFO's and Security's opinions", rebuttal)
+	}
+
+	// The Judge weighs every persona's statements
+	judge := requests["judge"][0]
+	if !strings.Contains(judge[0].Content, "评审小组") {
+		t.Error("Judge does not get the panel adjudicator prompt")
+	}
+	last := 0
+	for _, want := range []string{"第 1 轮 · CFO立论", "CFO body 1", "第 1 轮 · Ops立论", "第 2 轮 · Security反驳", "Ops body 2"} {
+		i := strings.Index(judge[1].Content, want)
+		if i < last {
+			t.Errorf("judge input is missing %q or has it out of order:\n%s", want, judge[1].Content)
+		}
+		last = i
+	}
+
+	report, err := os.ReadFile(result.ReportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	for _, want := range []string{
+		"> Models: CFO openai/gpt-4o · Security openai/gpt-4o · Ops openai/gpt-4o · Judge openai/gpt-4o",
+		"## 🟣 Security Argument (Full)\nSecurity body 1",
+		"### 🟠 Ops Rebuttal\n> Ops 2",
+		"| Security | openai/gpt-4o |",
+	} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+	if strings.Contains(string(report), "Affirmative") {
+		t.Error("report has a Pro section in a panel debate")
+	}
+}
+
+func TestExecutor_Execute_PanelOneRound(t *testing.T) {
+	requests := make(map[string][][]llm.Message)
+	server := roundsServer(t, requests)
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	result, err := NewExecutor(panelTestConfig(server.URL+"/v1", "CFO", "Ops")).Execute(context.Background(), "material")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(result.Panel) != 2 || result.Panel[1].FullBody != "Ops body 1" || result.Panel[1].Model.Model != "gpt-4o" {
+		t.Errorf("Panel = %+v, want both opinions", result.Panel)
+	}
+	if judge := requests["judge"][0][1].Content; !strings.Contains(judge, "**【CFO】**：\nCFO body 1") || strings.Contains(judge, "第 1 轮") {
+		t.Errorf("judge input = %q, want the opinions labelled by persona", judge)
+	}
+}
@@ -0,0 +1,248 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hrygo/dialecta/internal/config"
+	"github.com/hrygo/dialecta/internal/llm"
+	"github.com/hrygo/dialecta/internal/prompt"
+)
+
+// Side identifies a debater: Pro, Con, or a panel persona by name
+type Side string
+
+const (
+	SidePro Side = "pro" // 正方
+	SideCon Side = "con" // 反方
+)
+
+// Name returns the side as shown to users: 正方, 反方 or the persona's name
+func (s Side) Name() string {
+	switch s {
+	case SidePro:
+		return "正方"
+	case SideCon:
+		return "反方"
+	}
+	return string(s)
+}
+
+// Turn is one statement of a debater: the opening statement in round 1, and
+// a rebuttal of the others' previous statements in later rounds
+type Turn struct {
+	Round     int
+	Side      Side
+	OneLiner  string   // 一句话观点
+	Body      string   // 完整论述
+	Arguments []string // 论据, structured mode only
+	Truncated bool     // still cut off after the role's continuations
+}
+
+// Label names the turn as in the Judge's transcript, e.g. "第 2 轮 · 正方反驳"
+func (t Turn) Label() string {
+	kind := "立论"
+	if t.Round > 1 {
+		kind = "反驳"
+	}
+	return fmt.Sprintf("第 %d 轮 · %s%s", t.Round, t.Side.Name(), kind)
+}
+
+// Rounds returns how many rounds the debate had
+func (r *Result) Rounds() int {
+	rounds := 0
+	for _, turn := range r.Turns {
+		rounds = max(rounds, turn.Round)
+	}
+	return rounds
+}
+
+// transcript returns every turn's full argument for the Judge, labelled
+// with its round when there were rebuttals
+func (r *Result) transcript() []prompt.Statement {
+	statements := make([]prompt.Statement, len(r.Turns))
+	for i, turn := range r.Turns {
+		speaker := turn.Side.Name()
+		if r.Rounds() > 1 {
+			speaker = turn.Label()
+		}
+		statements[i] = prompt.Statement{Speaker: speaker, Content: turn.Body}
+	}
+	return statements
+}
+
+// debater holds one side's client and conversation across rounds
+type debater struct {
+	side     Side
+	label    string // names the debater in errors
+	role     config.RoleConfig
+	onStream func(string, bool)
+	client   llm.Client
+
+	messages []llm.Message // the conversation that produced answer
+	answer   string        // the last answer, as the model wrote it
+
+	reasoning strings.Builder
+	toolCalls []ToolCallRecord
+}
+
+// newDebaters creates Pro and Con, or the panel's personas, with their
+// clients and opening statement's messages. On error no client is left open.
+func (e *Executor) newDebaters(material string) ([]*debater, error) {
+	debaters := []*debater{
+		{side: SidePro, label: "affirmative", role: e.cfg.ProRole, onStream: e.onPro, messages: prompt.BuildAffirmativeMessages(material)},
+		{side: SideCon, label: "negative", role: e.cfg.ConRole, onStream: e.onCon, messages: prompt.BuildNegativeMessages(material)},
+	}
+	if e.panel() {
+		debaters = make([]*debater, len(e.cfg.Personas))
+		for i, p := range e.cfg.Personas {
+			debaters[i] = &debater{side: Side(p.Name), label: "persona " + p.Name, role: p.Role, messages: prompt.BuildPersonaMessages(p.Name, p.Prompt, material)}
+			if e.onPersona != nil {
+				debaters[i].onStream = func(content string, done bool) { e.onPersona(p.Name, content, done) }
+			}
+		}
+	}
+
+	for i, d := range debaters {
+		client, err := newRoleClient(e.roleConfig(d.role, argumentFormat))
+		if err != nil {
+			for _, created := range debaters[:i] {
+				created.client.Close()
+			}
+			name := string(d.side)
+			if e.panel() {
+				name = d.label
+			}
+			return nil, fmt.Errorf("create %s client: %w", name, err)
+		}
+		d.client = client
+		if e.structured {
+			d.messages = prompt.WithJSONOutput(d.messages, prompt.ArgumentJSONFormat)
+		}
+	}
+	return debaters, nil
+}
+
+// speak gets d's statement for round: the opening statement in round 1,
+// otherwise a rebuttal of others, the other debaters' previous statements.
+// One-liners reach d's stream callback as in a one-round debate.
+func (e *Executor) speak(ctx context.Context, d *debater, round int, others []Turn) (Turn, error) {
+	turn := Turn{Round: round, Side: d.side}
+	if round > 1 {
+		if e.panel() {
+			statements := make([]prompt.Statement, len(others))
+			for i, other := range others {
+				statements[i] = prompt.Statement{Speaker: other.Side.Name(), Content: other.Body}
+			}
+			d.messages = prompt.BuildPanelRebuttalMessages(d.messages, d.answer, statements)
+		} else {
+			d.messages = prompt.BuildRebuttalMessages(d.messages, d.answer, others[0].Body)
+		}
+	}
+	ctx = llm.WithReasoning(ctx, func(s string) { d.reasoning.WriteString(s) })
+
+	parser := NewStreamParser("## 📝 Full Argument")
+	var (
+		full  string
+		calls []ToolCallRecord
+		err   error
+	)
+	switch {
+	case e.structured:
+		var answer *structuredAnswer
+		full, calls, turn.Truncated, err = e.answer(ctx, d.client, d.messages, d.role.MaxContinuations)
+		if err == nil {
+			answer, err = decodeAnswer(full, argumentFormat.Schema)
+		}
+		if err == nil {
+			turn.OneLiner = answer.OneLiner
+			turn.Arguments = answer.Arguments
+			turn.Body = answer.body()
+		}
+		if e.stream {
+			notifyAnswer(d.onStream, turn.OneLiner)
+		}
+	case e.usesTools():
+		full, calls, turn.Truncated, err = e.answer(ctx, d.client, d.messages, d.role.MaxContinuations)
+		if err == nil {
+			parser.Feed(full)
+			turn.OneLiner, turn.Body = parseArgument(parser)
+		}
+		if e.stream {
+			notifyAnswer(d.onStream, turn.OneLiner)
+		}
+	case e.stream && d.onStream != nil:
+		// Continuations stream into the same parser, so the One-Liner and
+		// Full Argument are parsed from the stitched answer
+		full, turn.Truncated, err = continueAnswer(ctx, d.messages, d.role.MaxContinuations, func(ctx context.Context, messages []llm.Message) (string, error) {
+			return d.client.ChatStream(ctx, messages, func(chunk string) {
+				if oneLiner, found := parser.Feed(chunk); found {
+					// Notify CLI with the One-Liner ONLY once
+					d.onStream(oneLiner, false)
+				}
+			})
+		})
+		turn.OneLiner, turn.Body = parseArgument(parser)
+		d.onStream("", true) // Signal done
+	default:
+		full, turn.Truncated, err = continueAnswer(ctx, d.messages, d.role.MaxContinuations, d.client.Chat)
+		if err == nil {
+			parser.Feed(full)
+			turn.OneLiner, turn.Body = parseArgument(parser)
+		}
+	}
+
+	d.answer = full
+	d.toolCalls = append(d.toolCalls, calls...)
+	return turn, err
+}
+
+// result reports d's opening statement with the model, usage, reasoning and
+// tool calls of all its turns
+func (d *debater) result(opening Turn) DebaterResult {
+	model := usedModel(d.client, d.role)
+	return DebaterResult{
+		Side:      d.side,
+		OneLiner:  opening.OneLiner,
+		FullBody:  opening.Body,
+		Arguments: opening.Arguments,
+		Model:     model,
+		Usage:     roleUsage(d.client, model),
+		Reasoning: d.reasoning.String(),
+		ToolCalls: d.toolCalls,
+		Truncated: opening.Truncated,
+	}
+}
+
+// parseArgument finalizes parser and returns the One-Liner and Full
+// Argument, or the whole answer when it has no Full Argument section
+func parseArgument(parser *StreamParser) (oneLiner, body string) {
+	parser.Finalize()
+	if parser.fullBody == "" {
+		return parser.oneLiner, parser.buffer.String()
+	}
+	return parser.oneLiner, parser.fullBody
+}
+
+// rebuttalSection renders the report sections of rounds after the opening
+// statements, or nothing for a one-round debate
+func rebuttalSection(r *Result) string {
+	debaters := r.Debaters()
+	var b strings.Builder
+	for i, turn := range r.Turns {
+		if turn.Round == 1 {
+			continue
+		}
+		n := i % len(debaters)
+		if n == 0 {
+			fmt.Fprintf(&b, "## 🔁 Round %d · Rebuttals\n\n", turn.Round)
+		}
+		_, mark, heading := debaters[n].reportTitles(n)
+		fmt.Fprintf(&b, "### %s %s Rebuttal\n> %s\n\n%s\n%s\n", mark, heading, turn.OneLiner, turn.Body, truncationNote(turn.Truncated))
+		if n == len(debaters)-1 {
+			b.WriteString("---\n\n")
+		}
+	}
+	return b.String()
+}
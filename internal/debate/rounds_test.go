@@ -0,0 +1,155 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+// roundsServer answers, streamed or not, with the side and the round, which
+// it counts from the conversation length. It records each request's messages
+// by side ("pro", "con", "judge" or a panel persona's name) in arrival order.
+func roundsServer(t *testing.T, requests map[string][][]llm.Message) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []llm.Message `json:"messages"`
+			Stream   bool          `json:"stream"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		side := "pro"
+		switch system := req.Messages[0].Content; {
+		case strings.Contains(system, "首席裁决官"):
+			side = "judge"
+		case strings.Contains(system, "批判性思维专家"):
+			side = "con"
+		case strings.HasPrefix(system, "### Role\n你是评审小组中的【"):
+			name := strings.TrimPrefix(system, "### Role\n你是评审小组中的【")
+			side = name[:strings.Index(name, "】")]
+		}
+		mu.Lock()
+		requests[side] = append(requests[side], req.Messages)
+		mu.Unlock()
+
+		content := "## 💡 One-Liner\n裁决\n\n## 📝 Full Verdict\n裁决全文"
+		if side != "judge" {
+			round := len(req.Messages) / 2
+			content = fmt.Sprintf("## 💡 One-Liner\n%s %d\n\n## 📝 Full Argument\n%s body %d", side, round, side, round)
+		}
+		if !req.Stream {
+			answer, _ := json.Marshal(content)
+			fmt.Fprintf(w, `{"choices":[{"message":{"content":%s},"finish_reason":"stop"}]}`, answer)
+			return
+		}
+		chunk, _ := json.Marshal(map[string]any{
+			"choices": []map[string]any{{"delta": map[string]string{"content": content}, "finish_reason": "stop"}},
+		})
+		fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", chunk)
+	}))
+}
+
+func TestExecutor_Execute_Rounds(t *testing.T) {
+	requests := make(map[string][][]llm.Message)
+	server := roundsServer(t, requests)
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	executor := NewExecutor(openAITestConfig(server.URL + "/v1"))
+	executor.SetRounds(3)
+	var mu sync.Mutex
+	var events []string
+	collect := func(s string, done bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !done {
+			events = append(events, s)
+		}
+	}
+	executor.SetStream(collect, collect, collect)
+	executor.SetRoundStartCallback(func(round, rounds int) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, fmt.Sprintf("round %d/%d", round, rounds))
+	})
+
+	result, err := executor.Execute(context.Background(), "material")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var turns []string
+	for _, turn := range result.Turns {
+		turns = append(turns, fmt.Sprintf("%d %s %s", turn.Round, turn.Side, turn.OneLiner))
+	}
+	if got, want := strings.Join(turns, ", "), "1 pro pro 1, 1 con con 1, 2 pro pro 2, 2 con con 2, 3 pro pro 3, 3 con con 3"; got != want {
+		t.Errorf("Turns = %s, want %s", got, want)
+	}
+	if result.Rounds() != 3 || result.ProOneLiner != "pro 1" || result.ConFullBody != "con body 1" {
+		t.Errorf("Rounds() = %d, opening = %q / %q; want the opening statements", result.Rounds(), result.ProOneLiner, result.ConFullBody)
+	}
+
+	// Each round starts once the previous one is over, and is labelled
+	for i, want := range map[int]string{0: "round 1/3", 3: "round 2/3", 6: "round 3/3", 9: "裁决"} {
+		if i >= len(events) || events[i] != want {
+			t.Errorf("events = %q, want %q at %d", events, want, i)
+		}
+	}
+
+	// Rebuttals continue the side's conversation with the other's last statement
+	con := requests["con"][2]
+	if len(con) != 6 || con[4].Role != "assistant" || !strings.Contains(con[4].Content, "con body 2") || !strings.Contains(con[5].Content, "pro body 2") {
+		t.Errorf("con round 3 messages = %+v, want its own round 2 answer and Pro's to rebut", con)
+	}
+
+	// The Judge sees the whole transcript in speaking order
+	judge := requests["judge"][0][1].Content
+	last := 0
+	for _, want := range []string{"第 1 轮 · 正方立论", "pro body 1", "第 1 轮 · 反方立论", "第 2 轮 · 正方反驳", "pro body 2", "第 3 轮 · 反方反驳", "con body 3"} {
+		i := strings.Index(judge, want)
+		if i < last {
+			t.Errorf("judge transcript is missing %q or has it out of order:\n%s", want, judge)
+		}
+		last = i
+	}
+
+	report, err := os.ReadFile(result.ReportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	for _, want := range []string{"## 🔁 Round 2 · Rebuttals", "### 🔴 Negative Rebuttal\n> con 3\n\ncon body 3"} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
+
+func TestExecutor_Execute_OneRound(t *testing.T) {
+	requests := make(map[string][][]llm.Message)
+	server := roundsServer(t, requests)
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	executor := NewExecutor(openAITestConfig(server.URL + "/v1"))
+	executor.SetRounds(0)
+	executor.SetRoundStartCallback(func(int, int) { t.Error("round start callback called for a one-round debate") })
+	result, err := executor.Execute(context.Background(), "material")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(result.Turns) != 2 || len(requests["pro"]) != 1 || len(requests["con"]) != 1 {
+		t.Errorf("Turns = %+v, requests = %d pro / %d con; want the opening statements only", result.Turns, len(requests["pro"]), len(requests["con"]))
+	}
+	if judge := requests["judge"][0][1].Content; !strings.Contains(judge, "【正方观点】") || strings.Contains(judge, "第 1 轮") {
+		t.Errorf("judge input = %q, want the one-round layout", judge)
+	}
+}
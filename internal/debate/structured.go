@@ -0,0 +1,118 @@
+package debate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+// Decision values of a structured verdict
+const (
+	DecisionApprove = "approve" // 通过
+	DecisionReject  = "reject"  // 驳回
+	DecisionRevise  = "revise"  // 需修改
+)
+
+// decisionLabels are the verdict labels used by the Markdown prompts
+var decisionLabels = map[string]string{
+	DecisionApprove: "通过",
+	DecisionReject:  "驳回",
+	DecisionRevise:  "需修改",
+}
+
+// argumentFormat is the JSON answer of the Pro and Con roles in structured mode
+var argumentFormat = &llm.ResponseFormat{
+	Type: llm.ResponseJSONSchema,
+	Name: "debate_argument",
+	Schema: &llm.Schema{
+		Type: "object",
+		Properties: map[string]*llm.Schema{
+			"one_liner": {Type: "string", Description: "核心观点，不超过100字"},
+			"arguments": {Type: "array", Description: "3-5 条论据", Items: &llm.Schema{Type: "string"}},
+		},
+		Required: []string{"one_liner", "arguments"},
+	},
+}
+
+// verdictFormat is the JSON answer of the Judge in structured mode
+var verdictFormat = &llm.ResponseFormat{
+	Type: llm.ResponseJSONSchema,
+	Name: "debate_verdict",
+	Schema: &llm.Schema{
+		Type: "object",
+		Properties: map[string]*llm.Schema{
+			"one_liner": {Type: "string", Description: "裁决理由，不超过100字"},
+			"arguments": {Type: "array", Description: "裁决依据", Items: &llm.Schema{Type: "string"}},
+			"score":     {Type: "integer", Description: "综合评分 0-100"},
+			"decision":  {Type: "string", Enum: []string{DecisionApprove, DecisionReject, DecisionRevise}},
+		},
+		Required: []string{"one_liner", "arguments", "score", "decision"},
+	},
+}
+
+// structuredAnswer is a role's decoded JSON answer; Score and Decision are
+// only set by the Judge
+type structuredAnswer struct {
+	OneLiner  string   `json:"one_liner"`
+	Arguments []string `json:"arguments"`
+	Score     int      `json:"score"`
+	Decision  string   `json:"decision"`
+}
+
+// decodeAnswer validates text against schema and decodes it. Providers that
+// only follow the prompt sometimes wrap JSON in a code fence, which is
+// stripped first.
+func decodeAnswer(text string, schema *llm.Schema) (*structuredAnswer, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+	if err := schema.Validate([]byte(text)); err != nil {
+		return nil, fmt.Errorf("invalid structured answer: %w", err)
+	}
+	var answer structuredAnswer
+	if err := json.Unmarshal([]byte(text), &answer); err != nil {
+		return nil, fmt.Errorf("invalid structured answer: %w", err)
+	}
+	if answer.Score < 0 || answer.Score > 100 {
+		return nil, fmt.Errorf("invalid structured answer: score %d out of range 0-100", answer.Score)
+	}
+	return &answer, nil
+}
+
+// body renders the arguments as the Markdown list used in reports and in
+// the Judge's input
+func (a *structuredAnswer) body() string {
+	var b strings.Builder
+	for i, arg := range a.Arguments {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, arg)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// headline is the verdict one-liner, prefixed with score and decision like
+// the Markdown prompt asks for
+func (a *structuredAnswer) headline() string {
+	return fmt.Sprintf("【评分: %d/100】 【结论：%s】 %s", a.Score, decisionLabels[a.Decision], a.OneLiner)
+}
+
+// verdictBody renders the Judge's answer for the report
+func (a *structuredAnswer) verdictBody() string {
+	return fmt.Sprintf("* **综合评分**：%d / 100\n* **裁决结论**：%s\n\n%s", a.Score, decisionLabels[a.Decision], a.body())
+}
+
+// notifyAnswer reports an answer that was not streamed (structured or tool
+// mode) through a stream callback: the one-liner if there is one, then done
+func notifyAnswer(cb func(string, bool), oneLiner string) {
+	if cb == nil {
+		return
+	}
+	if oneLiner != "" {
+		cb(oneLiner, false)
+	}
+	cb("", true)
+}
@@ -0,0 +1,169 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/config"
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+func TestDecodeAnswer(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		schema  *llm.Schema
+		want    structuredAnswer
+		wantErr string
+	}{
+		{
+			name:   "argument",
+			text:   `{"one_liner":"值得做","arguments":["市场大","团队强"]}`,
+			schema: argumentFormat.Schema,
+			want:   structuredAnswer{OneLiner: "值得做", Arguments: []string{"市场大", "团队强"}},
+		},
+		{
+			name:   "verdict in a code fence",
+			text:   "```json\n{\"one_liner\":\"需补充数据\",\"arguments\":[],\"score\":62,\"decision\":\"revise\"}\n```",
+			schema: verdictFormat.Schema,
+			want:   structuredAnswer{OneLiner: "需补充数据", Arguments: []string{}, Score: 62, Decision: DecisionRevise},
+		},
+		{name: "markdown", text: "## 💡 One-Liner\n值得做", schema: argumentFormat.Schema, wantErr: "invalid JSON"},
+		{name: "missing decision", text: `{"one_liner":"x","arguments":[],"score":50}`, schema: verdictFormat.Schema, wantErr: `"decision"`},
+		{name: "unknown decision", text: `{"one_liner":"x","arguments":[],"score":50,"decision":"通过"}`, schema: verdictFormat.Schema, wantErr: "not one of"},
+		{name: "score out of range", text: `{"one_liner":"x","arguments":[],"score":120,"decision":"approve"}`, schema: verdictFormat.Schema, wantErr: "out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeAnswer(tt.text, tt.schema)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("decodeAnswer() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeAnswer() error = %v", err)
+			}
+			if fmt.Sprint(*got) != fmt.Sprint(tt.want) {
+				t.Errorf("decodeAnswer() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+// structuredServer answers OpenAI chat requests by the response_format
+// schema name, recording the system prompts it received
+func structuredServer(t *testing.T, answers map[string]string, prompts *[]string) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+			Stream         bool `json:"stream"`
+			ResponseFormat struct {
+				JSONSchema struct {
+					Name string `json:"name"`
+				} `json:"json_schema"`
+			} `json:"response_format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if req.Stream {
+			t.Error("structured answers should not be streamed")
+		}
+		mu.Lock()
+		*prompts = append(*prompts, req.Messages[0].Content)
+		mu.Unlock()
+
+		content, _ := json.Marshal(answers[req.ResponseFormat.JSONSchema.Name])
+		fmt.Fprintf(w, `{"choices":[{"message":{"content":%s}}],"usage":{"prompt_tokens":10,"completion_tokens":5}}`, content)
+	}))
+}
+
+// openAITestConfig runs every role on an OpenAI-compatible test server
+func openAITestConfig(baseURL string) *config.Config {
+	cfg := config.New()
+	for _, role := range []*config.RoleConfig{&cfg.ProRole, &cfg.ConRole, &cfg.JudgeRole} {
+		role.Provider = llm.ProviderOpenAI
+		role.Model = "gpt-4o"
+		role.BaseURL = baseURL
+		role.Retry = llm.RetryPolicy{}
+	}
+	return cfg
+}
+
+func TestExecutor_Execute_Structured(t *testing.T) {
+	var prompts []string
+	server := structuredServer(t, map[string]string{
+		"debate_argument": `{"one_liner":"论点","arguments":["第一","第二"]}`,
+		"debate_verdict":  `{"one_liner":"正方胜出","arguments":["焦点"],"score":78,"decision":"approve"}`,
+	}, &prompts)
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	executor := NewExecutor(openAITestConfig(server.URL + "/v1"))
+	executor.SetStructured(true)
+	var mu sync.Mutex
+	var oneLiners []string
+	collect := func(s string, done bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !done {
+			oneLiners = append(oneLiners, s)
+		}
+	}
+	executor.SetStream(collect, collect, collect)
+
+	result, err := executor.Execute(context.Background(), "material")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.ProOneLiner != "论点" || len(result.ConArguments) != 2 || result.ProFullBody != "1. 第一\n2. 第二" {
+		t.Errorf("pro/con = %q %q %q", result.ProOneLiner, result.ConArguments, result.ProFullBody)
+	}
+	if result.Score != 78 || result.Decision != DecisionApprove || len(result.VerdictArguments) != 1 {
+		t.Errorf("verdict = score %d, decision %q, arguments %q", result.Score, result.Decision, result.VerdictArguments)
+	}
+	if want := "【评分: 78/100】 【结论：通过】 正方胜出"; result.VerdictOneLiner != want {
+		t.Errorf("VerdictOneLiner = %q, want %q", result.VerdictOneLiner, want)
+	}
+	if len(oneLiners) != 3 {
+		t.Errorf("stream callbacks got %q, want three one-liners", oneLiners)
+	}
+	for _, p := range prompts {
+		if !strings.Contains(p, "JSON") || strings.Contains(p, "## 💡 One-Liner") {
+			t.Errorf("system prompt should ask for JSON only:\n%s", p)
+		}
+	}
+	if result.ProUsage.TotalTokens() != 15 {
+		t.Errorf("ProUsage = %+v, want 15 tokens", result.ProUsage)
+	}
+}
+
+func TestExecutor_Execute_StructuredInvalid(t *testing.T) {
+	var prompts []string
+	server := structuredServer(t, map[string]string{
+		"debate_argument": `{"one_liner":"论点","arguments":["第一"]}`,
+		"debate_verdict":  `{"one_liner":"正方胜出","score":78}`,
+	}, &prompts)
+	defer server.Close()
+	t.Chdir(t.TempDir())
+
+	executor := NewExecutor(openAITestConfig(server.URL + "/v1"))
+	executor.SetStructured(true)
+	_, err := executor.Execute(context.Background(), "material")
+	if err == nil || !strings.Contains(err.Error(), "adjudicator: invalid structured answer") {
+		t.Errorf("Execute() error = %v, want an invalid structured answer", err)
+	}
+}
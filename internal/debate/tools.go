@@ -0,0 +1,129 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+// builtinTools are the tools that can be enabled by name (see --tools)
+var builtinTools = map[string]func() (llm.Tool, ToolHandler){
+	"calculator": calculatorTool,
+}
+
+// BuiltinToolNames lists the built-in tools, sorted
+func BuiltinToolNames() []string {
+	names := make([]string, 0, len(builtinTools))
+	for name := range builtinTools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinToolbox returns a toolbox with the named built-in tools
+func BuiltinToolbox(names []string) (*Toolbox, error) {
+	toolbox := NewToolbox()
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		newTool, ok := builtinTools[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown tool %q (available: %s)", name, strings.Join(BuiltinToolNames(), ", "))
+		}
+		toolbox.Register(newTool())
+	}
+	return toolbox, nil
+}
+
+// calculatorTool evaluates arithmetic, so debaters do not have to do sums
+// in their heads when arguing about costs and growth rates
+func calculatorTool() (llm.Tool, ToolHandler) {
+	tool := llm.Tool{
+		Name:        "calculator",
+		Description: "Evaluate an arithmetic expression with + - * / and parentheses, e.g. (1200-800)/800*100",
+		Parameters: &llm.Schema{
+			Type: "object",
+			Properties: map[string]*llm.Schema{
+				"expression": {Type: "string", Description: "the expression to evaluate"},
+			},
+			Required: []string{"expression"},
+		},
+	}
+	handler := func(ctx context.Context, arguments string) (string, error) {
+		var args struct {
+			Expression string `json:"expression"`
+		}
+		if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		return calculate(args.Expression)
+	}
+	return tool, handler
+}
+
+// calculate evaluates expr exactly and formats the result as a decimal
+func calculate(expr string) (string, error) {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", fmt.Errorf("invalid expression %q", expr)
+	}
+	v, err := evalArithmetic(node)
+	if err != nil {
+		return "", err
+	}
+	f, _ := constant.Float64Val(v)
+	return strconv.FormatFloat(f, 'g', -1, 64), nil
+}
+
+func evalArithmetic(node ast.Expr) (constant.Value, error) {
+	switch n := node.(type) {
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT {
+			return nil, fmt.Errorf("unsupported literal %s", n.Value)
+		}
+		return constant.ToFloat(constant.MakeFromLiteral(n.Value, n.Kind, 0)), nil
+	case *ast.ParenExpr:
+		return evalArithmetic(n.X)
+	case *ast.UnaryExpr:
+		if n.Op != token.ADD && n.Op != token.SUB {
+			return nil, fmt.Errorf("unsupported operator %s", n.Op)
+		}
+		x, err := evalArithmetic(n.X)
+		if err != nil {
+			return nil, err
+		}
+		return constant.UnaryOp(n.Op, x, 0), nil
+	case *ast.BinaryExpr:
+		switch n.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO:
+		default:
+			return nil, fmt.Errorf("unsupported operator %s", n.Op)
+		}
+		x, err := evalArithmetic(n.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := evalArithmetic(n.Y)
+		if err != nil {
+			return nil, err
+		}
+		if n.Op == token.QUO && constant.Sign(y) == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return constant.BinaryOp(x, n.Op, y), nil
+	default:
+		return nil, fmt.Errorf("unsupported expression")
+	}
+}
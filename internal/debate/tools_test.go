@@ -0,0 +1,57 @@
+package debate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr string
+	}{
+		{"1+2*3", "7", ""},
+		{"(1200-800)/800*100", "50", ""},
+		{"7/2", "3.5", ""},
+		{"-3 + +1", "-2", ""},
+		{"1/3*3", "1", ""},
+		{"1/0", "", "division by zero"},
+		{"2^3", "", "unsupported operator"},
+		{"x+1", "", "unsupported expression"},
+		{"\"a\"", "", "unsupported literal"},
+		{"1+", "", "invalid expression"},
+	}
+	for _, tt := range tests {
+		got, err := calculate(tt.expr)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("calculate(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("calculate(%q) = %q, %v, want %q", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestBuiltinToolbox(t *testing.T) {
+	toolbox, err := BuiltinToolbox([]string{" Calculator ", ""})
+	if err != nil {
+		t.Fatalf("BuiltinToolbox() error = %v", err)
+	}
+	if toolbox.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", toolbox.Len())
+	}
+	if r := toolbox.call(context.Background(), llm.ToolCall{Name: "calculator", Arguments: `{"expression":"2*21"}`}); r.Result != "42" {
+		t.Errorf("calculator = %+v", r)
+	}
+
+	if _, err := BuiltinToolbox([]string{"weather"}); err == nil || !strings.Contains(err.Error(), "available: calculator") {
+		t.Errorf("BuiltinToolbox(weather) error = %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	anthropicBaseURL   = "https://api.anthropic.com/v1"
+	anthropicVersion   = "2023-06-01"
+	anthropicMaxTokens = 4096 // max_tokens is mandatory for the Messages API
+)
+
+// AnthropicClient implements the Client interface for the Anthropic Messages API.
+// The API has no JSON mode, so Config.ResponseFormat is left to the prompt.
+type AnthropicClient struct {
+	apiKey string
+	cfg    Config
+	http   *http.Client
+	usage  usageCounter
+}
+
+// NewAnthropicClient creates a new Anthropic client
+func NewAnthropicClient(apiKey string, cfg Config) *AnthropicClient {
+	cfg.Provider = ProviderAnthropic
+	if cfg.Model == "" {
+		cfg.Model = "claude-sonnet-4-5"
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = anthropicBaseURL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &AnthropicClient{
+		apiKey: apiKey,
+		cfg:    cfg,
+		http:   cfg.httpClient(),
+	}
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Temperature float64            `json:"temperature,omitempty"`
+	TopP        float64            `json:"top_p,omitempty"`
+	TopK        int                `json:"top_k,omitempty"`
+	MaxTokens   int                `json:"max_tokens"`
+	Stream      bool               `json:"stream,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type     string `json:"type"`
+		Text     string `json:"text"`
+		Thinking string `json:"thinking"` // extended thinking blocks
+	} `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      anthropicUsage `json:"usage"`
+}
+
+// anthropicUsage reports input_tokens excluding cache reads and writes,
+// which are billed separately
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+func (u anthropicUsage) toUsage() Usage {
+	return Usage{
+		PromptTokens:     u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens,
+		CompletionTokens: u.OutputTokens,
+		CachedTokens:     u.CacheReadInputTokens,
+	}
+}
+
+// anthropicStreamEvent covers the fields used from the SSE event payloads
+// (message_start, content_block_delta, message_delta, error); other event
+// types are ignored.
+type anthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Usage anthropicUsage `json:"usage"` // cumulative output_tokens on message_delta
+	Delta struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		Thinking   string `json:"thinking"`
+		StopReason string `json:"stop_reason"` // on message_delta
+	} `json:"delta"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Usage returns the tokens consumed by this client so far
+func (c *AnthropicClient) Usage() Usage {
+	return c.usage.get()
+}
+
+// Close is a no-op; the HTTP client may be shared with other clients
+func (c *AnthropicClient) Close() error {
+	return nil
+}
+
+// ChatTools returns ErrToolsUnsupported; tool use is not wired up for the
+// Anthropic API yet
+func (c *AnthropicClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	return Message{}, toolsUnsupported(c.cfg.Provider)
+}
+
+func (c *AnthropicClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	return c.chat(ctx, messages, false, nil)
+}
+
+func (c *AnthropicClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	return c.chat(ctx, messages, true, onChunk)
+}
+
+// buildAnthropicRequest lifts system messages into the top-level system field
+// and maps the remaining turns onto user/assistant roles
+func (c *AnthropicClient) buildAnthropicRequest(messages []Message, stream bool) anthropicRequest {
+	var system []string
+	var turns []anthropicMessage
+	for _, m := range messages {
+		switch m.Role {
+		case "system":
+			system = append(system, m.Content)
+		case "assistant", "model":
+			turns = append(turns, anthropicMessage{Role: "assistant", Content: m.Content})
+		default:
+			turns = append(turns, anthropicMessage{Role: "user", Content: m.Content})
+		}
+	}
+
+	maxTokens := c.cfg.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = anthropicMaxTokens
+	}
+
+	return anthropicRequest{
+		Model:       c.cfg.Model,
+		System:      strings.Join(system, "\n\n"),
+		Messages:    turns,
+		Temperature: c.cfg.Temperature,
+		TopP:        c.cfg.TopP,
+		TopK:        c.cfg.TopK,
+		MaxTokens:   maxTokens,
+		Stream:      stream,
+	}
+}
+
+func (c *AnthropicClient) chat(ctx context.Context, messages []Message, stream bool, onChunk func(string)) (string, error) {
+	body, err := json.Marshal(c.buildAnthropicRequest(messages, stream))
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.cfg.BaseURL+"/messages", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+	for k, v := range c.cfg.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError(resp, c.cfg.Provider, c.cfg.Model)
+	}
+
+	if stream {
+		content, stopReason, err := c.handleStream(resp.Body, onChunk, reasoningFunc(ctx))
+		if err == nil {
+			reportFinish(ctx, anthropicFinishReason(stopReason))
+		}
+		return content, err
+	}
+
+	var aResp anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&aResp); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	c.usage.add(aResp.Usage.toUsage())
+
+	onReasoning := reasoningFunc(ctx)
+	var result strings.Builder
+	for _, block := range aResp.Content {
+		switch block.Type {
+		case "text":
+			result.WriteString(block.Text)
+		case "thinking":
+			if onReasoning != nil {
+				onReasoning(block.Thinking)
+			}
+		}
+	}
+	if result.Len() == 0 && len(aResp.Content) == 0 {
+		return "", fmt.Errorf("no content in response")
+	}
+	reportFinish(ctx, anthropicFinishReason(aResp.StopReason))
+
+	return result.String(), nil
+}
+
+// handleStream parses Anthropic's SSE events and returns the text and the
+// stop reason. Text arrives in content_block_delta events with a text_delta
+// payload (thinking_delta for extended thinking) and the stop reason in
+// message_delta. The stream ends with message_stop; an error event aborts it
+// and a stream closed before message_stop is reported as ErrStreamTruncated.
+func (c *AnthropicClient) handleStream(body io.Reader, onChunk, onReasoning func(string)) (string, string, error) {
+	var fullContent strings.Builder
+	var stopReason string
+	var usage anthropicUsage
+	defer func() { c.usage.add(usage.toUsage()) }()
+	decoder := newSSEDecoder(body)
+
+	for {
+		sse, err := decoder.Next()
+		if err == io.EOF {
+			return fullContent.String(), stopReason, ErrStreamTruncated
+		}
+		if err != nil {
+			return fullContent.String(), stopReason, fmt.Errorf("read stream: %w", err)
+		}
+
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(sse.Data), &event); err != nil {
+			return fullContent.String(), stopReason, fmt.Errorf("decode stream event: %w", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			usage = event.Message.Usage
+		case "message_delta":
+			usage.OutputTokens = event.Usage.OutputTokens
+			if event.Delta.StopReason != "" {
+				stopReason = event.Delta.StopReason
+			}
+		case "content_block_delta":
+			if event.Delta.Type == "thinking_delta" && onReasoning != nil {
+				onReasoning(event.Delta.Thinking)
+			}
+			if event.Delta.Type != "text_delta" {
+				continue
+			}
+			fullContent.WriteString(event.Delta.Text)
+			if onChunk != nil {
+				onChunk(event.Delta.Text)
+			}
+		case "error":
+			return fullContent.String(), stopReason, classifyAPIError(&APIError{
+				Provider: ProviderAnthropic,
+				Model:    c.cfg.Model,
+				Type:     event.Error.Type,
+				Message:  event.Error.Message,
+				Body:     sse.Data,
+			})
+		case "message_stop":
+			return fullContent.String(), stopReason, nil
+		}
+	}
+}
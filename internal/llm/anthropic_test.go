@@ -0,0 +1,231 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewAnthropicClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		wantModel string
+	}{
+		{
+			name:      "with custom model",
+			cfg:       Config{Model: "claude-opus-4-1"},
+			wantModel: "claude-opus-4-1",
+		},
+		{
+			name:      "with empty model - uses default",
+			cfg:       Config{},
+			wantModel: "claude-sonnet-4-5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewAnthropicClient("test-key", tt.cfg)
+			if client.cfg.Model != tt.wantModel {
+				t.Errorf("client.cfg.Model = %v, want %v", client.cfg.Model, tt.wantModel)
+			}
+			if client.cfg.BaseURL != anthropicBaseURL {
+				t.Errorf("client.cfg.BaseURL = %v, want %v", client.cfg.BaseURL, anthropicBaseURL)
+			}
+		})
+	}
+}
+
+func TestAnthropicClient_Implements_Client(t *testing.T) {
+	var _ Client = (*AnthropicClient)(nil)
+}
+
+func TestAnthropicClient_BuildRequest(t *testing.T) {
+	client := NewAnthropicClient("k", Config{Model: "claude", Temperature: 0.2})
+	req := client.buildAnthropicRequest([]Message{
+		{Role: "system", Content: "You are a judge."},
+		{Role: "user", Content: "Question"},
+		{Role: "assistant", Content: "Answer"},
+		{Role: "user", Content: "Follow-up"},
+	}, true)
+
+	if req.System != "You are a judge." {
+		t.Errorf("System = %q, want %q", req.System, "You are a judge.")
+	}
+	if len(req.Messages) != 3 {
+		t.Fatalf("len(Messages) = %d, want 3", len(req.Messages))
+	}
+	if req.Messages[1].Role != "assistant" {
+		t.Errorf("Messages[1].Role = %v, want assistant", req.Messages[1].Role)
+	}
+	if req.MaxTokens != anthropicMaxTokens {
+		t.Errorf("MaxTokens = %d, want default %d", req.MaxTokens, anthropicMaxTokens)
+	}
+	if !req.Stream {
+		t.Error("Stream should be true")
+	}
+}
+
+func TestAnthropicClient_Chat_MockServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("Path = %s, want /v1/messages", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-api-key" {
+			t.Errorf("x-api-key = %q", r.Header.Get("x-api-key"))
+		}
+		if r.Header.Get("anthropic-version") != anthropicVersion {
+			t.Errorf("anthropic-version = %q", r.Header.Get("anthropic-version"))
+		}
+
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req["system"] != "sys" {
+			t.Errorf("system = %v, want sys", req["system"])
+		}
+
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"Hello "},{"type":"text","text":"Claude"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewAnthropicClient("test-api-key", Config{BaseURL: server.URL + "/v1"})
+	got, err := client.Chat(context.Background(), []Message{
+		{Role: "system", Content: "sys"},
+		{Role: "user", Content: "hi"},
+	})
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if got != "Hello Claude" {
+		t.Errorf("Chat() = %q, want %q", got, "Hello Claude")
+	}
+}
+
+func TestAnthropicClient_ChatStream_MockServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","usage":{"input_tokens":20,"cache_read_input_tokens":80,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: ping
+data: {"type":"ping"}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":" World"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":12}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`)
+	}))
+	defer server.Close()
+
+	client := NewAnthropicClient("k", Config{BaseURL: server.URL})
+	var chunks []string
+	got, err := client.ChatStream(context.Background(), []Message{{Role: "user", Content: "hi"}}, func(s string) {
+		chunks = append(chunks, s)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if got != "Hello World" {
+		t.Errorf("ChatStream() = %q, want %q", got, "Hello World")
+	}
+	if len(chunks) != 2 {
+		t.Errorf("Callback was called %d times, want 2", len(chunks))
+	}
+	want := Usage{PromptTokens: 100, CompletionTokens: 12, CachedTokens: 80}
+	if got := client.Usage(); got != want {
+		t.Errorf("Usage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAnthropicClient_HandleStream_ErrorEvent(t *testing.T) {
+	streamData := `event: content_block_delta
+data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"partial"}}
+
+event: error
+data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}
+`
+
+	client := &AnthropicClient{}
+	got, _, err := client.handleStream(strings.NewReader(streamData), nil, nil)
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) || serverErr.Type != "overloaded_error" {
+		t.Errorf("handleStream() error = %v, want ServerError overloaded_error", err)
+	}
+	if got != "partial" {
+		t.Errorf("handleStream() = %q, want %q", got, "partial")
+	}
+}
+
+func TestAnthropicClient_HandleStream_Thinking(t *testing.T) {
+	streamData := `event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"weighing both sides"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Verdict"}}
+
+event: message_stop
+data: {"type":"message_stop"}
+`
+
+	client := &AnthropicClient{}
+	var reasoning string
+	got, _, err := client.handleStream(strings.NewReader(streamData), nil, func(s string) { reasoning += s })
+	if err != nil {
+		t.Fatalf("handleStream() error = %v", err)
+	}
+	if got != "Verdict" {
+		t.Errorf("handleStream() = %q, want %q", got, "Verdict")
+	}
+	if reasoning != "weighing both sides" {
+		t.Errorf("reasoning = %q, want %q", reasoning, "weighing both sides")
+	}
+}
+
+func TestAnthropicClient_ChatTools_Unsupported(t *testing.T) {
+	client := NewAnthropicClient("key", Config{})
+	_, err := client.ChatTools(context.Background(), []Message{{Role: "user", Content: "hi"}}, []Tool{{Name: "calculator"}})
+	if !errors.Is(err, ErrToolsUnsupported) {
+		t.Errorf("ChatTools() error = %v, want ErrToolsUnsupported", err)
+	}
+}
+
+func TestAnthropicClient_HandleStream_Truncated(t *testing.T) {
+	streamData := `event: content_block_delta
+data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"partial"}}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"max_tokens"},"usage":{"output_tokens":5}}
+
+`
+
+	client := &AnthropicClient{}
+	got, stopReason, err := client.handleStream(strings.NewReader(streamData), nil, nil)
+	if !errors.Is(err, ErrStreamTruncated) {
+		t.Errorf("handleStream() error = %v, want ErrStreamTruncated", err)
+	}
+	if got != "partial" || stopReason != "max_tokens" {
+		t.Errorf("handleStream() = %q, %q; want %q, %q", got, stopReason, "partial", "max_tokens")
+	}
+}
@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DefaultAzureAPIVersion is the Azure OpenAI api-version used when neither
+// Config.APIVersion nor AZURE_OPENAI_API_VERSION is set
+const DefaultAzureAPIVersion = "2024-10-21"
+
+// AzureClient implements the Client interface for Azure OpenAI.
+// Azure speaks the OpenAI protocol with a URL per deployment and an
+// api-key header, so it is an OpenAIClient preset.
+type AzureClient = OpenAIClient
+
+// NewAzureClient creates a client for a deployment of the Azure OpenAI
+// resource at cfg.BaseURL, e.g. https://my-resource.openai.azure.com.
+// The deployment is cfg.Deployment, or cfg.Model when that is empty; Model
+// still names the model for pricing and reports.
+func NewAzureClient(apiKey string, cfg Config) *AzureClient {
+	if cfg.Model == "" {
+		cfg.Model = "gpt-4o"
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = ProviderAzure
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = os.Getenv("AZURE_OPENAI_ENDPOINT")
+	}
+	c := NewOpenAIClient(apiKey, cfg)
+	c.url = fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		strings.TrimSuffix(c.cfg.BaseURL, "/openai"),
+		url.PathEscape(cmp.Or(cfg.Deployment, cfg.Model)),
+		url.QueryEscape(cmp.Or(cfg.APIVersion, os.Getenv("AZURE_OPENAI_API_VERSION"), DefaultAzureAPIVersion)))
+	c.keyHeader = "api-key"
+	return c
+}
+
+// ResolveAzureEndpoint returns the API keys and resource endpoint for the
+// azure provider. The endpoint is cfg.BaseURL or AZURE_OPENAI_ENDPOINT; the
+// keys come from AZURE_OPENAI_API_KEY or cfg.Credentials. Both are required.
+func ResolveAzureEndpoint(cfg Config) (apiKeys []string, endpoint string, err error) {
+	endpoint = cmp.Or(cfg.BaseURL, os.Getenv("AZURE_OPENAI_ENDPOINT"))
+	if endpoint == "" {
+		return nil, "", fmt.Errorf("AZURE_OPENAI_ENDPOINT environment variable or a base URL is required")
+	}
+
+	envVars := []string{"AZURE_OPENAI_API_KEY"}
+	apiKeys, err = cfg.Credentials.Keys(ProviderAzure, envVars)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(apiKeys) == 0 {
+		return nil, "", cfg.Credentials.missingKeyError(ProviderAzure, envVars)
+	}
+	return apiKeys, endpoint, nil
+}
@@ -0,0 +1,134 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// azureStandIn serves an Azure OpenAI resource with one deployment that
+// accepts key. It answers like the real service: 404 DeploymentNotFound for
+// other deployments, 401 for a wrong api-key, and OpenAI-style completions
+// and streams otherwise. Each request's api-version is recorded.
+func azureStandIn(t *testing.T, deployment, key string) (*httptest.Server, *[]string) {
+	t.Helper()
+	versions := new([]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*versions = append(*versions, r.URL.Query().Get("api-version"))
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("Authorization header sent to Azure: %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Path != "/openai/deployments/"+deployment+"/chat/completions" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"DeploymentNotFound","message":"The API deployment for this resource does not exist."}}`)
+			return
+		}
+		if r.Header.Get("api-key") != key {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"code":"401","message":"Access denied due to invalid subscription key."}}`)
+			return
+		}
+
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if !req.Stream {
+			fmt.Fprint(w, `{"choices":[{"message":{"content":"azure says hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":7,"completion_tokens":3}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[],\"prompt_filter_results\":[]}\n\n")
+		for _, chunk := range []string{"azure", " streams"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":2}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server, versions
+}
+
+func TestAzureClient_Deployment(t *testing.T) {
+	server, versions := azureStandIn(t, "gpt4o-prod", "az-key")
+	t.Setenv("AZURE_OPENAI_API_VERSION", "")
+
+	client := NewAzureClient("az-key", Config{Model: "gpt-4o", Deployment: "gpt4o-prod", BaseURL: server.URL + "/"})
+	got, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err != nil || got != "azure says hi" {
+		t.Fatalf("Chat() = %q, %v", got, err)
+	}
+
+	var chunks []string
+	got, err = client.ChatStream(context.Background(), []Message{{Role: "user", Content: "hi"}}, func(s string) { chunks = append(chunks, s) })
+	if err != nil || got != "azure streams" || len(chunks) != 2 {
+		t.Fatalf("ChatStream() = %q in %d chunks, %v", got, len(chunks), err)
+	}
+	if u := client.Usage(); u.PromptTokens != 14 || u.CompletionTokens != 5 {
+		t.Errorf("Usage() = %+v, want both calls counted", u)
+	}
+	if strings.Join(*versions, ",") != DefaultAzureAPIVersion+","+DefaultAzureAPIVersion {
+		t.Errorf("api-version = %q, want %s", *versions, DefaultAzureAPIVersion)
+	}
+}
+
+func TestAzureClient_Errors(t *testing.T) {
+	server, versions := azureStandIn(t, "gpt4o-prod", "az-key")
+
+	// Without a deployment the model name is used, and is not deployed here
+	client := NewAzureClient("az-key", Config{Model: "gpt-4o", BaseURL: server.URL, APIVersion: "2025-01-01-preview"})
+	_, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "DeploymentNotFound" || apiErr.Provider != ProviderAzure {
+		t.Errorf("Chat() error = %v, want the DeploymentNotFound APIError", err)
+	}
+	if (*versions)[0] != "2025-01-01-preview" {
+		t.Errorf("api-version = %q, want the configured one", (*versions)[0])
+	}
+
+	client = NewAzureClient("wrong", Config{Deployment: "gpt4o-prod", BaseURL: server.URL + "/openai"})
+	_, err = client.ChatStream(context.Background(), []Message{{Role: "user", Content: "hi"}}, nil)
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		t.Errorf("ChatStream() error = %v, want an AuthError", err)
+	}
+}
+
+func TestNewClient_Azure(t *testing.T) {
+	server, _ := azureStandIn(t, "gpt-4o", "az-key")
+	t.Setenv("AZURE_OPENAI_API_KEY", "az-key")
+	t.Setenv("AZURE_OPENAI_ENDPOINT", server.URL)
+
+	client, err := NewClient(Config{Provider: ProviderAzure, Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}); err != nil || got != "azure says hi" {
+		t.Errorf("Chat() = %q, %v; want the deployment named after the model", got, err)
+	}
+}
+
+func TestResolveAzureEndpoint(t *testing.T) {
+	t.Setenv("AZURE_OPENAI_API_KEY", "")
+	t.Setenv("AZURE_OPENAI_API_KEYS", "")
+	t.Setenv("AZURE_OPENAI_ENDPOINT", "")
+	if _, _, err := ResolveAzureEndpoint(Config{}); err == nil || !strings.Contains(err.Error(), "AZURE_OPENAI_ENDPOINT") {
+		t.Errorf("ResolveAzureEndpoint() error = %v, want the endpoint required", err)
+	}
+	if _, _, err := ResolveAzureEndpoint(Config{BaseURL: "https://res.openai.azure.com"}); err == nil || err.Error() != "AZURE_OPENAI_API_KEY environment variable is required" {
+		t.Errorf("ResolveAzureEndpoint() error = %v, want the key required", err)
+	}
+
+	t.Setenv("AZURE_OPENAI_API_KEYS", "k1,k2")
+	t.Setenv("AZURE_OPENAI_ENDPOINT", "https://res.openai.azure.com")
+	keys, endpoint, err := ResolveAzureEndpoint(Config{})
+	if err != nil || len(keys) != 2 || endpoint != "https://res.openai.azure.com" {
+		t.Errorf("ResolveAzureEndpoint() = %q, %q, %v", keys, endpoint, err)
+	}
+}
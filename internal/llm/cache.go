@@ -0,0 +1,317 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cacheKeyVersion is bumped whenever the key derivation or entry format
+// changes, so stale entries simply stop matching
+const cacheKeyVersion = "v2"
+
+// Cache is a disk-backed store of LLM responses, one JSON file per entry.
+// It is safe for concurrent use by multiple clients and processes: entries
+// are written to a temporary file and renamed into place.
+type Cache struct {
+	dir string
+	now func() time.Time
+}
+
+// NewCache creates a cache rooted at dir; the directory is created lazily
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir, now: time.Now}
+}
+
+// DefaultCacheDir returns the per-user cache directory for responses
+func DefaultCacheDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "dialecta", "responses")
+	}
+	return filepath.Join(".dialecta", "cache")
+}
+
+// Dir returns the cache directory
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// cacheEntry is the on-disk form of a cached response. Chunks keep the
+// original stream boundaries so replays look like the live stream.
+type cacheEntry struct {
+	Provider     Provider     `json:"provider"`
+	Model        string       `json:"model"`
+	Chunks       []string     `json:"chunks"`
+	Reasoning    string       `json:"reasoning,omitempty"`
+	FinishReason FinishReason `json:"finish_reason,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+func (e *cacheEntry) content() string {
+	return strings.Join(e.Chunks, "")
+}
+
+// CacheKey derives the cache key from everything that shapes a response:
+// provider, model, the endpoint, deployment or plugin that serves it, extra
+// headers, sampling, generation and response-format settings, and the full
+// message list. Settings left at their zero value do not change the key.
+func CacheKey(cfg Config, messages []Message) string {
+	payload, _ := json.Marshal(struct {
+		Version        string            `json:"v"`
+		Provider       Provider          `json:"provider"`
+		Model          string            `json:"model"`
+		BaseURL        string            `json:"base_url,omitempty"`
+		Deployment     string            `json:"deployment,omitempty"`
+		Command        []string          `json:"command,omitempty"`
+		Headers        map[string]string `json:"headers,omitempty"`
+		Temperature    float64           `json:"temperature"`
+		MaxTokens      int               `json:"max_tokens"`
+		TopP           float64           `json:"top_p,omitempty"`
+		TopK           int               `json:"top_k,omitempty"`
+		ThinkingBudget int               `json:"thinking_budget,omitempty"`
+		SafetySettings []SafetySetting   `json:"safety,omitempty"`
+		ResponseFormat *ResponseFormat   `json:"response_format,omitempty"`
+		Messages       []Message         `json:"messages"`
+	}{cacheKeyVersion, cfg.Provider, cfg.Model, cfg.BaseURL, cfg.Deployment, cfg.Command, cfg.Headers, cfg.Temperature, cfg.MaxTokens,
+		cfg.TopP, cfg.TopK, cfg.ThinkingBudget, cfg.SafetySettings, cfg.ResponseFormat, messages})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// get loads an entry and refreshes its modification time, so pruning by
+// size evicts the least recently used entries first
+func (c *Cache) get(key string) (*cacheEntry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	now := c.now()
+	_ = os.Chtimes(c.path(key), now, now)
+	return &entry, true
+}
+
+func (c *Cache) put(key string, entry *cacheEntry) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal cache entry: %w", err)
+	}
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// PruneStats summarises a Prune run
+type PruneStats struct {
+	Removed   int   // entries deleted
+	Freed     int64 // bytes deleted
+	Remaining int   // entries kept
+	Size      int64 // bytes kept
+}
+
+// Prune deletes entries not used within maxAge, then evicts the least
+// recently used entries until the cache fits in maxBytes. A zero limit
+// disables that check.
+func (c *Cache) Prune(maxAge time.Duration, maxBytes int64) (PruneStats, error) {
+	var stats PruneStats
+
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, file{path, info.Size(), info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return stats, fmt.Errorf("scan cache: %w", err)
+	}
+
+	// Oldest first, so size eviction drops the least recently used
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	for _, f := range files {
+		stats.Size += f.size
+	}
+	cutoff := c.now().Add(-maxAge)
+	for _, f := range files {
+		expired := maxAge > 0 && f.modTime.Before(cutoff)
+		oversize := maxBytes > 0 && stats.Size > maxBytes
+		if !expired && !oversize {
+			stats.Remaining++
+			continue
+		}
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return stats, fmt.Errorf("remove cache entry: %w", err)
+		}
+		stats.Removed++
+		stats.Freed += f.size
+		stats.Size -= f.size
+	}
+	return stats, nil
+}
+
+// cachedClient serves repeated requests from a Cache
+type cachedClient struct {
+	inner Client
+	cfg   Config
+	cache *Cache
+}
+
+// WithCache wraps client so identical requests are answered from cache.
+// Cached streams are replayed chunk by chunk through onChunk, so streaming
+// callers behave as on a live response. Cache hits consume no tokens.
+func WithCache(client Client, cfg Config, cache *Cache) Client {
+	if cache == nil {
+		return client
+	}
+	return &cachedClient{inner: client, cfg: cfg, cache: cache}
+}
+
+func (c *cachedClient) Usage() Usage {
+	return c.inner.Usage()
+}
+
+func (c *cachedClient) Close() error {
+	return c.inner.Close()
+}
+
+// ChatTools is never cached: tool results feed back into the conversation
+// and may differ between runs
+func (c *cachedClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	return c.inner.ChatTools(ctx, messages, tools)
+}
+
+func (c *cachedClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	key := CacheKey(c.cfg, messages)
+	if entry, ok := c.cache.get(key); ok {
+		c.replay(ctx, entry)
+		return entry.content(), nil
+	}
+
+	entry := c.newEntry()
+	ctx, reasoning := captureReasoning(ctx)
+	ctx = captureFinish(ctx, entry)
+	result, err := c.inner.Chat(ctx, messages)
+	if err != nil {
+		return result, err
+	}
+	entry.Chunks = []string{result}
+	entry.Reasoning = reasoning.String()
+	_ = c.cache.put(key, entry) // a failed write only costs a future miss
+	return result, nil
+}
+
+func (c *cachedClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	key := CacheKey(c.cfg, messages)
+	if entry, ok := c.cache.get(key); ok {
+		c.replay(ctx, entry)
+		for _, chunk := range entry.Chunks {
+			if err := ctx.Err(); err != nil {
+				return "", err
+			}
+			if onChunk != nil {
+				onChunk(chunk)
+			}
+		}
+		return entry.content(), nil
+	}
+
+	entry := c.newEntry()
+	ctx, reasoning := captureReasoning(ctx)
+	ctx = captureFinish(ctx, entry)
+	result, err := c.inner.ChatStream(ctx, messages, func(chunk string) {
+		entry.Chunks = append(entry.Chunks, chunk)
+		if onChunk != nil {
+			onChunk(chunk)
+		}
+	})
+	if err != nil {
+		return result, err
+	}
+	entry.Reasoning = reasoning.String()
+	_ = c.cache.put(key, entry)
+	return result, nil
+}
+
+func (c *cachedClient) newEntry() *cacheEntry {
+	return &cacheEntry{Provider: c.cfg.Provider, Model: c.cfg.Model, CreatedAt: c.cache.now()}
+}
+
+// captureReasoning records reasoning for the cache entry while still
+// forwarding it to the caller's callback
+func captureReasoning(ctx context.Context) (context.Context, *strings.Builder) {
+	var reasoning strings.Builder
+	onReasoning := reasoningFunc(ctx)
+	return WithReasoning(ctx, func(s string) {
+		reasoning.WriteString(s)
+		if onReasoning != nil {
+			onReasoning(s)
+		}
+	}), &reasoning
+}
+
+// captureFinish records the finish reason for the cache entry while still
+// forwarding it to the caller's callback
+func captureFinish(ctx context.Context, entry *cacheEntry) context.Context {
+	onFinish := finishFunc(ctx)
+	return WithFinishReason(ctx, func(reason FinishReason) {
+		entry.FinishReason = reason
+		if onFinish != nil {
+			onFinish(reason)
+		}
+	})
+}
+
+// replay reports a cached entry's reasoning and finish reason
+func (c *cachedClient) replay(ctx context.Context, entry *cacheEntry) {
+	if onReasoning := reasoningFunc(ctx); onReasoning != nil && entry.Reasoning != "" {
+		onReasoning(entry.Reasoning)
+	}
+	reportFinish(ctx, entry.FinishReason)
+}
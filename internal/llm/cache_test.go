@@ -0,0 +1,207 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chunkClient streams fixed chunks, reasoning and finish reason, counting calls
+type chunkClient struct {
+	chunks    []string
+	reasoning string
+	finish    FinishReason
+	err       error
+	calls     int
+}
+
+func (c *chunkClient) Usage() Usage { return Usage{CompletionTokens: c.calls} }
+func (c *chunkClient) Close() error { return nil }
+
+func (c *chunkClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	return c.ChatStream(ctx, messages, nil)
+}
+
+func (c *chunkClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	content, err := c.ChatStream(ctx, messages, nil)
+	return Message{Role: "assistant", Content: content}, err
+}
+
+func (c *chunkClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	c.calls++
+	if onReasoning := reasoningFunc(ctx); onReasoning != nil && c.reasoning != "" {
+		onReasoning(c.reasoning)
+	}
+	for _, chunk := range c.chunks {
+		if onChunk != nil {
+			onChunk(chunk)
+		}
+	}
+	if c.err == nil {
+		reportFinish(ctx, c.finish)
+	}
+	return strings.Join(c.chunks, ""), c.err
+}
+
+func TestCacheKey(t *testing.T) {
+	cfg := Config{Provider: ProviderDeepSeek, Model: "deepseek-chat", Temperature: 0.8, MaxTokens: 4096}
+	msgs := []Message{{Role: "user", Content: "material"}}
+	base := CacheKey(cfg, msgs)
+
+	if CacheKey(cfg, []Message{{Role: "user", Content: "material"}}) != base {
+		t.Error("CacheKey() should be stable for identical input")
+	}
+
+	variants := map[string]func(Config, []Message) (Config, []Message){
+		"provider":    func(c Config, m []Message) (Config, []Message) { c.Provider = ProviderOpenAI; return c, m },
+		"model":       func(c Config, m []Message) (Config, []Message) { c.Model = "deepseek-reasoner"; return c, m },
+		"temperature": func(c Config, m []Message) (Config, []Message) { c.Temperature = 0.1; return c, m },
+		"max tokens":  func(c Config, m []Message) (Config, []Message) { c.MaxTokens = 8192; return c, m },
+		"top p":       func(c Config, m []Message) (Config, []Message) { c.TopP = 0.9; return c, m },
+		"thinking":    func(c Config, m []Message) (Config, []Message) { c.ThinkingBudget = 1024; return c, m },
+		"base url":    func(c Config, m []Message) (Config, []Message) { c.BaseURL = "http://localhost:8000/v1"; return c, m },
+		"deployment":  func(c Config, m []Message) (Config, []Message) { c.Deployment = "prod-gpt4o"; return c, m },
+		"command":     func(c Config, m []Message) (Config, []Message) { c.Command = []string{"./my-model"}; return c, m },
+		"headers": func(c Config, m []Message) (Config, []Message) {
+			c.Headers = map[string]string{"X-Tenant": "a"}
+			return c, m
+		},
+		"response format": func(c Config, m []Message) (Config, []Message) {
+			c.ResponseFormat = &ResponseFormat{Type: ResponseJSONObject}
+			return c, m
+		},
+		"messages": func(c Config, m []Message) (Config, []Message) {
+			return c, []Message{{Role: "user", Content: "other"}}
+		},
+	}
+	for name, mutate := range variants {
+		if CacheKey(mutate(cfg, msgs)) == base {
+			t.Errorf("CacheKey() should change with %s", name)
+		}
+	}
+
+	// Settings that do not shape the response are not part of the key
+	cfg.Retry = DefaultRetryPolicy
+	cfg.Cache = NewCache(t.TempDir())
+	if CacheKey(cfg, msgs) != base {
+		t.Error("CacheKey() should ignore retry and cache settings")
+	}
+}
+
+func TestCachedClient_ReplaysStream(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	cfg := Config{Provider: ProviderDeepSeek, Model: "deepseek-chat"}
+	inner := &chunkClient{chunks: []string{"## 💡 One", "-Liner\n", "body"}, reasoning: "thinking", finish: FinishLength}
+	client := WithCache(inner, cfg, cache)
+	msgs := []Message{{Role: "user", Content: "material"}}
+
+	for i := 0; i < 2; i++ {
+		var chunks []string
+		var reasoning string
+		var finish FinishReason
+		ctx := WithReasoning(context.Background(), func(s string) { reasoning += s })
+		ctx = WithFinishReason(ctx, func(r FinishReason) { finish = r })
+		got, err := client.ChatStream(ctx, msgs, func(s string) { chunks = append(chunks, s) })
+		if err != nil {
+			t.Fatalf("run %d: ChatStream() error = %v", i, err)
+		}
+		if got != "## 💡 One-Liner\nbody" {
+			t.Errorf("run %d: ChatStream() = %q", i, got)
+		}
+		if len(chunks) != 3 {
+			t.Errorf("run %d: got %d chunks, want the original 3 boundaries", i, len(chunks))
+		}
+		if reasoning != "thinking" {
+			t.Errorf("run %d: reasoning = %q, want %q", i, reasoning, "thinking")
+		}
+		if finish != FinishLength {
+			t.Errorf("run %d: finish reason = %q, want %q", i, finish, FinishLength)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("inner client called %d times, want 1", inner.calls)
+	}
+	if client.Usage().CompletionTokens != 1 {
+		t.Errorf("Usage() should only count the live call, got %+v", client.Usage())
+	}
+
+	// Chat shares entries with ChatStream
+	if got, err := client.Chat(context.Background(), msgs); err != nil || got != "## 💡 One-Liner\nbody" {
+		t.Errorf("Chat() = %q, %v; want cached content", got, err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("Chat() should hit the cache, inner called %d times", inner.calls)
+	}
+}
+
+func TestCachedClient_DoesNotCacheErrors(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	inner := &chunkClient{chunks: []string{"partial"}, err: errors.New("stream broke")}
+	client := WithCache(inner, Config{Provider: ProviderGemini}, cache)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ChatStream(context.Background(), nil, nil); err == nil {
+			t.Fatal("ChatStream() should return the inner error")
+		}
+	}
+	if inner.calls != 2 {
+		t.Errorf("inner client called %d times, want 2 (failures are not cached)", inner.calls)
+	}
+}
+
+func TestWithCache_Nil(t *testing.T) {
+	inner := &chunkClient{}
+	if WithCache(inner, Config{}, nil) != Client(inner) {
+		t.Error("WithCache(nil) should return the client unchanged")
+	}
+}
+
+func TestCache_Prune(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCache(dir)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	write := func(name string, size int, age time.Duration) {
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mod := now.Add(-age)
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("expired", 100, 40*24*time.Hour)
+	write("old", 300, 3*time.Hour)
+	write("recent", 300, time.Hour)
+	write("fresh", 300, time.Minute)
+
+	stats, err := cache.Prune(30*24*time.Hour, 700)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if stats.Removed != 2 || stats.Freed != 400 {
+		t.Errorf("Prune() removed %d entries (%d bytes), want 2 (400 bytes)", stats.Removed, stats.Freed)
+	}
+	if stats.Remaining != 2 || stats.Size != 600 {
+		t.Errorf("Prune() kept %d entries (%d bytes), want 2 (600 bytes)", stats.Remaining, stats.Size)
+	}
+	for _, name := range []string{"recent", "fresh"} {
+		if _, err := os.Stat(filepath.Join(dir, name+".json")); err != nil {
+			t.Errorf("%s entry should survive: %v", name, err)
+		}
+	}
+}
+
+func TestCache_PruneMissingDir(t *testing.T) {
+	cache := NewCache(filepath.Join(t.TempDir(), "never-created"))
+	stats, err := cache.Prune(time.Hour, 1)
+	if err != nil || stats != (PruneStats{}) {
+		t.Errorf("Prune() on missing dir = %+v, %v; want empty stats", stats, err)
+	}
+}
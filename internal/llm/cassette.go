@@ -0,0 +1,221 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+)
+
+// CassetteMode selects whether a Cassette talks to the network
+type CassetteMode int
+
+const (
+	// CassetteReplay serves recorded responses and never touches the network
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord forwards requests to the real transport and records them
+	CassetteRecord
+)
+
+// Cassette is an http.RoundTripper that records provider HTTP/SSE exchanges
+// to a fixture file and replays them offline. Requests are matched on
+// method, path and JSON body, so concurrent requests (Pro and Con) replay
+// correctly regardless of order. Only the path is stored: API keys in
+// headers or query strings never reach the fixture.
+type Cassette struct {
+	path      string
+	mode      CassetteMode
+	transport http.RoundTripper // real transport used when recording
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// Interaction is one recorded request/response pair
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies a request for matching
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// RecordedResponse holds the full response, including streamed bodies
+type RecordedResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// NewCassette opens the fixture at path. In replay mode the file must
+// exist; in record mode it is (re)written by Save.
+func NewCassette(path string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{path: path, mode: mode, transport: http.DefaultTransport}
+	if mode == CassetteRecord {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+	}
+	c.used = make([]bool, len(c.interactions))
+	return c, nil
+}
+
+// HTTPClient returns a client that sends all traffic through the cassette
+func (c *Cassette) HTTPClient() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+// RoundTrip implements http.RoundTripper
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, body, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.mode == CassetteRecord {
+		return c.record(req, recorded, body)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, in := range c.interactions {
+		if !c.used[i] && in.Request.matches(recorded) {
+			c.used[i] = true
+			return in.Response.toHTTP(req), nil
+		}
+	}
+	return nil, fmt.Errorf("cassette %s: no unused interaction for %s %s", filepath.Base(c.path), recorded.Method, recorded.Path)
+}
+
+func (c *Cassette) record(req *http.Request, recorded RecordedRequest, body []byte) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: read response: %w", err)
+	}
+	in := &Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        string(respBody),
+		},
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, in)
+	c.used = append(c.used, true)
+	c.mu.Unlock()
+
+	return in.Response.toHTTP(req), nil
+}
+
+// Save writes the recorded interactions; it is a no-op in replay mode
+func (c *Cassette) Save() error {
+	if c.mode != CassetteRecord {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("create cassette dir: %w", err)
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
+
+// Unused returns the number of recorded interactions not yet replayed
+func (c *Cassette) Unused() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, used := range c.used {
+		if !used {
+			n++
+		}
+	}
+	return n
+}
+
+func recordRequest(req *http.Request) (RecordedRequest, []byte, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return RecordedRequest{}, nil, fmt.Errorf("cassette: read request: %w", err)
+		}
+	}
+
+	recorded := RecordedRequest{Method: req.Method, Path: req.URL.Path}
+	if len(body) > 0 {
+		if json.Valid(body) {
+			recorded.Body = json.RawMessage(body)
+		} else {
+			recorded.Body, _ = json.Marshal(string(body))
+		}
+	}
+	return recorded, body, nil
+}
+
+// matches compares method, path and the decoded JSON body, so formatting
+// and key order in the fixture do not matter
+func (r RecordedRequest) matches(other RecordedRequest) bool {
+	if r.Method != other.Method || r.Path != other.Path {
+		return false
+	}
+	if len(r.Body) == 0 || len(other.Body) == 0 {
+		return len(r.Body) == len(other.Body)
+	}
+	var a, b any
+	if json.Unmarshal(r.Body, &a) != nil || json.Unmarshal(other.Body, &b) != nil {
+		return bytes.Equal(r.Body, other.Body)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func (r RecordedResponse) toHTTP(req *http.Request) *http.Response {
+	header := http.Header{}
+	if r.ContentType != "" {
+		header.Set("Content-Type", r.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassette_RecordReplay(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		var req openAIRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\ndata: [DONE]\n\n", "echo "+req.Messages[0].Content)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "openai.json")
+	newClient := func(c *Cassette) *OpenAIClient {
+		return NewOpenAIClient("sk-secret", Config{Model: "gpt-4o", BaseURL: server.URL + "/v1", HTTPClient: c.HTTPClient()})
+	}
+	send := func(client *OpenAIClient, content string) (string, error) {
+		return client.ChatStream(context.Background(), []Message{{Role: "user", Content: content}}, nil)
+	}
+
+	recorder, err := NewCassette(path, CassetteRecord)
+	if err != nil {
+		t.Fatalf("NewCassette(record) error = %v", err)
+	}
+	for _, content := range []string{"first", "second"} {
+		if _, err := send(newClient(recorder), content); err != nil {
+			t.Fatalf("record %s: %v", content, err)
+		}
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "sk-secret") {
+		t.Error("cassette should not contain the API key")
+	}
+
+	// Replay in reverse order without a server
+	server.Close()
+	player, err := NewCassette(path, CassetteReplay)
+	if err != nil {
+		t.Fatalf("NewCassette(replay) error = %v", err)
+	}
+	for _, content := range []string{"second", "first"} {
+		result, err := send(newClient(player), content)
+		if err != nil {
+			t.Fatalf("replay %s: %v", content, err)
+		}
+		if want := "echo " + content; result != want {
+			t.Errorf("replay %s = %q, want %q", content, result, want)
+		}
+	}
+	if hits != 2 {
+		t.Errorf("server hits = %d, want 2", hits)
+	}
+	if n := player.Unused(); n != 0 {
+		t.Errorf("Unused() = %d, want 0", n)
+	}
+
+	// Each interaction replays once; unknown requests fail
+	if _, err := send(newClient(player), "first"); err == nil || !strings.Contains(err.Error(), "no unused interaction") {
+		t.Errorf("repeated request error = %v, want no unused interaction", err)
+	}
+	if _, err := send(newClient(player), "third"); err == nil {
+		t.Error("unrecorded request should fail")
+	}
+}
+
+func TestNewCassette_Missing(t *testing.T) {
+	if _, err := NewCassette(filepath.Join(t.TempDir(), "missing.json"), CassetteReplay); err == nil {
+		t.Error("NewCassette(replay) should fail for a missing file")
+	}
+	c, err := NewCassette(filepath.Join(t.TempDir(), "new.json"), CassetteRecord)
+	if err != nil {
+		t.Fatalf("NewCassette(record) error = %v", err)
+	}
+	if err := c.Save(); err != nil {
+		t.Errorf("Save() error = %v", err)
+	}
+}
+
+func TestRecordedRequest_Matches(t *testing.T) {
+	a := RecordedRequest{Method: "POST", Path: "/v1/chat", Body: []byte(`{"model":"m","n":1}`)}
+	tests := []struct {
+		name  string
+		other RecordedRequest
+		want  bool
+	}{
+		{"key order and spacing", RecordedRequest{Method: "POST", Path: "/v1/chat", Body: []byte(`{ "n": 1, "model": "m" }`)}, true},
+		{"different body", RecordedRequest{Method: "POST", Path: "/v1/chat", Body: []byte(`{"model":"m","n":2}`)}, false},
+		{"different path", RecordedRequest{Method: "POST", Path: "/v1/other", Body: a.Body}, false},
+		{"different method", RecordedRequest{Method: "GET", Path: "/v1/chat", Body: a.Body}, false},
+		{"missing body", RecordedRequest{Method: "POST", Path: "/v1/chat"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.matches(tt.other); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
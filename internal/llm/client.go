@@ -3,6 +3,7 @@ package llm
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 )
 
@@ -13,12 +14,23 @@ const (
 	ProviderDeepSeek  Provider = "deepseek"
 	ProviderGemini    Provider = "gemini"
 	ProviderDashScope Provider = "dashscope"
+	ProviderOpenAI    Provider = "openai"
+	ProviderAnthropic Provider = "anthropic"
+	ProviderAzure     Provider = "azure"
+	ProviderOllama    Provider = "ollama"
+	ProviderMock      Provider = "mock" // offline, deterministic answers
+	ProviderExec      Provider = "exec" // external plugin process (see ExecClient)
 )
 
 // Message represents a chat message
 type Message struct {
-	Role    string
+	Role    string // system, user, assistant, or tool
 	Content string
+
+	// ToolCalls are the tools an assistant message asks to run; a "tool"
+	// message carries one result and the ToolCallID it answers
+	ToolCalls  []ToolCall `json:",omitempty"`
+	ToolCallID string     `json:",omitempty"`
 }
 
 // Config holds the configuration for an LLM client
@@ -27,56 +39,144 @@ type Config struct {
 	Model       string
 	Temperature float64
 	MaxTokens   int
+	TopP        float64 // nucleus sampling; 0 uses the provider default
+	TopK        int     // top-k sampling (not OpenAI-compatible endpoints); 0 uses the default
+
+	// Gemini generation settings
+	ThinkingBudget int             // thinking tokens; 0 uses the model default, -1 lets the model decide
+	SafetySettings []SafetySetting // harm-category block thresholds
+
+	// ResponseFormat requests JSON output; nil leaves responses as free text
+	ResponseFormat *ResponseFormat
+
+	// OpenAI-compatible endpoint settings; empty values use provider defaults
+	BaseURL   string            // e.g. http://localhost:8000/v1 for vLLM
+	APIKeyEnv string            // env var holding the API key (openai provider only)
+	Headers   map[string]string // extra HTTP headers sent with every request
+
+	// Azure OpenAI settings (see NewAzureClient); BaseURL is the resource endpoint
+	Deployment string // deployment name; empty uses Model
+	APIVersion string // api-version; empty uses AZURE_OPENAI_API_VERSION or DefaultAzureAPIVersion
+
+	// Credentials supplies API keys besides the provider's environment
+	// variables; nil reads the environment only
+	Credentials *CredentialSources
+
+	// Command is the plugin binary and its arguments (exec provider only);
+	// empty uses DIALECTA_EXEC_COMMAND
+	Command []string
+
+	Retry     RetryPolicy // zero value disables retries
+	Cache     *Cache      // nil disables response caching
+	RateLimit RateLimit   // shared by all clients of the same provider and API key
+	Timeouts  Timeouts    // zero value waits forever
+
+	// HTTPClient carries all provider traffic; nil uses a fresh default
+	// client. Inject one for proxies or to record/replay tests (see Cassette);
+	// Timeouts.Connect does not apply to an injected client.
+	HTTPClient *http.Client
+}
+
+// httpClient returns the configured HTTP client or a default one that
+// applies Timeouts.Connect. http.Client.Timeout stays unset: it would cut
+// off long streams, which WithTimeouts watches instead.
+func (cfg Config) httpClient() *http.Client {
+	if cfg.HTTPClient != nil {
+		return cfg.HTTPClient
+	}
+	if cfg.Timeouts.Connect > 0 {
+		return &http.Client{Transport: cfg.connectTransport()}
+	}
+	return &http.Client{}
 }
 
 // Client is the interface for LLM clients
 type Client interface {
 	Chat(ctx context.Context, messages []Message) (string, error)
 	ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error)
-}
 
-// NewClient creates a new LLM client based on the provider
-func NewClient(cfg Config) (Client, error) {
-	switch cfg.Provider {
-	case ProviderDeepSeek:
-		apiKey := os.Getenv("DEEPSEEK_API_KEY")
-		if apiKey == "" {
-			return nil, fmt.Errorf("DEEPSEEK_API_KEY environment variable is required")
-		}
-		return NewDeepSeekClient(apiKey, cfg), nil
+	// ChatTools offers tools to the model and returns its reply, which
+	// either requests ToolCalls or answers in Content. Providers without
+	// function calling return ErrToolsUnsupported.
+	ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error)
 
-	case ProviderGemini:
-		apiKey := os.Getenv("GEMINI_API_KEY")
-		if apiKey == "" {
-			apiKey = os.Getenv("GOOGLE_API_KEY")
-		}
-		if apiKey == "" {
-			return nil, fmt.Errorf("GEMINI_API_KEY or GOOGLE_API_KEY environment variable is required")
-		}
-		return NewGeminiClient(apiKey, cfg), nil
+	// Usage returns the tokens consumed by all calls made through this client,
+	// as reported by the provider. Providers that report nothing yield zeros.
+	Usage() Usage
 
-	case ProviderDashScope:
-		apiKey := os.Getenv("DASHSCOPE_API_KEY")
-		if apiKey == "" {
-			return nil, fmt.Errorf("DASHSCOPE_API_KEY environment variable is required")
-		}
-		return NewDashScopeClient(apiKey, cfg), nil
+	// Close releases resources held for the client's lifetime, such as the
+	// Gemini SDK connection. The client must not be used afterwards.
+	Close() error
+}
 
-	default:
+// NewClient creates a new LLM client based on the provider.
+// Every request waits for the process-wide rate limiter of the provider
+// account (see WithRateLimit), including each retry attempt, and each
+// attempt gets its own cfg.Timeouts once it is admitted. With several API
+// keys, calls rotate through them and move on from a key the provider
+// rejects (see withKeyRotation). The client is wrapped with cfg.Retry when
+// retries are enabled, and with cfg.Cache when caching is enabled; cache
+// hits skip retries and the rate limiter entirely.
+func NewClient(cfg Config) (Client, error) {
+	spec, ok := LookupProvider(string(cfg.Provider))
+	if !ok {
 		return nil, fmt.Errorf("unsupported provider: %s", cfg.Provider)
 	}
+	apiKeys, cfg, err := spec.Credentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(apiKeys) == 0 {
+		apiKeys = []string{""}
+	}
+
+	clients := make([]Client, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		client, err := spec.New(apiKey, cfg)
+		if err != nil {
+			for _, c := range clients {
+				c.Close()
+			}
+			return nil, err
+		}
+		client = WithTimeouts(client, cfg.Provider, cfg.Model, cfg.Timeouts)
+		clients = append(clients, WithRateLimit(client, cfg.Provider, apiKey, cfg.RateLimit))
+	}
+	client := withKeyRotation(cfg.Provider, apiKeys, clients)
+	return WithCache(WithRetry(client, cfg.Retry), cfg, cfg.Cache), nil
 }
 
-// ParseProvider parses a provider string
+// ParseProvider parses a registered provider name or alias
 func ParseProvider(s string) (Provider, error) {
-	switch s {
-	case "deepseek":
-		return ProviderDeepSeek, nil
-	case "gemini", "google":
-		return ProviderGemini, nil
-	case "dashscope", "qwen", "alibaba":
-		return ProviderDashScope, nil
-	default:
-		return "", fmt.Errorf("unknown provider: %s (supported: deepseek, gemini, dashscope)", s)
+	spec, ok := LookupProvider(s)
+	if !ok {
+		return "", fmt.Errorf("unknown provider: %s (supported: %s)", s, ProviderNames())
+	}
+	return spec.Name, nil
+}
+
+// ResolveOpenAIEndpoint returns the API keys and base URL for the generic
+// openai provider. The keys are read from cfg.APIKeyEnv (default
+// OPENAI_API_KEY) or cfg.Credentials, and the base URL falls back to
+// OPENAI_BASE_URL. A key is only required when talking to the public OpenAI
+// API or when APIKeyEnv is set explicitly, since local servers such as vLLM
+// or LM Studio usually run without one.
+func ResolveOpenAIEndpoint(cfg Config) (apiKeys []string, baseURL string, err error) {
+	baseURL = cfg.BaseURL
+	if baseURL == "" {
+		baseURL = os.Getenv("OPENAI_BASE_URL")
+	}
+
+	keyEnv := cfg.APIKeyEnv
+	if keyEnv == "" {
+		keyEnv = "OPENAI_API_KEY"
+	}
+	apiKeys, err = cfg.Credentials.Keys(ProviderOpenAI, []string{keyEnv})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(apiKeys) == 0 && (baseURL == "" || cfg.APIKeyEnv != "") {
+		return nil, "", cfg.Credentials.missingKeyError(ProviderOpenAI, []string{keyEnv})
 	}
+	return apiKeys, baseURL, nil
 }
@@ -48,6 +48,54 @@ func TestParseProvider(t *testing.T) {
 			want:    ProviderDashScope,
 			wantErr: false,
 		},
+		{
+			name:    "openai",
+			input:   "openai",
+			want:    ProviderOpenAI,
+			wantErr: false,
+		},
+		{
+			name:    "openai-compatible alias for openai",
+			input:   "openai-compatible",
+			want:    ProviderOpenAI,
+			wantErr: false,
+		},
+		{
+			name:    "anthropic",
+			input:   "anthropic",
+			want:    ProviderAnthropic,
+			wantErr: false,
+		},
+		{
+			name:    "claude alias for anthropic",
+			input:   "claude",
+			want:    ProviderAnthropic,
+			wantErr: false,
+		},
+		{
+			name:    "ollama",
+			input:   "ollama",
+			want:    ProviderOllama,
+			wantErr: false,
+		},
+		{
+			name:    "mock",
+			input:   "mock",
+			want:    ProviderMock,
+			wantErr: false,
+		},
+		{
+			name:    "azure alias",
+			input:   "azure-openai",
+			want:    ProviderAzure,
+			wantErr: false,
+		},
+		{
+			name:    "plugin alias",
+			input:   "plugin",
+			want:    ProviderExec,
+			wantErr: false,
+		},
 		{
 			name:    "unknown provider",
 			input:   "unknown",
@@ -82,7 +130,9 @@ func TestNewClient(t *testing.T) {
 	origGemini := os.Getenv("GEMINI_API_KEY")
 	origGoogle := os.Getenv("GOOGLE_API_KEY")
 	origDashScope := os.Getenv("DASHSCOPE_API_KEY")
+	origAnthropic := os.Getenv("ANTHROPIC_API_KEY")
 	defer func() {
+		os.Setenv("ANTHROPIC_API_KEY", origAnthropic)
 		os.Setenv("DEEPSEEK_API_KEY", origDeepSeek)
 		os.Setenv("GEMINI_API_KEY", origGemini)
 		os.Setenv("GOOGLE_API_KEY", origGoogle)
@@ -172,6 +222,42 @@ func TestNewClient(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Anthropic with key",
+			setup: func() {
+				os.Setenv("ANTHROPIC_API_KEY", "test-key")
+			},
+			cfg: Config{
+				Provider: ProviderAnthropic,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Anthropic without key",
+			setup: func() {},
+			cfg: Config{
+				Provider: ProviderAnthropic,
+			},
+			wantErr: true,
+		},
+		{
+			name:  "Ollama needs no key",
+			setup: func() {},
+			cfg: Config{
+				Provider: ProviderOllama,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "OpenAI-compatible local server without key",
+			setup: func() {},
+			cfg: Config{
+				Provider: ProviderOpenAI,
+				Model:    "llama3",
+				BaseURL:  "http://localhost:8000/v1",
+			},
+			wantErr: false,
+		},
 		{
 			name:  "Unknown provider",
 			setup: func() {},
@@ -189,6 +275,9 @@ func TestNewClient(t *testing.T) {
 			os.Unsetenv("GEMINI_API_KEY")
 			os.Unsetenv("GOOGLE_API_KEY")
 			os.Unsetenv("DASHSCOPE_API_KEY")
+			os.Unsetenv("OPENAI_API_KEY")
+			os.Unsetenv("OPENAI_BASE_URL")
+			os.Unsetenv("ANTHROPIC_API_KEY")
 
 			tt.setup()
 
@@ -215,6 +304,12 @@ func TestProviderConstants(t *testing.T) {
 	if ProviderDashScope != "dashscope" {
 		t.Errorf("ProviderDashScope = %v, want %v", ProviderDashScope, "dashscope")
 	}
+	if ProviderOpenAI != "openai" {
+		t.Errorf("ProviderOpenAI = %v, want %v", ProviderOpenAI, "openai")
+	}
+	if ProviderAnthropic != "anthropic" {
+		t.Errorf("ProviderAnthropic = %v, want %v", ProviderAnthropic, "anthropic")
+	}
 }
 
 func TestMessage(t *testing.T) {
@@ -0,0 +1,262 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables selecting where API keys come from besides the
+// provider's own variables (see CredentialSources)
+const (
+	CredentialsFileEnv  = "DIALECTA_CREDENTIALS_FILE"
+	CredentialHelperEnv = "DIALECTA_CREDENTIAL_HELPER"
+	ProfileEnv          = "DIALECTA_PROFILE"
+)
+
+// DefaultProfile is the key set used when no profile is selected
+const DefaultProfile = "default"
+
+// credentialHelperTimeout bounds one run of the credential helper
+const credentialHelperTimeout = 30 * time.Second
+
+// DefaultCredentialsFile returns the credentials file read when none is
+// configured, if it exists
+func DefaultCredentialsFile() string {
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "dialecta", "credentials")
+	}
+	return filepath.Join(".dialecta", "credentials")
+}
+
+// CredentialSources looks up API keys in the environment, a credentials
+// file and a credential helper command. A provider may have several keys;
+// NewClient rotates through them (see withKeyRotation).
+//
+// The sources are tried in this order, and the first with a key wins:
+//
+//  1. the provider's environment variables, only when no profile is
+//     selected: a selected profile names a key set, so it is not
+//     overridden by whatever key the shell happens to export
+//  2. the profile's keys in the credentials file (DefaultProfile when none
+//     is selected)
+//  3. the credential helper, asked for the same profile
+//
+// An environment variable holds one key, as is. Several keys go in the
+// variable of the same name with an S appended (DEEPSEEK_API_KEYS for
+// DEEPSEEK_API_KEY), separated by commas; it is read before the single-key
+// variable.
+//
+// The credentials file holds one key set per profile. It must not be
+// accessible by other users:
+//
+//	# ~/.config/dialecta/credentials
+//	[default]
+//	deepseek = sk-first, sk-second
+//	dashscope = sk-qwen
+//
+//	[work]
+//	deepseek = sk-work
+//
+// Providers are named as in the provider registry, without aliases.
+//
+// The helper works like a git credential helper: it is run with the extra
+// argument "get", reads "provider=<name>" and "profile=<name>" lines on
+// stdin and prints one "key=<key>" line per key. Printing nothing means it
+// has no key for the provider.
+type CredentialSources struct {
+	File    string   // credentials file; empty for none
+	Helper  []string // helper command and arguments; empty for none
+	Profile string   // key set to use; empty tries the environment first, then DefaultProfile
+
+	mu      sync.Mutex
+	loaded  bool
+	file    map[string]map[Provider][]string // keys from File, by profile
+	fileErr error
+	helper  map[Provider]helperResult
+}
+
+type helperResult struct {
+	keys []string
+	err  error
+}
+
+// NewCredentialSources creates sources reading file and helper; either may
+// be empty
+func NewCredentialSources(file string, helper []string, profile string) *CredentialSources {
+	return &CredentialSources{File: file, Helper: helper, Profile: profile}
+}
+
+// Keys returns the API keys for provider, in rotation order, or none when
+// no source has one, trying the sources in the order CredentialSources
+// gives. The environment is read from the first of envVars that is set,
+// together with its key list variable. A nil *CredentialSources reads the
+// environment only.
+func (s *CredentialSources) Keys(provider Provider, envVars []string) ([]string, error) {
+	if s == nil || s.Profile == "" {
+		for _, env := range envVars {
+			if keys := splitKeys(os.Getenv(KeyListEnv(env))); len(keys) > 0 {
+				return keys, nil
+			}
+			if key := os.Getenv(env); key != "" {
+				return []string{key}, nil
+			}
+		}
+	}
+	if s == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.File != "" {
+		if !s.loaded {
+			s.file, s.fileErr = readCredentialsFile(s.File)
+			s.loaded = true
+		}
+		if s.fileErr != nil {
+			return nil, s.fileErr
+		}
+		if keys := s.file[s.profile()][provider]; len(keys) > 0 {
+			return keys, nil
+		}
+	}
+	if len(s.Helper) > 0 {
+		result, ok := s.helper[provider]
+		if !ok {
+			result.keys, result.err = s.runHelper(provider)
+			if s.helper == nil {
+				s.helper = make(map[Provider]helperResult)
+			}
+			s.helper[provider] = result
+		}
+		return result.keys, result.err
+	}
+	return nil, nil
+}
+
+// KeyListEnv names the variable listing several keys, separated by commas,
+// for the single-key variable env
+func KeyListEnv(env string) string {
+	return env + "S"
+}
+
+func (s *CredentialSources) profile() string {
+	if s.Profile == "" {
+		return DefaultProfile
+	}
+	return s.Profile
+}
+
+// missingKeyError describes where a key for provider was looked for
+func (s *CredentialSources) missingKeyError(provider Provider, envVars []string) error {
+	env := fmt.Sprintf("%s environment variable is required", strings.Join(envVars, " or "))
+	switch {
+	case s == nil || (s.File == "" && len(s.Helper) == 0 && s.Profile == ""):
+		return fmt.Errorf("%s", env)
+	case s.Profile != "":
+		return fmt.Errorf("no %s API key in profile %q (%s)", provider, s.Profile, s.describe())
+	}
+	return fmt.Errorf("%s, or a %s key in profile %q (%s)", env, provider, DefaultProfile, s.describe())
+}
+
+// describe names the configured file and helper for error messages
+func (s *CredentialSources) describe() string {
+	var parts []string
+	if s.File != "" {
+		parts = append(parts, "credentials file "+s.File)
+	}
+	if len(s.Helper) > 0 {
+		parts = append(parts, "credential helper "+s.Helper[0])
+	}
+	if len(parts) == 0 {
+		return "no credentials file or helper configured"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// runHelper asks the helper for the keys of provider
+func (s *CredentialSources) runHelper(provider Provider) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialHelperTimeout)
+	defer cancel()
+
+	args := append(append([]string(nil), s.Helper[1:]...), "get")
+	cmd := exec.CommandContext(ctx, s.Helper[0], args...)
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("provider=%s\nprofile=%s\n\n", provider, s.profile()))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("credential helper %s: %w: %s", s.Helper[0], err, msg)
+		}
+		return nil, fmt.Errorf("credential helper %s: %w", s.Helper[0], err)
+	}
+
+	var keys []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if key, ok := strings.CutPrefix(strings.TrimSpace(line), "key="); ok && key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// readCredentialsFile parses a credentials file. Entries before the first
+// [profile] belong to DefaultProfile.
+func readCredentialsFile(path string) (map[string]map[Provider][]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("credentials file: %w", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("credentials file %s is accessible by other users (mode %04o); run chmod 600 %s", path, info.Mode().Perm(), path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("credentials file: %w", err)
+	}
+
+	profiles := make(map[string]map[Provider][]string)
+	profile := DefaultProfile
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "["); ok && strings.HasSuffix(name, "]") {
+			profile = strings.TrimSpace(strings.TrimSuffix(name, "]"))
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("credentials file %s:%d: want provider = key[, key...]", path, n)
+		}
+		provider := Provider(strings.TrimSpace(name))
+		if profiles[profile] == nil {
+			profiles[profile] = make(map[Provider][]string)
+		}
+		profiles[profile][provider] = append(profiles[profile][provider], splitKeys(value)...)
+	}
+	return profiles, scanner.Err()
+}
+
+// splitKeys splits a comma-separated key list
+func splitKeys(s string) []string {
+	var keys []string
+	for _, key := range strings.Split(s, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,169 @@
+package llm
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeCredentials writes a credentials file readable by its owner only
+func writeCredentials(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const testCredentials = `
+# keys for tests
+deepseek = ds-1, ds-2
+deepseek = ds-3
+
+[work]
+deepseek = ds-work
+gemini=gm-work
+`
+
+func TestCredentialSources_Keys(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	file := writeCredentials(t, testCredentials)
+
+	tests := []struct {
+		name      string
+		sources   *CredentialSources
+		env, list string
+		want      []string
+	}{
+		{"env only", nil, "env-1", "", []string{"env-1"}},
+		{"env key kept as is", nil, "env-1,env-2", "", []string{"env-1,env-2"}},
+		{"env key list", nil, "", "env-1, env-2,", []string{"env-1", "env-2"}},
+		{"key list before key", nil, "env-0", "env-1,env-2", []string{"env-1", "env-2"}},
+		{"nil without env", nil, "", "", nil},
+		{"env before file", NewCredentialSources(file, nil, ""), "env-1", "", []string{"env-1"}},
+		{"default profile", NewCredentialSources(file, nil, ""), "", "", []string{"ds-1", "ds-2", "ds-3"}},
+		{"profile skips env", NewCredentialSources(file, nil, "work"), "env-1", "env-2", []string{"ds-work"}},
+		{"unknown profile", NewCredentialSources(file, nil, "home"), "env-1", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEEPSEEK_API_KEY", tt.env)
+			t.Setenv("DEEPSEEK_API_KEYS", tt.list)
+			got, err := tt.sources.Keys(ProviderDeepSeek, []string{"DEEPSEEK_API_KEY"})
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Keys() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialSources_FileErrors(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	tests := []struct {
+		name string
+		file func(t *testing.T) string
+		want string
+	}{
+		{"missing", func(t *testing.T) string { return filepath.Join(t.TempDir(), "nope") }, "no such file"},
+		{"bad line", func(t *testing.T) string { return writeCredentials(t, "[default]\nsk-without-provider\n") }, ":2: want provider = key"},
+	}
+	if runtime.GOOS != "windows" {
+		tests = append(tests, struct {
+			name string
+			file func(t *testing.T) string
+			want string
+		}{"readable by others", func(t *testing.T) string {
+			path := writeCredentials(t, testCredentials)
+			if err := os.Chmod(path, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			return path
+		}, "accessible by other users (mode 0644)"})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCredentialSources(tt.file(t), nil, "").Keys(ProviderDeepSeek, []string{"DEEPSEEK_API_KEY"})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Keys() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestCredentialHelper is not a test: it is the credential helper the tests
+// run, answering "<provider>-<profile>-1" and "-2" for every provider but
+// anthropic
+func TestCredentialHelper(t *testing.T) {
+	mode := os.Getenv("DIALECTA_TEST_CREDENTIAL_HELPER")
+	if mode == "" {
+		t.Skip("credential helper")
+	}
+	if mode == "fail" || os.Args[len(os.Args)-1] != "get" {
+		fmt.Fprintln(os.Stderr, "vault is locked")
+		os.Exit(1)
+	}
+	attrs := make(map[string]string)
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() && in.Text() != "" {
+		name, value, _ := strings.Cut(in.Text(), "=")
+		attrs[name] = value
+	}
+	if attrs["provider"] != "anthropic" {
+		fmt.Printf("key=%s-%s-1\nkey=%[1]s-%[2]s-2\n", attrs["provider"], attrs["profile"])
+	}
+	os.Exit(0)
+}
+
+func TestCredentialSources_Helper(t *testing.T) {
+	t.Setenv("DIALECTA_TEST_CREDENTIAL_HELPER", "ok")
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	helper := []string{os.Args[0], "-test.run=^TestCredentialHelper$"}
+
+	// The file answers first; the helper covers what it lacks
+	sources := NewCredentialSources(writeCredentials(t, testCredentials), helper, "work")
+	for provider, want := range map[Provider][]string{
+		ProviderDeepSeek:  {"ds-work"},
+		ProviderDashScope: {"dashscope-work-1", "dashscope-work-2"},
+		ProviderAnthropic: nil,
+	} {
+		got, err := sources.Keys(provider, nil)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Keys(%s) = %q, %v; want %q", provider, got, err, want)
+		}
+	}
+
+	t.Setenv("DIALECTA_TEST_CREDENTIAL_HELPER", "fail")
+	_, err := NewCredentialSources("", helper, "").Keys(ProviderDeepSeek, nil)
+	if err == nil || !strings.Contains(err.Error(), "vault is locked") {
+		t.Errorf("Keys() error = %v, want the helper's stderr", err)
+	}
+}
+
+func TestProviderSpec_Credentials_Sources(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	deepseek, _ := LookupProvider("deepseek")
+	file := writeCredentials(t, testCredentials)
+
+	keys, _, err := deepseek.Credentials(Config{Credentials: NewCredentialSources(file, nil, "work")})
+	if err != nil || !reflect.DeepEqual(keys, []string{"ds-work"}) {
+		t.Errorf("Credentials() = %q, %v; want the work profile", keys, err)
+	}
+
+	_, _, err = deepseek.Credentials(Config{Credentials: NewCredentialSources(file, nil, "home")})
+	if err == nil || err.Error() != fmt.Sprintf("no deepseek API key in profile \"home\" (credentials file %s)", file) {
+		t.Errorf("Credentials() error = %v", err)
+	}
+
+	dashscope, _ := LookupProvider("dashscope")
+	t.Setenv("DASHSCOPE_API_KEY", "")
+	_, _, err = dashscope.Credentials(Config{Credentials: NewCredentialSources(file, nil, "")})
+	if err == nil || !strings.HasPrefix(err.Error(), "DASHSCOPE_API_KEY environment variable is required, or a dashscope key") {
+		t.Errorf("Credentials() error = %v", err)
+	}
+}
@@ -75,9 +75,13 @@ func TestDashScopeClient_ConfigPreserved(t *testing.T) {
 func TestDashScopeClient_HandleStream(t *testing.T) {
 	// Test the handleStream function with mock data
 	streamData := `data: {"choices":[{"delta":{"content":"你好"}}]}
+
 data: {"choices":[{"delta":{"content":"世界"}}]}
+
 data: {"choices":[{"delta":{"content":"！"}}]}
+
 data: [DONE]
+
 `
 
 	client := &DashScopeClient{}
@@ -87,7 +91,7 @@ data: [DONE]
 		chunks = append(chunks, s)
 	}
 
-	result, err := client.handleStream(strings.NewReader(streamData), onChunk)
+	result, _, err := client.handleStream(strings.NewReader(streamData), onChunk, nil)
 
 	if err != nil {
 		t.Errorf("handleStream() error = %v", err)
@@ -104,12 +108,15 @@ data: [DONE]
 
 func TestDashScopeClient_HandleStream_EmptyChoices(t *testing.T) {
 	streamData := `data: {"choices":[]}
+
 data: {"choices":[{"delta":{"content":"test"}}]}
+
 data: [DONE]
+
 `
 
 	client := &DashScopeClient{}
-	result, err := client.handleStream(strings.NewReader(streamData), nil)
+	result, _, err := client.handleStream(strings.NewReader(streamData), nil, nil)
 
 	if err != nil {
 		t.Errorf("handleStream() error = %v", err)
@@ -121,19 +128,21 @@ data: [DONE]
 }
 
 func TestDashScopeClient_HandleStream_InvalidJSON(t *testing.T) {
-	streamData := `data: invalid json
-data: {"choices":[{"delta":{"content":"valid"}}]}
+	streamData := `data: {"choices":[{"delta":{"content":"valid"}}]}
+
+data: invalid json
+
 data: [DONE]
+
 `
 
 	client := &DashScopeClient{}
-	result, err := client.handleStream(strings.NewReader(streamData), nil)
+	result, _, err := client.handleStream(strings.NewReader(streamData), nil, nil)
 
-	if err != nil {
-		t.Errorf("handleStream() error = %v", err)
+	// A corrupt chunk fails the stream instead of silently losing content
+	if err == nil {
+		t.Error("handleStream() error = nil, want decode error")
 	}
-
-	// Should skip invalid JSON and continue
 	if result != "valid" {
 		t.Errorf("handleStream() result = %v, want %v", result, "valid")
 	}
@@ -141,11 +150,13 @@ data: [DONE]
 
 func TestDashScopeClient_HandleStream_NilCallback(t *testing.T) {
 	streamData := `data: {"choices":[{"delta":{"content":"test"}}]}
+
 data: [DONE]
+
 `
 
 	client := &DashScopeClient{}
-	result, err := client.handleStream(strings.NewReader(streamData), nil)
+	result, _, err := client.handleStream(strings.NewReader(streamData), nil, nil)
 
 	if err != nil {
 		t.Errorf("handleStream() error = %v", err)
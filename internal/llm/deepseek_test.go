@@ -130,9 +130,13 @@ func TestDeepSeekClient_Chat_MockServer(t *testing.T) {
 func TestDeepSeekClient_HandleStream(t *testing.T) {
 	// Test the handleStream function with mock data
 	streamData := `data: {"choices":[{"delta":{"content":"Hello"}}]}
+
 data: {"choices":[{"delta":{"content":" World"}}]}
+
 data: {"choices":[{"delta":{"content":"!"}}]}
+
 data: [DONE]
+
 `
 
 	client := &DeepSeekClient{}
@@ -142,7 +146,7 @@ data: [DONE]
 		chunks = append(chunks, s)
 	}
 
-	result, err := client.handleStream(strings.NewReader(streamData), onChunk)
+	result, _, err := client.handleStream(strings.NewReader(streamData), onChunk, nil)
 
 	if err != nil {
 		t.Errorf("handleStream() error = %v", err)
@@ -159,12 +163,15 @@ data: [DONE]
 
 func TestDeepSeekClient_HandleStream_EmptyChoices(t *testing.T) {
 	streamData := `data: {"choices":[]}
+
 data: {"choices":[{"delta":{"content":"test"}}]}
+
 data: [DONE]
+
 `
 
 	client := &DeepSeekClient{}
-	result, err := client.handleStream(strings.NewReader(streamData), nil)
+	result, _, err := client.handleStream(strings.NewReader(streamData), nil, nil)
 
 	if err != nil {
 		t.Errorf("handleStream() error = %v", err)
@@ -176,19 +183,21 @@ data: [DONE]
 }
 
 func TestDeepSeekClient_HandleStream_InvalidJSON(t *testing.T) {
-	streamData := `data: invalid json
-data: {"choices":[{"delta":{"content":"valid"}}]}
+	streamData := `data: {"choices":[{"delta":{"content":"valid"}}]}
+
+data: invalid json
+
 data: [DONE]
+
 `
 
 	client := &DeepSeekClient{}
-	result, err := client.handleStream(strings.NewReader(streamData), nil)
+	result, _, err := client.handleStream(strings.NewReader(streamData), nil, nil)
 
-	if err != nil {
-		t.Errorf("handleStream() error = %v", err)
+	// A corrupt chunk fails the stream instead of silently losing content
+	if err == nil {
+		t.Error("handleStream() error = nil, want decode error")
 	}
-
-	// Should skip invalid JSON and continue
 	if result != "valid" {
 		t.Errorf("handleStream() result = %v, want %v", result, "valid")
 	}
@@ -196,13 +205,17 @@ data: [DONE]
 
 func TestDeepSeekClient_HandleStream_NoDataPrefix(t *testing.T) {
 	streamData := `some random line
+
 data: {"choices":[{"delta":{"content":"test"}}]}
+
 another random line
+
 data: [DONE]
+
 `
 
 	client := &DeepSeekClient{}
-	result, err := client.handleStream(strings.NewReader(streamData), nil)
+	result, _, err := client.handleStream(strings.NewReader(streamData), nil, nil)
 
 	if err != nil {
 		t.Errorf("handleStream() error = %v", err)
@@ -215,11 +228,13 @@ data: [DONE]
 
 func TestDeepSeekClient_HandleStream_NilCallback(t *testing.T) {
 	streamData := `data: {"choices":[{"delta":{"content":"test"}}]}
+
 data: [DONE]
+
 `
 
 	client := &DeepSeekClient{}
-	result, err := client.handleStream(strings.NewReader(streamData), nil)
+	result, _, err := client.handleStream(strings.NewReader(streamData), nil, nil)
 
 	if err != nil {
 		t.Errorf("handleStream() error = %v", err)
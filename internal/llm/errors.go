@@ -0,0 +1,230 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/googleapi"
+)
+
+// APIError is returned when a provider rejects a request. Callers that need
+// to react to a specific failure should check for the typed errors below
+// (AuthError, RateLimitError, ContextLengthError, ContentFilterError,
+// ServerError) with errors.As; every one of them also unwraps to *APIError.
+type APIError struct {
+	Provider   Provider
+	Model      string
+	StatusCode int           // HTTP status, zero for errors reported mid-stream
+	Type       string        // provider error type, e.g. "invalid_request_error"
+	Code       string        // provider error code, e.g. "context_length_exceeded"
+	Message    string        // human readable message parsed from the body
+	Body       string        // raw response body
+	RetryAfter time.Duration // parsed Retry-After header, zero if absent
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Body
+	}
+
+	var prefix string
+	switch {
+	case e.Provider != "" && e.Model != "":
+		prefix = fmt.Sprintf("%s (%s): ", e.Provider, e.Model)
+	case e.Provider != "":
+		prefix = fmt.Sprintf("%s: ", e.Provider)
+	}
+
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%sAPI error: %s", prefix, msg)
+	}
+	return fmt.Sprintf("%sAPI error (status %d): %s", prefix, e.StatusCode, msg)
+}
+
+// AuthError indicates a missing, invalid, or unauthorised API key
+type AuthError struct{ *APIError }
+
+// RateLimitError indicates the provider throttled the request or the quota ran out
+type RateLimitError struct{ *APIError }
+
+// ContextLengthError indicates the prompt does not fit the model's context window
+type ContextLengthError struct{ *APIError }
+
+// ContentFilterError indicates the provider's safety system blocked the request or response
+type ContentFilterError struct{ *APIError }
+
+// ServerError indicates a failure on the provider side (5xx, overloaded)
+type ServerError struct{ *APIError }
+
+func (e *AuthError) Unwrap() error          { return e.APIError }
+func (e *RateLimitError) Unwrap() error     { return e.APIError }
+func (e *ContextLengthError) Unwrap() error { return e.APIError }
+func (e *ContentFilterError) Unwrap() error { return e.APIError }
+func (e *ServerError) Unwrap() error        { return e.APIError }
+
+// newAPIError builds a typed error from a failed HTTP response, consuming its body
+func newAPIError(resp *http.Response, provider Provider, model string) error {
+	body, _ := io.ReadAll(resp.Body)
+	apiErr := &APIError{
+		Provider:   provider,
+		Model:      model,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+	apiErr.parseBody()
+	return classifyAPIError(apiErr)
+}
+
+// parseBody extracts type, code, and message from the error body shapes used
+// by OpenAI-compatible APIs, Anthropic, DashScope, and Ollama
+func (e *APIError) parseBody() {
+	var nested struct {
+		Error json.RawMessage `json:"error"`
+		Code  string          `json:"code"`
+		Msg   string          `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(e.Body), &nested); err != nil {
+		return
+	}
+
+	var detail struct {
+		Type    string          `json:"type"`
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+	}
+	var plain string
+	switch {
+	case json.Unmarshal(nested.Error, &detail) == nil:
+		e.Type = detail.Type
+		e.Message = detail.Message
+		if code, err := strconv.Unquote(string(detail.Code)); err == nil {
+			e.Code = code
+		} else if len(detail.Code) > 0 && string(detail.Code) != "null" {
+			e.Code = string(detail.Code)
+		}
+	case json.Unmarshal(nested.Error, &plain) == nil:
+		// Ollama: {"error": "model not found"}
+		e.Message = plain
+	default:
+		// DashScope native: {"code": "...", "message": "..."}
+		e.Code = nested.Code
+		e.Message = nested.Msg
+	}
+}
+
+// classifyAPIError wraps apiErr in the most specific typed error
+func classifyAPIError(apiErr *APIError) error {
+	kind := strings.ToLower(apiErr.Type + " " + apiErr.Code)
+	msg := strings.ToLower(apiErr.Message + " " + apiErr.Body)
+
+	switch {
+	case apiErr.StatusCode == http.StatusUnauthorized,
+		apiErr.StatusCode == http.StatusForbidden,
+		strings.Contains(kind, "authentication_error"),
+		strings.Contains(kind, "permission_error"),
+		strings.Contains(kind, "invalid_api_key"):
+		return &AuthError{apiErr}
+
+	case apiErr.StatusCode == http.StatusTooManyRequests,
+		strings.Contains(kind, "rate_limit"),
+		strings.Contains(kind, "insufficient_quota"),
+		strings.Contains(kind, "throttling"):
+		return &RateLimitError{apiErr}
+
+	case strings.Contains(kind, "context_length_exceeded"),
+		strings.Contains(msg, "context length"),
+		strings.Contains(msg, "prompt is too long"),
+		strings.Contains(msg, "range of input length"),
+		strings.Contains(msg, "exceeds the maximum number of tokens"),
+		apiErr.StatusCode == http.StatusRequestEntityTooLarge:
+		return &ContextLengthError{apiErr}
+
+	case strings.Contains(kind, "content_filter"),
+		strings.Contains(kind, "data_inspection_failed"),
+		strings.Contains(msg, "content management policy"),
+		strings.Contains(msg, "inappropriate content"):
+		return &ContentFilterError{apiErr}
+
+	case apiErr.StatusCode >= 500,
+		strings.Contains(kind, "overloaded_error"),
+		strings.Contains(kind, "api_error"),
+		strings.Contains(kind, "server_error"):
+		return &ServerError{apiErr}
+	}
+	return apiErr
+}
+
+// parseRetryAfter understands both forms of the Retry-After header:
+// delay-seconds and an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// wrapGeminiError converts Google API and SDK errors into typed errors so
+// that Gemini failures are classified the same way as the HTTP providers
+func wrapGeminiError(err error, model string) error {
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		return &ContentFilterError{&APIError{
+			Provider: ProviderGemini,
+			Model:    model,
+			Type:     "blocked",
+			Message:  blocked.Error(),
+		}}
+	}
+
+	var gErr *googleapi.Error
+	if errors.As(err, &gErr) {
+		apiErr := &APIError{
+			Provider:   ProviderGemini,
+			Model:      model,
+			StatusCode: gErr.Code,
+			Message:    gErr.Message,
+			Body:       gErr.Body,
+		}
+		if len(gErr.Errors) > 0 {
+			apiErr.Code = gErr.Errors[0].Reason
+		}
+		if gErr.Header != nil {
+			apiErr.RetryAfter = parseRetryAfter(gErr.Header.Get("Retry-After"), time.Now())
+		}
+		if apiErr.Body != "" && apiErr.Message == "" {
+			apiErr.parseBody()
+		}
+		return classifyAPIError(apiErr)
+	}
+
+	var coded interface{ HTTPCode() int }
+	if errors.As(err, &coded) && coded.HTTPCode() > 0 {
+		return classifyAPIError(&APIError{
+			Provider:   ProviderGemini,
+			Model:      model,
+			StatusCode: coded.HTTPCode(),
+			Message:    err.Error(),
+		})
+	}
+	return err
+}
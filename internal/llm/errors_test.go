@@ -0,0 +1,162 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/googleapi"
+)
+
+func newTestResponse(status int, body string) *http.Response {
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(status)
+	_, _ = io.WriteString(rec, body)
+	return rec.Result()
+}
+
+func TestNewAPIError_Classification(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(error) bool
+	}{
+		{
+			name:   "OpenAI invalid key",
+			status: 401,
+			body:   `{"error":{"message":"Incorrect API key provided","type":"invalid_request_error","code":"invalid_api_key"}}`,
+			check:  func(err error) bool { var e *AuthError; return errors.As(err, &e) },
+		},
+		{
+			name:   "rate limit",
+			status: 429,
+			body:   `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`,
+			check:  func(err error) bool { var e *RateLimitError; return errors.As(err, &e) },
+		},
+		{
+			name:   "OpenAI context length",
+			status: 400,
+			body:   `{"error":{"message":"This model's maximum context length is 8192 tokens","type":"invalid_request_error","code":"context_length_exceeded"}}`,
+			check:  func(err error) bool { var e *ContextLengthError; return errors.As(err, &e) },
+		},
+		{
+			name:   "Anthropic prompt too long",
+			status: 400,
+			body:   `{"type":"error","error":{"type":"invalid_request_error","message":"prompt is too long: 210000 tokens > 200000 maximum"}}`,
+			check:  func(err error) bool { var e *ContextLengthError; return errors.As(err, &e) },
+		},
+		{
+			name:   "DashScope content inspection",
+			status: 400,
+			body:   `{"error":{"code":"data_inspection_failed","message":"Input data may contain inappropriate content.","type":"data_inspection_failed"}}`,
+			check:  func(err error) bool { var e *ContentFilterError; return errors.As(err, &e) },
+		},
+		{
+			name:   "server error",
+			status: 503,
+			body:   `upstream unavailable`,
+			check:  func(err error) bool { var e *ServerError; return errors.As(err, &e) },
+		},
+		{
+			name:   "plain bad request stays APIError",
+			status: 400,
+			body:   `{"error":{"message":"temperature out of range","type":"invalid_request_error"}}`,
+			check: func(err error) bool {
+				var e *APIError
+				_, isAPIErr := err.(*APIError)
+				return errors.As(err, &e) && isAPIErr
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAPIError(newTestResponse(tt.status, tt.body), ProviderDeepSeek, "deepseek-chat")
+			if !tt.check(err) {
+				t.Errorf("newAPIError() = %T (%v), wrong type", err, err)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("typed error should unwrap to *APIError")
+			}
+			if apiErr.Provider != ProviderDeepSeek || apiErr.Model != "deepseek-chat" || apiErr.StatusCode != tt.status {
+				t.Errorf("APIError = %+v, want provider/model/status preserved", apiErr)
+			}
+			if apiErr.Body != tt.body {
+				t.Errorf("APIError.Body = %q, want raw body", apiErr.Body)
+			}
+		})
+	}
+}
+
+func TestAPIError_ParsedBody(t *testing.T) {
+	err := newAPIError(newTestResponse(401, `{"error":{"message":"Incorrect API key provided","type":"invalid_request_error","code":"invalid_api_key"}}`), ProviderOpenAI, "gpt-4o-mini")
+
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("error = %T, want *AuthError", err)
+	}
+	if authErr.Message != "Incorrect API key provided" || authErr.Code != "invalid_api_key" || authErr.Type != "invalid_request_error" {
+		t.Errorf("parsed body = %+v", authErr.APIError)
+	}
+	want := "openai (gpt-4o-mini): API error (status 401): Incorrect API key provided"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestAPIError_OllamaBody(t *testing.T) {
+	err := newAPIError(newTestResponse(404, `{"error":"model 'nope' not found, try pulling it first"}`), ProviderOllama, "nope")
+	if !strings.Contains(err.Error(), "try pulling it first") {
+		t.Errorf("Error() = %q, want Ollama message", err.Error())
+	}
+}
+
+func TestTypedErrors_ThroughWrapping(t *testing.T) {
+	err := fmt.Errorf("affirmative: %w", &RateLimitError{&APIError{Provider: ProviderDashScope, StatusCode: 429}})
+
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatal("errors.As should find RateLimitError through fmt wrapping")
+	}
+	if rateErr.Provider != ProviderDashScope {
+		t.Errorf("Provider = %v, want %v", rateErr.Provider, ProviderDashScope)
+	}
+	if !IsRetryable(err) {
+		t.Error("rate limit should be retryable")
+	}
+
+	quota := &RateLimitError{&APIError{StatusCode: 429, Code: "insufficient_quota"}}
+	if IsRetryable(quota) {
+		t.Error("an exhausted quota should not be retried")
+	}
+}
+
+func TestWrapGeminiError(t *testing.T) {
+	var rateErr *RateLimitError
+	err := wrapGeminiError(fmt.Errorf("rpc: %w", &googleapi.Error{Code: 429, Message: "Resource has been exhausted"}), "gemini-pro")
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("wrapGeminiError() = %T, want *RateLimitError", err)
+	}
+	if rateErr.Provider != ProviderGemini || rateErr.Model != "gemini-pro" {
+		t.Errorf("APIError = %+v", rateErr.APIError)
+	}
+
+	var filterErr *ContentFilterError
+	err = wrapGeminiError(&genai.BlockedError{Candidate: &genai.Candidate{FinishReason: genai.FinishReasonSafety}}, "gemini-pro")
+	if !errors.As(err, &filterErr) {
+		t.Errorf("wrapGeminiError(BlockedError) = %T, want *ContentFilterError", err)
+	}
+
+	plain := errors.New("dial tcp: timeout")
+	if got := wrapGeminiError(plain, "gemini-pro"); got != plain {
+		t.Errorf("wrapGeminiError() should pass through unrelated errors, got %v", got)
+	}
+}
@@ -0,0 +1,530 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The exec provider runs an external plugin binary and talks to it in JSON
+// lines: one JSON object per line on the plugin's stdin and stdout. The
+// plugin serves one request at a time and stays running between requests.
+//
+// Each request is a "request" frame with a unique id:
+//
+//	{"type":"request","id":"1","model":"m","messages":[{"role":"user","content":"hi"}],
+//	 "params":{"temperature":0.8,"max_tokens":4096,"stream":true}}
+//
+// params may also carry top_p, top_k and response_format ({"type":
+// "json_schema","name":...,"schema":{...}}). The plugin answers with any
+// number of "chunk" frames and ends the request with exactly one "done" or
+// "error" frame, all carrying the request id:
+//
+//	{"type":"chunk","id":"1","content":"Hel"}
+//	{"type":"chunk","id":"1","reasoning":"thinking..."}
+//	{"type":"done","id":"1","finish_reason":"stop","usage":{"prompt_tokens":12,"completion_tokens":3}}
+//	{"type":"error","id":"1","error":{"message":"slow down","code":"rate_limit","status":429,"retry_after":2}}
+//
+// done may carry a final "content" too. Errors are classified like HTTP
+// provider errors, using status and code. When the caller gives up, the
+// client sends {"type":"cancel","id":"1"}; the plugin should stop and
+// finish the request with done or error. A plugin that does not do so
+// within ExecCancelGrace is killed and restarted for the next request.
+// Anything the plugin writes to stderr is kept for error messages.
+
+// ExecCommandEnv names the variable holding the plugin command line when
+// Config.Command is empty
+const ExecCommandEnv = "DIALECTA_EXEC_COMMAND"
+
+// ExecCancelGrace is how long a cancelled request may take to finish before
+// the plugin is killed
+var ExecCancelGrace = 5 * time.Second
+
+// execFrame is one line of the exec protocol, in either direction
+type execFrame struct {
+	Type string `json:"type"` // request, cancel, chunk, done or error
+	ID   string `json:"id"`
+
+	Model    string        `json:"model,omitempty"`
+	Messages []execMessage `json:"messages,omitempty"`
+	Params   *execParams   `json:"params,omitempty"`
+
+	Content      string     `json:"content,omitempty"`
+	Reasoning    string     `json:"reasoning,omitempty"`
+	FinishReason string     `json:"finish_reason,omitempty"`
+	Usage        *execUsage `json:"usage,omitempty"`
+	Error        *execError `json:"error,omitempty"`
+}
+
+type execMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type execParams struct {
+	Temperature    float64         `json:"temperature"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	TopP           float64         `json:"top_p,omitempty"`
+	TopK           int             `json:"top_k,omitempty"`
+	Stream         bool            `json:"stream"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+type execUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	CachedTokens     int `json:"cached_tokens"`
+}
+
+type execError struct {
+	Message    string `json:"message"`
+	Type       string `json:"type"`
+	Code       string `json:"code"`
+	Status     int    `json:"status"`
+	RetryAfter int    `json:"retry_after"` // seconds
+}
+
+// ResolveExecCommand returns the plugin command line: cfg.Command, or
+// DIALECTA_EXEC_COMMAND split by SplitCommand. The binary must exist.
+func ResolveExecCommand(cfg Config) ([]string, error) {
+	command := cfg.Command
+	if len(command) == 0 {
+		var err error
+		if command, err = SplitCommand(os.Getenv(ExecCommandEnv)); err != nil {
+			return nil, fmt.Errorf("%s: %w", ExecCommandEnv, err)
+		}
+	}
+	if len(command) == 0 {
+		return nil, fmt.Errorf("exec provider needs a plugin command (%s)", ExecCommandEnv)
+	}
+	if _, err := exec.LookPath(command[0]); err != nil {
+		return nil, fmt.Errorf("exec plugin: %w", err)
+	}
+	return command, nil
+}
+
+// SplitCommand splits a command line into its arguments the way a shell
+// splits words: blanks separate arguments, single quotes keep their content
+// as is, double quotes keep blanks and honour \" and \\, and a backslash
+// outside quotes escapes the next character. Nothing else (variables,
+// globs, pipes) is interpreted.
+func SplitCommand(s string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case ch == '\\':
+			if i+1 == len(s) {
+				return nil, errors.New("command ends with a backslash")
+			}
+			i++
+			arg.WriteByte(s[i])
+			inArg = true
+		case ch == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated ' in command")
+			}
+			arg.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inArg = true
+		case ch == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+					i++
+				}
+				arg.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, errors.New("unterminated \" in command")
+			}
+			inArg = true
+		default:
+			arg.WriteByte(ch)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// ExecClient implements Client by talking to a plugin process. The process
+// is started on first use and reused; if it exits, the next call starts a
+// new one. Calls are served one at a time.
+type ExecClient struct {
+	cfg   Config
+	usage usageCounter
+
+	sem    chan struct{} // held by the call talking to the process
+	proc   *execProcess
+	nextID int
+}
+
+// NewExecClient creates a client for the plugin cfg.Command
+func NewExecClient(cfg Config) *ExecClient {
+	cfg.Provider = ProviderExec
+	return &ExecClient{cfg: cfg, sem: make(chan struct{}, 1)}
+}
+
+// Usage returns the tokens the plugin reported
+func (c *ExecClient) Usage() Usage {
+	return c.usage.get()
+}
+
+// Close stops the plugin: its stdin is closed so that it can exit on its
+// own, and it is killed after ExecCancelGrace
+func (c *ExecClient) Close() error {
+	c.sem <- struct{}{}
+	defer func() { <-c.sem }()
+	if c.proc == nil {
+		return nil
+	}
+	c.proc.stop(ExecCancelGrace)
+	c.proc = nil
+	return nil
+}
+
+// ChatTools returns ErrToolsUnsupported; the protocol has no tool frames
+func (c *ExecClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	return Message{}, toolsUnsupported(c.cfg.Provider)
+}
+
+func (c *ExecClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	return c.chat(ctx, messages, false, nil)
+}
+
+func (c *ExecClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	return c.chat(ctx, messages, true, onChunk)
+}
+
+func (c *ExecClient) chat(ctx context.Context, messages []Message, stream bool, onChunk func(string)) (string, error) {
+	select {
+	case c.sem <- struct{}{}:
+		defer func() { <-c.sem }()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	proc, err := c.process()
+	if err != nil {
+		return "", err
+	}
+
+	c.nextID++
+	id := strconv.Itoa(c.nextID)
+	req := execFrame{
+		Type:     "request",
+		ID:       id,
+		Model:    c.cfg.Model,
+		Messages: make([]execMessage, len(messages)),
+		Params: &execParams{
+			Temperature:    c.cfg.Temperature,
+			MaxTokens:      c.cfg.MaxTokens,
+			TopP:           c.cfg.TopP,
+			TopK:           c.cfg.TopK,
+			Stream:         stream,
+			ResponseFormat: c.cfg.ResponseFormat,
+		},
+	}
+	for i, m := range messages {
+		req.Messages[i] = execMessage{Role: m.Role, Content: m.Content}
+	}
+	if err := proc.send(ctx, req); err != nil {
+		c.discard()
+		return "", fmt.Errorf("send request: %w", err)
+	}
+
+	onReasoning := reasoningFunc(ctx)
+	var content strings.Builder
+	for {
+		select {
+		case f, ok := <-proc.frames:
+			if !ok {
+				c.discard()
+				return content.String(), proc.exitError()
+			}
+			if f.ID != id {
+				continue // late frames of an abandoned request
+			}
+			switch f.Type {
+			case "chunk":
+				if f.Content != "" {
+					content.WriteString(f.Content)
+					if onChunk != nil {
+						onChunk(f.Content)
+					}
+				}
+				if f.Reasoning != "" && onReasoning != nil {
+					onReasoning(f.Reasoning)
+				}
+			case "done":
+				content.WriteString(f.Content)
+				if f.Content != "" && onChunk != nil {
+					onChunk(f.Content)
+				}
+				if f.Usage != nil {
+					c.usage.add(Usage{PromptTokens: f.Usage.PromptTokens, CompletionTokens: f.Usage.CompletionTokens, CachedTokens: f.Usage.CachedTokens})
+				}
+				reportFinish(ctx, openAIFinishReason(f.FinishReason))
+				return content.String(), nil
+			case "error":
+				return content.String(), c.frameError(f.Error)
+			}
+
+		case <-ctx.Done():
+			c.cancel(proc, id)
+			return content.String(), ctx.Err()
+		}
+	}
+}
+
+// cancel asks the plugin to stop request id and waits for it to finish
+// the request; a plugin that does not is killed
+func (c *ExecClient) cancel(proc *execProcess, id string) {
+	timer := time.NewTimer(ExecCancelGrace)
+	defer timer.Stop()
+	ctx, stop := context.WithTimeout(context.Background(), ExecCancelGrace)
+	defer stop()
+	if proc.send(ctx, execFrame{Type: "cancel", ID: id}) != nil {
+		c.discard()
+		return
+	}
+	for {
+		select {
+		case f, ok := <-proc.frames:
+			if !ok {
+				c.discard()
+				return
+			}
+			if f.ID == id && (f.Type == "done" || f.Type == "error") {
+				return
+			}
+		case <-timer.C:
+			c.discard()
+			return
+		}
+	}
+}
+
+// frameError turns an error frame into a typed provider error
+func (c *ExecClient) frameError(e *execError) error {
+	if e == nil {
+		e = &execError{Message: "plugin reported an error without details"}
+	}
+	return classifyAPIError(&APIError{
+		Provider:   c.cfg.Provider,
+		Model:      c.cfg.Model,
+		StatusCode: e.Status,
+		Type:       e.Type,
+		Code:       e.Code,
+		Message:    e.Message,
+		RetryAfter: time.Duration(e.RetryAfter) * time.Second,
+	})
+}
+
+// process returns the running plugin, starting it if needed
+func (c *ExecClient) process() (*execProcess, error) {
+	if c.proc != nil && !c.proc.exited() {
+		return c.proc, nil
+	}
+	command, err := ResolveExecCommand(c.cfg)
+	if err != nil {
+		return nil, err
+	}
+	proc, err := startExecProcess(command)
+	if err != nil {
+		return nil, err
+	}
+	c.proc = proc
+	return proc, nil
+}
+
+// discard kills the plugin; the next call starts a fresh one
+func (c *ExecClient) discard() {
+	if c.proc != nil {
+		c.proc.stop(0)
+		c.proc = nil
+	}
+}
+
+// execProcess is one running plugin
+type execProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	frames chan execFrame // closed once stdout ends
+	stderr *tailBuffer
+	quit   chan struct{} // closed by stop; frames are then dropped
+
+	done    chan struct{} // closed once the process has exited
+	waitErr error
+	readErr error
+}
+
+func startExecProcess(command []string) (*execProcess, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	p := &execProcess{
+		cmd:    cmd,
+		stdin:  stdin,
+		frames: make(chan execFrame),
+		stderr: &tailBuffer{max: 4096},
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	cmd.Stderr = p.stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start exec plugin: %w", err)
+	}
+
+	go p.read(stdout)
+	return p, nil
+}
+
+// read decodes stdout into frames until it ends, then reaps the process.
+// Once stop is called nobody receives frames, so they are dropped and
+// stdout is read until the process exits.
+func (p *execProcess) read(stdout io.Reader) {
+	defer func() {
+		p.waitErr = p.cmd.Wait()
+		close(p.frames)
+		close(p.done)
+	}()
+	r := bufio.NewReader(stdout)
+	for {
+		line, err := r.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var f execFrame
+			if jsonErr := json.Unmarshal(line, &f); jsonErr != nil {
+				p.readErr = fmt.Errorf("decode plugin output: %w", jsonErr)
+				_ = p.cmd.Process.Kill()
+				return
+			}
+			select {
+			case p.frames <- f:
+			case <-p.quit:
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				p.readErr = err
+			}
+			return
+		}
+	}
+}
+
+// send writes f to the plugin's stdin. A plugin that stops reading blocks
+// the write, so it gives up when ctx ends or the process exits; the caller
+// then discards the process, which unblocks the write.
+func (p *execProcess) send(ctx context.Context, f execFrame) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	written := make(chan error, 1)
+	go func() {
+		_, err := p.stdin.Write(append(data, '\n'))
+		written <- err
+	}()
+	select {
+	case err := <-written:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.done:
+		return p.exitError()
+	}
+}
+
+func (p *execProcess) exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop closes stdin and kills the process if it has not exited after
+// grace. It must be called once, by the only receiver of frames.
+func (p *execProcess) stop(grace time.Duration) {
+	close(p.quit)
+	_ = p.stdin.Close()
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-p.done:
+	case <-timer.C:
+		_ = p.cmd.Process.Kill()
+		<-p.done
+	}
+}
+
+// exitError describes why the plugin stopped in the middle of a request.
+// Only valid once frames is closed.
+func (p *execProcess) exitError() error {
+	<-p.done
+	msg := "exec plugin exited"
+	switch {
+	case p.readErr != nil:
+		msg += ": " + p.readErr.Error()
+	case p.waitErr != nil:
+		msg += ": " + p.waitErr.Error()
+	default:
+		msg += " before finishing the request"
+	}
+	if tail := strings.TrimSpace(p.stderr.String()); tail != "" {
+		msg += "; stderr: " + tail
+	}
+	return errors.New(msg)
+}
+
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
@@ -0,0 +1,256 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestExecPlugin is not a test: it is the plugin the exec tests launch,
+// re-running the test binary with DIALECTA_EXEC_TEST_PLUGIN set to the
+// behaviour wanted
+func TestExecPlugin(t *testing.T) {
+	mode := os.Getenv("DIALECTA_EXEC_TEST_PLUGIN")
+	if mode == "" {
+		t.Skip("exec plugin helper")
+	}
+	if mode == "deaf" {
+		select {} // never reads a request
+	}
+	out := json.NewEncoder(os.Stdout)
+	served := 0
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		var f execFrame
+		if err := json.Unmarshal(in.Bytes(), &f); err != nil {
+			fmt.Fprintln(os.Stderr, "bad frame:", err)
+			os.Exit(2)
+		}
+		if f.Type == "cancel" {
+			if mode == "hang" {
+				_ = out.Encode(execFrame{Type: "error", ID: f.ID, Error: &execError{Message: "cancelled"}})
+			}
+			continue
+		}
+		served++
+
+		switch mode {
+		case "echo":
+			last := f.Messages[len(f.Messages)-1].Content
+			_ = out.Encode(execFrame{Type: "chunk", ID: f.ID, Reasoning: "thinking"})
+			_ = out.Encode(execFrame{Type: "chunk", ID: f.ID, Content: fmt.Sprintf("#%d ", served)})
+			_ = out.Encode(execFrame{Type: "chunk", ID: f.ID, Content: fmt.Sprintf("%s t=%g stream=%v", last, f.Params.Temperature, f.Params.Stream)})
+			_ = out.Encode(execFrame{Type: "done", ID: f.ID, FinishReason: "length", Usage: &execUsage{PromptTokens: 10, CompletionTokens: 4}})
+		case "error":
+			_ = out.Encode(execFrame{Type: "error", ID: f.ID, Error: &execError{Message: "slow down", Status: 429, RetryAfter: 2}})
+		case "hang", "stubborn":
+			if f.Messages[0].Content != "hang" {
+				_ = out.Encode(execFrame{Type: "done", ID: f.ID, Content: fmt.Sprintf("#%d", served)})
+				continue
+			}
+			_ = out.Encode(execFrame{Type: "chunk", ID: f.ID, Content: "partial"})
+		case "flood":
+			// Keeps streaming after the cancel until it is killed
+			go func() {
+				for {
+					_ = out.Encode(execFrame{Type: "chunk", ID: f.ID, Content: "more"})
+				}
+			}()
+		case "crash":
+			_ = out.Encode(execFrame{Type: "chunk", ID: f.ID, Content: "partial"})
+			fmt.Fprintln(os.Stderr, "model weights not found")
+			os.Exit(3)
+		case "garbage":
+			fmt.Println("Loading model...")
+		}
+	}
+	os.Exit(0)
+}
+
+// execTestClient returns a client running TestExecPlugin in mode
+func execTestClient(t *testing.T, mode string) *ExecClient {
+	t.Helper()
+	t.Setenv("DIALECTA_EXEC_TEST_PLUGIN", mode)
+	client := NewExecClient(Config{Model: "in-house", Temperature: 0.5, Command: []string{os.Args[0], "-test.run=^TestExecPlugin$"}})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestExecClient_ChatStream(t *testing.T) {
+	client := execTestClient(t, "echo")
+	var chunks []string
+	var reasoning string
+	var finish FinishReason
+	ctx := WithReasoning(context.Background(), func(s string) { reasoning += s })
+	ctx = WithFinishReason(ctx, func(r FinishReason) { finish = r })
+
+	got, err := client.ChatStream(ctx, []Message{{Role: "user", Content: "hi"}}, func(s string) { chunks = append(chunks, s) })
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if got != "#1 hi t=0.5 stream=true" || len(chunks) != 2 {
+		t.Errorf("ChatStream() = %q in %d chunks", got, len(chunks))
+	}
+	if reasoning != "thinking" || finish != FinishLength {
+		t.Errorf("reasoning = %q, finish = %q", reasoning, finish)
+	}
+
+	// The same process serves the next request
+	got, err = client.Chat(context.Background(), []Message{{Role: "user", Content: "again"}})
+	if err != nil || got != "#2 again t=0.5 stream=false" {
+		t.Errorf("Chat() = %q, %v; want the second request of the same plugin", got, err)
+	}
+	if u := client.Usage(); u.PromptTokens != 20 || u.CompletionTokens != 8 {
+		t.Errorf("Usage() = %+v, want the reported usage of both requests", u)
+	}
+}
+
+func TestExecClient_ErrorFrame(t *testing.T) {
+	client := execTestClient(t, "error")
+	_, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) || rateErr.RetryAfter != 2*time.Second || rateErr.Provider != ProviderExec {
+		t.Errorf("Chat() error = %v, want a RateLimitError with Retry-After", err)
+	}
+}
+
+func TestExecClient_Cancel(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string // answer to the request after the cancelled one
+	}{
+		{"hang", "#2"},     // the plugin finishes the cancelled request and is reused
+		{"stubborn", "#1"}, // the plugin ignores the cancel, so it is replaced
+	}
+	defer func(grace time.Duration) { ExecCancelGrace = grace }(ExecCancelGrace)
+	ExecCancelGrace = 100 * time.Millisecond
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			client := execTestClient(t, tt.mode)
+			ctx, cancel := context.WithCancel(context.Background())
+			got, err := client.ChatStream(ctx, []Message{{Role: "user", Content: "hang"}}, func(string) { cancel() })
+			if !errors.Is(err, context.Canceled) || got != "partial" {
+				t.Fatalf("ChatStream() = %q, %v; want the partial answer and context.Canceled", got, err)
+			}
+
+			got, err = client.Chat(context.Background(), []Message{{Role: "user", Content: "next"}})
+			if err != nil || got != tt.want {
+				t.Errorf("next Chat() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecClient_CancelFlood(t *testing.T) {
+	defer func(grace time.Duration) { ExecCancelGrace = grace }(ExecCancelGrace)
+	ExecCancelGrace = 100 * time.Millisecond
+
+	client := execTestClient(t, "flood")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.ChatStream(ctx, []Message{{Role: "user", Content: "hang"}}, func(string) { cancel() })
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ChatStream() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("ChatStream() did not return after cancel while the plugin kept streaming")
+	}
+}
+
+func TestExecClient_SendBlocked(t *testing.T) {
+	// A request larger than the pipe buffer blocks on a plugin that does
+	// not read it
+	client := execTestClient(t, "deaf")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Chat(ctx, []Message{{Role: "user", Content: strings.Repeat("x", 1<<20)}})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Chat() error = %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Chat() did not return while the plugin ignored its stdin")
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"  ./model   --fast ", []string{"./model", "--fast"}},
+		{`"/opt/my models/run" --name 'a b' x\ y`, []string{"/opt/my models/run", "--name", "a b", "x y"}},
+		{`--say "he said \"hi\"" 'it\s' ""`, []string{"--say", `he said "hi"`, `it\s`, ""}},
+		{`--path "C:\dir"`, []string{"--path", `C:\dir`}},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommand(tt.in)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("SplitCommand(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{`"open`, `'open`, `trailing\`} {
+		if _, err := SplitCommand(in); err == nil {
+			t.Errorf("SplitCommand(%q) should fail", in)
+		}
+	}
+}
+
+func TestExecClient_PluginFailure(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"crash", "model weights not found"},
+		{"garbage", "decode plugin output"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			client := execTestClient(t, tt.mode)
+			_, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Chat() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveExecCommand(t *testing.T) {
+	t.Setenv(ExecCommandEnv, "")
+	if _, err := ResolveExecCommand(Config{}); err == nil || !strings.Contains(err.Error(), ExecCommandEnv) {
+		t.Errorf("ResolveExecCommand() error = %v, want one naming %s", err, ExecCommandEnv)
+	}
+
+	t.Setenv(ExecCommandEnv, os.Args[0]+` -flag "two words"`)
+	command, err := ResolveExecCommand(Config{})
+	if err != nil || len(command) != 3 || command[2] != "two words" {
+		t.Errorf("ResolveExecCommand() = %q, %v; want the env command split like a shell", command, err)
+	}
+	t.Setenv(ExecCommandEnv, os.Args[0]+` -flag "two words`)
+	if _, err := ResolveExecCommand(Config{}); err == nil || !strings.Contains(err.Error(), ExecCommandEnv) {
+		t.Errorf("ResolveExecCommand() error = %v, want an unterminated quote reported", err)
+	}
+
+	if _, err := ResolveExecCommand(Config{Command: []string{"./no-such-plugin"}}); err == nil {
+		t.Error("ResolveExecCommand() accepted a missing binary")
+	}
+}
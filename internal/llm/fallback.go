@@ -0,0 +1,143 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// FallbackClient tries an ordered chain of provider configurations. When an
+// entry fails with a non-retryable error, or its retries are exhausted, the
+// next entry is used. Entries are created lazily, so a fallback whose API key
+// is missing only matters if it is actually reached.
+type FallbackClient struct {
+	chain     []Config
+	newClient func(Config) (Client, error)
+
+	mu      sync.Mutex
+	clients []Client
+	used    int // index of the entry that served the last successful call
+}
+
+// NewFallbackClient creates a client over chain; chain[0] is the primary
+func NewFallbackClient(chain []Config) *FallbackClient {
+	return &FallbackClient{
+		chain:     chain,
+		newClient: NewClient,
+		clients:   make([]Client, len(chain)),
+	}
+}
+
+// Used returns the provider and model that served the last successful call,
+// and whether that was a fallback rather than the primary entry
+func (c *FallbackClient) Used() (provider Provider, model string, fallback bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg := c.chain[c.used]
+	return cfg.Provider, cfg.Model, c.used > 0
+}
+
+// Usage sums the tokens consumed across every entry that was tried
+func (c *FallbackClient) Usage() Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var total Usage
+	for _, client := range c.clients {
+		if client != nil {
+			total = total.Add(client.Usage())
+		}
+	}
+	return total
+}
+
+// Close closes every entry that was created
+func (c *FallbackClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	for _, client := range c.clients {
+		if client != nil {
+			errs = append(errs, client.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *FallbackClient) Chat(ctx context.Context, messages []Message) (result string, err error) {
+	err = c.try(ctx, func(client Client) (bool, error) {
+		result, err = client.Chat(ctx, messages)
+		return false, err
+	})
+	return result, err
+}
+
+// ChatStream falls back only while no chunk has reached onChunk; once output
+// has been shown, switching models would splice two different answers.
+func (c *FallbackClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (result string, err error) {
+	err = c.try(ctx, func(client Client) (bool, error) {
+		sent := false
+		result, err = client.ChatStream(ctx, messages, func(chunk string) {
+			sent = true
+			if onChunk != nil {
+				onChunk(chunk)
+			}
+		})
+		return sent, err
+	})
+	return result, err
+}
+
+// ChatTools falls back like Chat. Tool call ids are provider-specific, so a
+// conversation that already holds tool calls is best kept on one provider;
+// the fallback still answers from the full message list.
+func (c *FallbackClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (result Message, err error) {
+	err = c.try(ctx, func(client Client) (bool, error) {
+		result, err = client.ChatTools(ctx, messages, tools)
+		return false, err
+	})
+	return result, err
+}
+
+// try runs call on each entry in turn until one succeeds. call reports
+// whether output already reached the caller, which stops the fallback.
+func (c *FallbackClient) try(ctx context.Context, call func(Client) (sent bool, err error)) error {
+	var errs []error
+	for i, cfg := range c.chain {
+		client, err := c.client(i)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cfg.Provider, err))
+			continue
+		}
+
+		sent, err := call(client)
+		if err == nil {
+			c.mu.Lock()
+			c.used = i
+			c.mu.Unlock()
+			return nil
+		}
+		if sent || ctx.Err() != nil {
+			return err
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return fmt.Errorf("all %d providers in fallback chain failed: %w", len(c.chain), errors.Join(errs...))
+}
+
+func (c *FallbackClient) client(i int) (Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clients[i] == nil {
+		client, err := c.newClient(c.chain[i])
+		if err != nil {
+			return nil, err
+		}
+		c.clients[i] = client
+	}
+	return c.clients[i], nil
+}
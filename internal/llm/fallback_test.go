@@ -0,0 +1,179 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestFallbackClient(clients map[Provider]Client, providers ...Provider) *FallbackClient {
+	chain := make([]Config, len(providers))
+	for i, p := range providers {
+		chain[i] = Config{Provider: p, Model: string(p) + "-model"}
+	}
+	fc := NewFallbackClient(chain)
+	fc.newClient = func(cfg Config) (Client, error) {
+		if c, ok := clients[cfg.Provider]; ok {
+			return c, nil
+		}
+		return nil, errors.New("missing API key")
+	}
+	return fc
+}
+
+func TestFallbackClient_Implements_Client(t *testing.T) {
+	var _ Client = (*FallbackClient)(nil)
+}
+
+func TestFallbackClient_PrimarySucceeds(t *testing.T) {
+	primary := &scriptedClient{}
+	backup := &scriptedClient{}
+	client := newTestFallbackClient(map[Provider]Client{
+		ProviderGemini:   primary,
+		ProviderDeepSeek: backup,
+	}, ProviderGemini, ProviderDeepSeek)
+
+	if _, err := client.Chat(context.Background(), nil); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if backup.calls != 0 {
+		t.Errorf("fallback called %d times, want 0", backup.calls)
+	}
+	provider, model, fallback := client.Used()
+	if provider != ProviderGemini || model != "gemini-model" || fallback {
+		t.Errorf("Used() = %v, %v, %v; want gemini, gemini-model, false", provider, model, fallback)
+	}
+}
+
+func TestFallbackClient_FallsBackOnFailure(t *testing.T) {
+	primary := &scriptedClient{errs: []error{&ServerError{&APIError{StatusCode: 503}}}}
+	backup := &scriptedClient{}
+	client := newTestFallbackClient(map[Provider]Client{
+		ProviderGemini:   primary,
+		ProviderDeepSeek: backup,
+	}, ProviderGemini, ProviderDeepSeek)
+
+	var chunks []string
+	got, err := client.ChatStream(context.Background(), nil, func(s string) { chunks = append(chunks, s) })
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if got != "ok" || len(chunks) != 1 {
+		t.Errorf("ChatStream() = %q with %d chunks, want ok with 1", got, len(chunks))
+	}
+	provider, _, fallback := client.Used()
+	if provider != ProviderDeepSeek || !fallback {
+		t.Errorf("Used() = %v, fallback %v; want deepseek, true", provider, fallback)
+	}
+}
+
+func TestFallbackClient_SkipsUnconstructibleEntries(t *testing.T) {
+	backup := &scriptedClient{}
+	client := newTestFallbackClient(map[Provider]Client{
+		ProviderDeepSeek: backup,
+	}, ProviderAnthropic, ProviderDeepSeek)
+
+	if _, err := client.Chat(context.Background(), nil); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if provider, _, _ := client.Used(); provider != ProviderDeepSeek {
+		t.Errorf("Used() provider = %v, want deepseek", provider)
+	}
+}
+
+func TestFallbackClient_NoFallbackAfterOutput(t *testing.T) {
+	primary := &scriptedClient{
+		errs:   []error{&ServerError{&APIError{StatusCode: 500}}},
+		chunks: []string{"partial"},
+	}
+	backup := &scriptedClient{}
+	client := newTestFallbackClient(map[Provider]Client{
+		ProviderGemini:   primary,
+		ProviderDeepSeek: backup,
+	}, ProviderGemini, ProviderDeepSeek)
+
+	_, err := client.ChatStream(context.Background(), nil, func(string) {})
+	if err == nil {
+		t.Fatal("ChatStream() should fail once output was streamed")
+	}
+	if backup.calls != 0 {
+		t.Errorf("fallback called %d times after output, want 0", backup.calls)
+	}
+}
+
+func TestFallbackClient_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	primary := &scriptedClient{errs: []error{context.Canceled}}
+	backup := &scriptedClient{}
+	client := newTestFallbackClient(map[Provider]Client{
+		ProviderGemini:   primary,
+		ProviderDeepSeek: backup,
+	}, ProviderGemini, ProviderDeepSeek)
+
+	if _, err := client.Chat(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Chat() error = %v, want context.Canceled", err)
+	}
+	if backup.calls != 0 {
+		t.Errorf("fallback called %d times after cancel, want 0", backup.calls)
+	}
+}
+
+func TestFallbackClient_AllFail(t *testing.T) {
+	primary := &scriptedClient{errs: []error{&AuthError{&APIError{StatusCode: 401}}}}
+	backup := &scriptedClient{errs: []error{&RateLimitError{&APIError{StatusCode: 429}}}}
+	client := newTestFallbackClient(map[Provider]Client{
+		ProviderGemini:   primary,
+		ProviderDeepSeek: backup,
+	}, ProviderGemini, ProviderDeepSeek)
+
+	_, err := client.Chat(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "all 2 providers") {
+		t.Fatalf("Chat() error = %v, want all providers failed", err)
+	}
+	var authErr *AuthError
+	var rateErr *RateLimitError
+	if !errors.As(err, &authErr) || !errors.As(err, &rateErr) {
+		t.Errorf("Chat() error should wrap each provider's typed error, got %v", err)
+	}
+}
+
+func TestFallbackClient_UsageSumsTriedEntries(t *testing.T) {
+	primary := &scriptedClient{errs: []error{&ServerError{&APIError{StatusCode: 503}}}}
+	backup := &scriptedClient{}
+	client := newTestFallbackClient(map[Provider]Client{
+		ProviderGemini:   primary,
+		ProviderDeepSeek: backup,
+	}, ProviderGemini, ProviderDeepSeek)
+
+	if _, err := client.Chat(context.Background(), nil); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	// scriptedClient reports 10 prompt tokens per call
+	if got := client.Usage().PromptTokens; got != 20 {
+		t.Errorf("Usage().PromptTokens = %d, want 20", got)
+	}
+}
+
+func TestFallbackClient_CloseCreatedEntries(t *testing.T) {
+	primary := &scriptedClient{}
+	backup := &scriptedClient{}
+	client := newTestFallbackClient(map[Provider]Client{
+		ProviderGemini:   primary,
+		ProviderDeepSeek: backup,
+	}, ProviderGemini, ProviderDeepSeek)
+
+	if _, err := client.Chat(context.Background(), nil); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !primary.closed {
+		t.Error("Close() should close the primary client")
+	}
+	if backup.closed {
+		t.Error("Close() should not touch fallbacks that were never created")
+	}
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// FinishReason tells why the model stopped generating, normalised across
+// providers. Reasons without an equivalent are passed through as reported.
+type FinishReason string
+
+const (
+	FinishStop          FinishReason = "stop"           // natural end or stop sequence
+	FinishLength        FinishReason = "length"         // hit MaxTokens; the answer is cut off
+	FinishToolCalls     FinishReason = "tool_calls"     // stopped to call tools
+	FinishContentFilter FinishReason = "content_filter" // blocked by the provider's safety system
+)
+
+type finishKey struct{}
+
+// WithFinishReason returns a context that reports the finish reason of each
+// successful response to onFinish. It is called once per Chat, ChatStream
+// or ChatTools call, after the answer is complete; providers that report no
+// reason never call it.
+func WithFinishReason(ctx context.Context, onFinish func(FinishReason)) context.Context {
+	return context.WithValue(ctx, finishKey{}, onFinish)
+}
+
+// finishFunc returns the finish reason callback registered on ctx, or nil
+func finishFunc(ctx context.Context) func(FinishReason) {
+	fn, _ := ctx.Value(finishKey{}).(func(FinishReason))
+	return fn
+}
+
+// reportFinish passes reason to the callback registered on ctx, if any
+func reportFinish(ctx context.Context, reason FinishReason) {
+	if fn := finishFunc(ctx); fn != nil && reason != "" {
+		fn(reason)
+	}
+}
+
+// openAIFinishReason normalises an OpenAI-compatible finish_reason. Ollama's
+// done_reason uses the same values.
+func openAIFinishReason(reason string) FinishReason {
+	if reason == "function_call" {
+		return FinishToolCalls
+	}
+	return FinishReason(reason)
+}
+
+// anthropicFinishReason normalises an Anthropic stop_reason
+func anthropicFinishReason(reason string) FinishReason {
+	switch reason {
+	case "end_turn", "stop_sequence":
+		return FinishStop
+	case "max_tokens":
+		return FinishLength
+	case "tool_use":
+		return FinishToolCalls
+	case "refusal":
+		return FinishContentFilter
+	}
+	return FinishReason(reason)
+}
+
+// geminiFinishReason normalises the finish reason of a Gemini candidate
+func geminiFinishReason(reason genai.FinishReason) FinishReason {
+	switch reason {
+	case genai.FinishReasonUnspecified:
+		return ""
+	case genai.FinishReasonStop:
+		return FinishStop
+	case genai.FinishReasonMaxTokens:
+		return FinishLength
+	case genai.FinishReasonSafety, genai.FinishReasonRecitation:
+		return FinishContentFilter
+	case genai.FinishReasonOther:
+		return "other"
+	}
+	return FinishReason(reason.String())
+}
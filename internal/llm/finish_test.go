@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// finishServer answers every request with body
+func finishServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithFinishReason(t *testing.T) {
+	msgs := []Message{{Role: "user", Content: "hi"}}
+	tests := []struct {
+		name string
+		call func(ctx context.Context, baseURL string) error
+		body string
+		want FinishReason
+	}{
+		{
+			name: "openai chat",
+			body: `{"choices":[{"message":{"content":"Hi"},"finish_reason":"length"}]}`,
+			call: func(ctx context.Context, baseURL string) error {
+				_, err := NewOpenAIClient("", Config{BaseURL: baseURL}).Chat(ctx, msgs)
+				return err
+			},
+			want: FinishLength,
+		},
+		{
+			name: "openai stream",
+			body: "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n",
+			call: func(ctx context.Context, baseURL string) error {
+				_, err := NewOpenAIClient("", Config{BaseURL: baseURL}).ChatStream(ctx, msgs, nil)
+				return err
+			},
+			want: FinishStop,
+		},
+		{
+			name: "anthropic chat",
+			body: `{"content":[{"type":"text","text":"Hi"}],"stop_reason":"max_tokens"}`,
+			call: func(ctx context.Context, baseURL string) error {
+				_, err := NewAnthropicClient("k", Config{BaseURL: baseURL}).Chat(ctx, msgs)
+				return err
+			},
+			want: FinishLength,
+		},
+		{
+			name: "anthropic stream",
+			body: "event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"}}\n\nevent: message_stop\ndata: {\"type\":\"message_stop\"}\n\n",
+			call: func(ctx context.Context, baseURL string) error {
+				_, err := NewAnthropicClient("k", Config{BaseURL: baseURL}).ChatStream(ctx, msgs, nil)
+				return err
+			},
+			want: FinishStop,
+		},
+		{
+			name: "ollama stream",
+			body: "{\"message\":{\"content\":\"Hi\"},\"done\":true,\"done_reason\":\"length\"}\n",
+			call: func(ctx context.Context, baseURL string) error {
+				_, err := NewOllamaClient(Config{BaseURL: baseURL}).ChatStream(ctx, msgs, nil)
+				return err
+			},
+			want: FinishLength,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := finishServer(t, tt.body)
+			var got FinishReason
+			ctx := WithFinishReason(context.Background(), func(r FinishReason) { got = r })
+			if err := tt.call(ctx, server.URL); err != nil {
+				t.Fatalf("call error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("finish reason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithFinishReason_NotReportedOnError(t *testing.T) {
+	server := finishServer(t, "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"},\"finish_reason\":\"length\"}]}\n\ndata: {\"error\":{\"message\":\"boom\"}}\n\n")
+	called := false
+	ctx := WithFinishReason(context.Background(), func(FinishReason) { called = true })
+	if _, err := NewOpenAIClient("", Config{BaseURL: server.URL}).ChatStream(ctx, []Message{{Role: "user", Content: "hi"}}, nil); err == nil {
+		t.Fatal("ChatStream() error = nil, want stream error")
+	}
+	if called {
+		t.Error("finish reason reported for a failed stream")
+	}
+}
+
+func TestGeminiFinishReason(t *testing.T) {
+	tests := map[genai.FinishReason]FinishReason{
+		genai.FinishReasonUnspecified: "",
+		genai.FinishReasonStop:        FinishStop,
+		genai.FinishReasonMaxTokens:   FinishLength,
+		genai.FinishReasonSafety:      FinishContentFilter,
+		genai.FinishReasonOther:       "other",
+	}
+	for in, want := range tests {
+		if got := geminiFinishReason(in); got != want {
+			t.Errorf("geminiFinishReason(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
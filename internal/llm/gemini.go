@@ -1,19 +1,50 @@
 package llm
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
-// GeminiClient implements the Client interface for Google Gemini
+// GeminiClient implements the Client interface for Google Gemini. One SDK
+// client is created on first use and reused until Close.
 type GeminiClient struct {
 	apiKey string
 	cfg    Config
+	usage  usageCounter
+
+	mu     sync.Mutex
+	client *genai.Client
+}
+
+// SafetySetting sets the block threshold for one Gemini harm category, using
+// the API's enum names, e.g. {"HARM_CATEGORY_HARASSMENT", "BLOCK_ONLY_HIGH"}
+type SafetySetting struct {
+	Category  string
+	Threshold string
+}
+
+var geminiHarmCategories = map[string]genai.HarmCategory{
+	"HARM_CATEGORY_HARASSMENT":        genai.HarmCategoryHarassment,
+	"HARM_CATEGORY_HATE_SPEECH":       genai.HarmCategoryHateSpeech,
+	"HARM_CATEGORY_SEXUALLY_EXPLICIT": genai.HarmCategorySexuallyExplicit,
+	"HARM_CATEGORY_DANGEROUS_CONTENT": genai.HarmCategoryDangerousContent,
+}
+
+var geminiBlockThresholds = map[string]genai.HarmBlockThreshold{
+	"BLOCK_LOW_AND_ABOVE":    genai.HarmBlockLowAndAbove,
+	"BLOCK_MEDIUM_AND_ABOVE": genai.HarmBlockMediumAndAbove,
+	"BLOCK_ONLY_HIGH":        genai.HarmBlockOnlyHigh,
+	"BLOCK_NONE":             genai.HarmBlockNone,
 }
 
 // NewGeminiClient creates a new Gemini client
@@ -27,84 +58,592 @@ func NewGeminiClient(apiKey string, cfg Config) *GeminiClient {
 	}
 }
 
-func (c *GeminiClient) Chat(ctx context.Context, messages []Message) (string, error) {
-	client, err := genai.NewClient(ctx, option.WithAPIKey(c.apiKey))
+// Usage returns the tokens consumed by this client so far
+func (c *GeminiClient) Usage() Usage {
+	return c.usage.get()
+}
+
+// Close releases the underlying SDK client
+func (c *GeminiClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client == nil {
+		return nil
+	}
+	err := c.client.Close()
+	c.client = nil
+	return err
+}
+
+// genaiClient returns the shared SDK client, creating it on first use. It
+// outlives the request, so it is not tied to ctx's cancellation.
+func (c *GeminiClient) genaiClient(ctx context.Context) (*genai.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client == nil {
+		client, err := genai.NewClient(context.WithoutCancel(ctx), c.clientOptions()...)
+		if err != nil {
+			return nil, fmt.Errorf("create client: %w", err)
+		}
+		c.client = client
+	}
+	return c.client, nil
+}
+
+// clientOptions routes the SDK through geminiTransport over cfg.HTTPClient,
+// and through cfg.BaseURL when set. The custom HTTP client bypasses the
+// SDK's own key handling, so the key is attached as a header instead.
+func (c *GeminiClient) clientOptions() []option.ClientOption {
+	hc := *c.cfg.httpClient()
+	hc.Transport = &geminiTransport{key: c.apiKey, thinkingBudget: c.cfg.ThinkingBudget, base: hc.Transport}
+	opts := []option.ClientOption{option.WithAPIKey(c.apiKey), option.WithHTTPClient(&hc)}
+	if c.cfg.BaseURL != "" {
+		opts = append(opts, option.WithEndpoint(c.cfg.BaseURL))
+	}
+	return opts
+}
+
+// geminiTransport adds the API key header to every request and, when set,
+// the thinking budget to generation requests. When a thinking budget is set
+// or the request's context asks for reasoning, it also asks for thought
+// summaries and routes them to the reasoning callback, since the pinned SDK
+// would mix them into the answer. Generation responses are re-framed by
+// geminiThoughtFilter, which also tells ChatStream when a stream has ended
+// (see geminiStreamEnd).
+type geminiTransport struct {
+	key            string
+	thinkingBudget int
+	base           http.RoundTripper
+}
+
+func (t *geminiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("x-goog-api-key", t.key)
+	path := req.URL.Path
+	generate := strings.HasSuffix(path, ":generateContent") || strings.HasSuffix(path, ":streamGenerateContent")
+	if generate && (t.thinkingBudget != 0 || reasoningFunc(req.Context()) != nil) {
+		if err := t.addThinkingConfig(req); err != nil {
+			return nil, err
+		}
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || !generate || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	end, _ := req.Context().Value(geminiStreamEndKey{}).(*geminiStreamEnd)
+	src := &eofReader{r: resp.Body}
+	resp.Body = &geminiThoughtFilter{
+		body:        resp.Body,
+		src:         src,
+		dec:         json.NewDecoder(src),
+		stream:      strings.HasSuffix(path, ":streamGenerateContent"),
+		onReasoning: reasoningFunc(req.Context()),
+		end:         end,
+	}
+	resp.ContentLength = -1
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// addThinkingConfig sets generationConfig.thinkingConfig in the JSON request
+// body: includeThoughts, and thinkingBudget when set. The rest of the body is
+// kept as sent.
+func (t *geminiTransport) addThinkingConfig(req *http.Request) error {
+	if req.Body == nil {
+		return nil
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
 	if err != nil {
-		return "", fmt.Errorf("create client: %w", err)
+		return fmt.Errorf("read request: %w", err)
 	}
-	defer client.Close()
 
-	model := client.GenerativeModel(c.cfg.Model)
-	model.SetTemperature(float32(c.cfg.Temperature))
-	if c.cfg.MaxTokens > 0 {
-		model.SetMaxOutputTokens(int32(c.cfg.MaxTokens))
+	body, err := decodeRawObject(data)
+	if err != nil {
+		return fmt.Errorf("decode request: %w", err)
+	}
+	genCfg := rawObject{}
+	if raw := body.get("generationConfig"); raw != nil {
+		if genCfg, err = decodeRawObject(raw); err != nil {
+			return fmt.Errorf("decode request: %w", err)
+		}
+	}
+	thinking := struct {
+		ThinkingBudget  int  `json:"thinkingBudget,omitempty"`
+		IncludeThoughts bool `json:"includeThoughts"`
+	}{t.thinkingBudget, true}
+	if err := genCfg.set("thinkingConfig", thinking); err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+	if err := body.set("generationConfig", genCfg); err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+	if data, err = json.Marshal(body); err != nil {
+		return fmt.Errorf("encode request: %w", err)
 	}
 
-	// Build chat history
-	cs := model.StartChat()
-	for i := 0; i < len(messages)-1; i++ {
-		msg := messages[i]
-		role := "user"
-		if msg.Role == "assistant" || msg.Role == "model" {
-			role = "model"
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.ContentLength = int64(len(data))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(data)), nil }
+	return nil
+}
+
+// geminiStreamEnd is set by geminiThoughtFilter once a streamed response
+// array has been read through its closing ']'. With GOEXPERIMENT=jsonv2 the
+// SDK's stream reader fails on that ']' instead of reporting io.EOF, so
+// ChatStream treats an error after the end as the end of the stream.
+type geminiStreamEnd struct {
+	ended bool
+}
+
+type geminiStreamEndKey struct{}
+
+// geminiThoughtFilter reads a generation response, either one JSON object or
+// a streamed JSON array of them, with the thought parts taken out and
+// passed to onReasoning as they arrive. A stream that ends before its
+// closing ']' is reported as ErrStreamTruncated.
+type geminiThoughtFilter struct {
+	body        io.ReadCloser
+	src         *eofReader
+	dec         *json.Decoder
+	stream      bool
+	onReasoning func(string)
+	end         *geminiStreamEnd // may be nil
+
+	buf     bytes.Buffer // filtered output not yet read
+	started bool         // the array's '[' was read
+	objects int          // response objects filtered so far
+	done    bool
+	err     error
+}
+
+func (f *geminiThoughtFilter) Read(p []byte) (int, error) {
+	for f.buf.Len() == 0 && !f.done && f.err == nil {
+		f.err = f.next()
+	}
+	if f.buf.Len() > 0 {
+		return f.buf.Read(p)
+	}
+	if f.err != nil {
+		return 0, f.err
+	}
+	return 0, io.EOF
+}
+
+// next filters the next response object into buf, with the array's
+// brackets and commas when streaming
+func (f *geminiThoughtFilter) next() error {
+	if f.stream {
+		switch {
+		case f.objects == 0 && !f.started:
+			if _, err := f.dec.Token(); err != nil {
+				return f.truncated(err)
+			}
+			f.started = true
+			f.buf.WriteByte('[')
+			return nil
+		case !f.dec.More():
+			t, err := f.dec.Token()
+			if err != nil {
+				return f.truncated(err)
+			}
+			if t != json.Delim(']') {
+				return fmt.Errorf("decode response: unexpected %v", t)
+			}
+			f.done = true
+			if f.end != nil {
+				f.end.ended = true
+			}
+			f.buf.WriteByte(']')
+			return nil
+		case f.objects > 0:
+			f.buf.WriteByte(',')
 		}
-		cs.History = append(cs.History, &genai.Content{
-			Role:  role,
-			Parts: []genai.Part{genai.Text(msg.Content)},
-		})
 	}
+	var raw json.RawMessage
+	if err := f.dec.Decode(&raw); err != nil {
+		return f.truncated(err)
+	}
+	data, err := splitGeminiThoughts(raw, f.onReasoning)
+	if err != nil {
+		return err
+	}
+	f.buf.Write(data)
+	f.objects++
+	f.done = !f.stream
+	return nil
+}
 
-	// Send the last message
-	lastMsg := messages[len(messages)-1]
-	resp, err := cs.SendMessage(ctx, genai.Text(lastMsg.Content))
+// truncated reports a decode error on a stream whose body has ended as
+// ErrStreamTruncated. json.Decoder reports an early end as io.EOF or as a
+// syntax error depending on GOEXPERIMENT=jsonv2, so the body is checked
+// instead of the error.
+func (f *geminiThoughtFilter) truncated(err error) error {
+	if f.stream && f.src.eof {
+		return ErrStreamTruncated
+	}
+	return err
+}
+
+func (f *geminiThoughtFilter) Close() error {
+	return f.body.Close()
+}
+
+// eofReader records whether r has reported io.EOF
+type eofReader struct {
+	r   io.Reader
+	eof bool
+}
+
+func (r *eofReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+// splitGeminiThoughts removes the parts marked "thought" from a response
+// object, passing their text to onReasoning. Everything else is kept as
+// received: key order, numbers and unknown fields.
+func splitGeminiThoughts(data []byte, onReasoning func(string)) ([]byte, error) {
+	resp, err := decodeRawObject(data)
 	if err != nil {
-		return "", fmt.Errorf("send message: %w", err)
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	var candidates []json.RawMessage
+	if raw := resp.get("candidates"); raw != nil {
+		if err := json.Unmarshal(raw, &candidates); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
 	}
+	removed := false
+	for i, raw := range candidates {
+		candidate, err := decodeRawObject(raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		content, err := decodeRawObject(candidate.get("content"))
+		if err != nil || content.get("parts") == nil {
+			continue
+		}
+		var parts []json.RawMessage
+		if err := json.Unmarshal(content.get("parts"), &parts); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		kept := make([]json.RawMessage, 0, len(parts))
+		for _, part := range parts {
+			var p struct {
+				Text    string `json:"text"`
+				Thought bool   `json:"thought"`
+			}
+			if json.Unmarshal(part, &p) == nil && p.Thought {
+				if p.Text != "" && onReasoning != nil {
+					onReasoning(p.Text)
+				}
+				continue
+			}
+			kept = append(kept, part)
+		}
+		if len(kept) == len(parts) {
+			continue
+		}
+		removed = true
+		if err := content.set("parts", kept); err != nil {
+			return nil, err
+		}
+		if err := candidate.set("content", content); err != nil {
+			return nil, err
+		}
+		if candidates[i], err = json.Marshal(candidate); err != nil {
+			return nil, err
+		}
+	}
+	if !removed {
+		return data, nil
+	}
+	if err := resp.set("candidates", candidates); err != nil {
+		return nil, err
+	}
+	return json.Marshal(resp)
+}
 
-	return extractGeminiText(resp), nil
+// rawObject is a JSON object as its fields in order, with their values
+// undecoded, so that one field can be changed without touching the others
+type rawObject []rawField
+
+type rawField struct {
+	key   string
+	value json.RawMessage
 }
 
-func (c *GeminiClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
-	client, err := genai.NewClient(ctx, option.WithAPIKey(c.apiKey))
+// decodeRawObject splits a JSON object into its fields
+func decodeRawObject(data []byte) (rawObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil {
+		return nil, err
+	} else if t != json.Delim('{') {
+		return nil, fmt.Errorf("want a JSON object, got %v", t)
+	}
+	obj := rawObject{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := t.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		obj = append(obj, rawField{key, value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// get returns the value of key, or nil when the object has no such field
+func (o rawObject) get(key string) json.RawMessage {
+	for _, f := range o {
+		if f.key == key {
+			return f.value
+		}
+	}
+	return nil
+}
+
+// set encodes v as the value of key, in place or as a new last field
+func (o *rawObject) set(key string, v any) error {
+	value, err := json.Marshal(v)
 	if err != nil {
-		return "", fmt.Errorf("create client: %w", err)
+		return err
+	}
+	for i := range *o {
+		if (*o)[i].key == key {
+			(*o)[i].value = value
+			return nil
+		}
+	}
+	*o = append(*o, rawField{key, value})
+	return nil
+}
+
+func (o rawObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(f.value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// startChat configures a model for messages and tools and returns a chat
+// session holding the history, plus the parts of the final turn to send
+func (c *GeminiClient) startChat(ctx context.Context, messages []Message, tools []Tool) (*genai.ChatSession, []genai.Part, error) {
+	client, err := c.genaiClient(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
-	defer client.Close()
 
 	model := client.GenerativeModel(c.cfg.Model)
+	turns, err := c.configureModel(model, messages)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tools) > 0 {
+		decls, err := geminiFunctions(tools)
+		if err != nil {
+			return nil, nil, err
+		}
+		model.Tools = []*genai.Tool{{FunctionDeclarations: decls}}
+	}
+
+	contents, err := geminiContents(turns)
+	if err != nil {
+		return nil, nil, err
+	}
+	last := contents[len(contents)-1]
+	if last.Role != "user" {
+		return nil, nil, fmt.Errorf("last message must come from the user or a tool, got %s", turns[len(turns)-1].Role)
+	}
+
+	cs := model.StartChat()
+	cs.History = contents[:len(contents)-1]
+	return cs, last.Parts, nil
+}
+
+// geminiContents converts conversation turns to Gemini contents. Assistant
+// tool calls become function calls; consecutive tool results become one
+// user turn of function responses, as Gemini expects.
+func geminiContents(turns []Message) ([]*genai.Content, error) {
+	var contents []*genai.Content
+	for i, msg := range turns {
+		switch msg.Role {
+		case "tool":
+			name := toolCallName(turns[:i], msg.ToolCallID)
+			if name == "" {
+				return nil, fmt.Errorf("tool result %q answers no tool call", msg.ToolCallID)
+			}
+			part := genai.FunctionResponse{Name: name, Response: map[string]any{"content": msg.Content}}
+			if i > 0 && turns[i-1].Role == "tool" {
+				prev := contents[len(contents)-1]
+				prev.Parts = append(prev.Parts, part)
+				continue
+			}
+			contents = append(contents, &genai.Content{Role: "user", Parts: []genai.Part{part}})
+
+		case "assistant", "model":
+			content := &genai.Content{Role: "model"}
+			if msg.Content != "" || len(msg.ToolCalls) == 0 {
+				content.Parts = append(content.Parts, genai.Text(msg.Content))
+			}
+			for _, call := range msg.ToolCalls {
+				var args map[string]any
+				if call.Arguments != "" {
+					if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+						return nil, fmt.Errorf("tool call %s arguments: %w", call.Name, err)
+					}
+				}
+				content.Parts = append(content.Parts, genai.FunctionCall{Name: call.Name, Args: args})
+			}
+			contents = append(contents, content)
+
+		default:
+			contents = append(contents, &genai.Content{Role: "user", Parts: []genai.Part{genai.Text(msg.Content)}})
+		}
+	}
+	return contents, nil
+}
+
+// geminiFunctions converts tools to Gemini function declarations
+func geminiFunctions(tools []Tool) ([]*genai.FunctionDeclaration, error) {
+	decls := make([]*genai.FunctionDeclaration, len(tools))
+	for i, t := range tools {
+		params, err := t.Parameters.toGenai()
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %w", t.Name, err)
+		}
+		decls[i] = &genai.FunctionDeclaration{Name: t.Name, Description: t.Description, Parameters: params}
+	}
+	return decls, nil
+}
+
+// configureModel applies the generation settings to model and returns the
+// conversation turns. System messages become the model's system instruction
+// rather than conversation turns.
+func (c *GeminiClient) configureModel(model *genai.GenerativeModel, messages []Message) ([]Message, error) {
 	model.SetTemperature(float32(c.cfg.Temperature))
 	if c.cfg.MaxTokens > 0 {
 		model.SetMaxOutputTokens(int32(c.cfg.MaxTokens))
 	}
+	if c.cfg.TopP > 0 {
+		model.SetTopP(float32(c.cfg.TopP))
+	}
+	if c.cfg.TopK > 0 {
+		model.SetTopK(int32(c.cfg.TopK))
+	}
+	for _, s := range c.cfg.SafetySettings {
+		category, ok := geminiHarmCategories[strings.ToUpper(s.Category)]
+		if !ok {
+			return nil, fmt.Errorf("unknown Gemini harm category %q", s.Category)
+		}
+		threshold, ok := geminiBlockThresholds[strings.ToUpper(s.Threshold)]
+		if !ok {
+			return nil, fmt.Errorf("unknown Gemini block threshold %q", s.Threshold)
+		}
+		model.SafetySettings = append(model.SafetySettings, &genai.SafetySetting{Category: category, Threshold: threshold})
+	}
+	if f := c.cfg.ResponseFormat; f != nil && f.Type != ResponseText {
+		model.ResponseMIMEType = "application/json"
+		if f.JSONSchema() {
+			schema, err := f.Schema.toGenai()
+			if err != nil {
+				return nil, fmt.Errorf("response schema: %w", err)
+			}
+			model.ResponseSchema = schema
+		}
+	}
 
-	// Build chat history
-	cs := model.StartChat()
-	for i := 0; i < len(messages)-1; i++ {
-		msg := messages[i]
-		role := "user"
-		if msg.Role == "assistant" || msg.Role == "model" {
-			role = "model"
+	var system []string
+	var turns []Message
+	for _, msg := range messages {
+		if msg.Role == "system" {
+			system = append(system, msg.Content)
+		} else {
+			turns = append(turns, msg)
 		}
-		cs.History = append(cs.History, &genai.Content{
-			Role:  role,
-			Parts: []genai.Part{genai.Text(msg.Content)},
-		})
 	}
+	if len(system) > 0 {
+		model.SystemInstruction = genai.NewUserContent(genai.Text(strings.Join(system, "\n\n")))
+	}
+	if len(turns) == 0 {
+		return nil, fmt.Errorf("no user message to send")
+	}
+	return turns, nil
+}
 
-	// Send the last message with streaming
-	lastMsg := messages[len(messages)-1]
-	iter := cs.SendMessageStream(ctx, genai.Text(lastMsg.Content))
+func (c *GeminiClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	cs, last, err := c.startChat(ctx, messages, nil)
+	if err != nil {
+		return "", err
+	}
 
+	resp, err := cs.SendMessage(ctx, last...)
+	if err != nil {
+		return "", fmt.Errorf("send message: %w", wrapGeminiError(err, c.cfg.Model))
+	}
+	c.usage.add(geminiUsage(resp.UsageMetadata))
+	reportFinish(ctx, geminiResponseFinish(resp))
+
+	return extractGeminiText(resp), nil
+}
+
+func (c *GeminiClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	cs, last, err := c.startChat(ctx, messages, nil)
+	if err != nil {
+		return "", err
+	}
+
+	end := &geminiStreamEnd{}
+	iter := cs.SendMessageStream(context.WithValue(ctx, geminiStreamEndKey{}, end), last...)
+
+	// Each streamed response carries the running totals; keep the last one
 	var fullContent strings.Builder
+	var usage *genai.UsageMetadata
+	var finishReason FinishReason
+	defer func() { c.usage.add(geminiUsage(usage)) }()
 	for {
 		resp, err := iter.Next()
-		if err == iterator.Done {
+		if err == iterator.Done || err != nil && end.ended {
+			reportFinish(ctx, finishReason)
 			break
 		}
 		if err != nil {
-			return fullContent.String(), fmt.Errorf("stream error: %w", err)
+			return fullContent.String(), fmt.Errorf("stream error: %w", wrapGeminiError(err, c.cfg.Model))
 		}
 
+		if resp.UsageMetadata != nil {
+			usage = resp.UsageMetadata
+		}
+		if reason := geminiResponseFinish(resp); reason != "" {
+			finishReason = reason
+		}
 		text := extractGeminiText(resp)
 		fullContent.WriteString(text)
 		if onChunk != nil {
@@ -115,6 +654,65 @@ func (c *GeminiClient) ChatStream(ctx context.Context, messages []Message, onChu
 	return fullContent.String(), nil
 }
 
+// ChatTools declares tools as Gemini functions. Gemini function calls carry
+// no id, so each is given one ("call_N") for the tool results to refer to.
+func (c *GeminiClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	cs, last, err := c.startChat(ctx, messages, tools)
+	if err != nil {
+		return Message{}, err
+	}
+
+	resp, err := cs.SendMessage(ctx, last...)
+	if err != nil {
+		return Message{}, fmt.Errorf("send message: %w", wrapGeminiError(err, c.cfg.Model))
+	}
+	c.usage.add(geminiUsage(resp.UsageMetadata))
+	reportFinish(ctx, geminiResponseFinish(resp))
+
+	return geminiMessage(resp)
+}
+
+// geminiMessage converts a response to an assistant message with its text
+// and function calls
+func geminiMessage(resp *genai.GenerateContentResponse) (Message, error) {
+	msg := Message{Role: "assistant", Content: extractGeminiText(resp)}
+	for _, cand := range resp.Candidates {
+		if cand.Content == nil {
+			continue
+		}
+		for _, part := range cand.Content.Parts {
+			call, ok := part.(genai.FunctionCall)
+			if !ok {
+				continue
+			}
+			args := []byte("{}")
+			if len(call.Args) > 0 {
+				var err error
+				if args, err = json.Marshal(call.Args); err != nil {
+					return Message{}, fmt.Errorf("tool call %s arguments: %w", call.Name, err)
+				}
+			}
+			msg.ToolCalls = append(msg.ToolCalls, ToolCall{
+				ID:        fmt.Sprintf("call_%d", len(msg.ToolCalls)),
+				Name:      call.Name,
+				Arguments: string(args),
+			})
+		}
+	}
+	return msg, nil
+}
+
+// geminiResponseFinish returns the finish reason of the first candidate
+func geminiResponseFinish(resp *genai.GenerateContentResponse) FinishReason {
+	if len(resp.Candidates) == 0 {
+		return ""
+	}
+	return geminiFinishReason(resp.Candidates[0].FinishReason)
+}
+
+// extractGeminiText concatenates the text parts of all candidates. Thought
+// summaries never get here: geminiTransport routes them to the reasoning
+// callback.
 func extractGeminiText(resp *genai.GenerateContentResponse) string {
 	var result strings.Builder
 	for _, cand := range resp.Candidates {
@@ -128,3 +726,21 @@ func extractGeminiText(resp *genai.GenerateContentResponse) string {
 	}
 	return result.String()
 }
+
+// geminiUsage converts UsageMetadata. Thinking tokens are not reported
+// separately but are billed as output, so completion tokens are derived
+// from the total when it exceeds prompt plus candidates.
+func geminiUsage(meta *genai.UsageMetadata) Usage {
+	if meta == nil {
+		return Usage{}
+	}
+	completion := int(meta.CandidatesTokenCount)
+	if rest := int(meta.TotalTokenCount - meta.PromptTokenCount); rest > completion {
+		completion = rest
+	}
+	return Usage{
+		PromptTokens:     int(meta.PromptTokenCount),
+		CompletionTokens: completion,
+		CachedTokens:     int(meta.CachedContentTokenCount),
+	}
+}
@@ -1,7 +1,14 @@
 package llm
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/generative-ai-go/genai"
@@ -163,6 +170,34 @@ func TestExtractGeminiText(t *testing.T) {
 	}
 }
 
+func TestGeminiUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		meta *genai.UsageMetadata
+		want Usage
+	}{
+		{name: "nil metadata", meta: nil, want: Usage{}},
+		{
+			name: "plain",
+			meta: &genai.UsageMetadata{PromptTokenCount: 100, CandidatesTokenCount: 40, TotalTokenCount: 140, CachedContentTokenCount: 60},
+			want: Usage{PromptTokens: 100, CompletionTokens: 40, CachedTokens: 60},
+		},
+		{
+			name: "thinking tokens billed as output",
+			meta: &genai.UsageMetadata{PromptTokenCount: 100, CandidatesTokenCount: 40, TotalTokenCount: 400},
+			want: Usage{PromptTokens: 100, CompletionTokens: 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := geminiUsage(tt.meta); got != tt.want {
+				t.Errorf("geminiUsage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExtractGeminiText_UnicodeContent(t *testing.T) {
 	resp := &genai.GenerateContentResponse{
 		Candidates: []*genai.Candidate{
@@ -197,3 +232,333 @@ func TestGeminiClient_Integration(t *testing.T) {
 	// This test would make a real API call - only run if explicitly enabled
 	t.Skip("Skipping integration test to avoid API costs")
 }
+
+func TestGeminiClient_ConfigureModel(t *testing.T) {
+	client := NewGeminiClient("key", Config{
+		Temperature: 0.2,
+		MaxTokens:   1024,
+		TopP:        0.9,
+		TopK:        40,
+		SafetySettings: []SafetySetting{
+			{Category: "HARM_CATEGORY_HARASSMENT", Threshold: "block_only_high"},
+		},
+	})
+	model := &genai.GenerativeModel{}
+	turns, err := client.configureModel(model, []Message{
+		{Role: "system", Content: "be brief"},
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+		{Role: "system", Content: "be kind"},
+		{Role: "user", Content: "bye"},
+	})
+	if err != nil {
+		t.Fatalf("configureModel() error = %v", err)
+	}
+
+	if len(turns) != 3 || turns[0].Content != "hi" || turns[2].Content != "bye" {
+		t.Errorf("turns = %+v, want the three non-system messages", turns)
+	}
+	if model.SystemInstruction == nil || len(model.SystemInstruction.Parts) != 1 ||
+		model.SystemInstruction.Parts[0] != genai.Text("be brief\n\nbe kind") {
+		t.Errorf("SystemInstruction = %+v, want joined system messages", model.SystemInstruction)
+	}
+	if *model.TopP != 0.9 || *model.TopK != 40 || *model.MaxOutputTokens != 1024 {
+		t.Errorf("GenerationConfig = %+v, want top-p 0.9, top-k 40, max 1024", model.GenerationConfig)
+	}
+	if len(model.SafetySettings) != 1 || model.SafetySettings[0].Category != genai.HarmCategoryHarassment ||
+		model.SafetySettings[0].Threshold != genai.HarmBlockOnlyHigh {
+		t.Errorf("SafetySettings = %+v", model.SafetySettings)
+	}
+}
+
+func TestGeminiClient_ConfigureModel_ResponseFormat(t *testing.T) {
+	client := NewGeminiClient("key", Config{ResponseFormat: &ResponseFormat{
+		Type: ResponseJSONSchema,
+		Schema: &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{"decision": {Type: "string", Enum: []string{"approve", "reject"}}},
+			Required:   []string{"decision"},
+		},
+	}})
+	model := &genai.GenerativeModel{}
+	if _, err := client.configureModel(model, []Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("configureModel() error = %v", err)
+	}
+	if model.ResponseMIMEType != "application/json" {
+		t.Errorf("ResponseMIMEType = %q, want application/json", model.ResponseMIMEType)
+	}
+	schema := model.ResponseSchema
+	if schema == nil || schema.Type != genai.TypeObject || len(schema.Required) != 1 {
+		t.Fatalf("ResponseSchema = %+v", schema)
+	}
+	if d := schema.Properties["decision"]; d == nil || d.Type != genai.TypeString || d.Format != "enum" || len(d.Enum) != 2 {
+		t.Errorf("decision schema = %+v", d)
+	}
+
+	// json_object only sets the MIME type
+	client = NewGeminiClient("key", Config{ResponseFormat: &ResponseFormat{Type: ResponseJSONObject}})
+	model = &genai.GenerativeModel{}
+	client.configureModel(model, []Message{{Role: "user", Content: "hi"}})
+	if model.ResponseMIMEType != "application/json" || model.ResponseSchema != nil {
+		t.Errorf("json_object: MIME %q, schema %+v", model.ResponseMIMEType, model.ResponseSchema)
+	}
+}
+
+func TestGeminiContents_ToolCalls(t *testing.T) {
+	calls := []ToolCall{
+		{ID: "call_0", Name: "calculator", Arguments: `{"expression":"1+1"}`},
+		{ID: "call_1", Name: "search", Arguments: `{}`},
+	}
+	contents, err := geminiContents([]Message{
+		{Role: "user", Content: "question"},
+		{Role: "assistant", Content: "let me check", ToolCalls: calls},
+		ToolResultMessage(calls[0], "2"),
+		ToolResultMessage(calls[1], "nothing found"),
+	})
+	if err != nil {
+		t.Fatalf("geminiContents() error = %v", err)
+	}
+	if len(contents) != 3 {
+		t.Fatalf("contents = %d, want user, model, and one grouped tool turn", len(contents))
+	}
+
+	model := contents[1]
+	if model.Role != "model" || len(model.Parts) != 3 || model.Parts[0] != genai.Text("let me check") {
+		t.Errorf("model turn = %+v", model)
+	}
+	if fc, ok := model.Parts[1].(genai.FunctionCall); !ok || fc.Name != "calculator" || fc.Args["expression"] != "1+1" {
+		t.Errorf("function call = %#v", model.Parts[1])
+	}
+
+	results := contents[2]
+	if results.Role != "user" || len(results.Parts) != 2 {
+		t.Fatalf("tool turn = %+v", results)
+	}
+	if fr, ok := results.Parts[1].(genai.FunctionResponse); !ok || fr.Name != "search" || fr.Response["content"] != "nothing found" {
+		t.Errorf("function response = %#v", results.Parts[1])
+	}
+
+	if _, err := geminiContents([]Message{{Role: "tool", ToolCallID: "call_9", Content: "x"}}); err == nil {
+		t.Error("a tool result without a matching call should fail")
+	}
+}
+
+func TestGeminiMessage_FunctionCalls(t *testing.T) {
+	resp := &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{Content: &genai.Content{Parts: []genai.Part{
+		genai.Text("checking"),
+		genai.FunctionCall{Name: "calculator", Args: map[string]any{"expression": "6*7"}},
+		genai.FunctionCall{Name: "now"},
+	}}}}}
+	msg, err := geminiMessage(resp)
+	if err != nil {
+		t.Fatalf("geminiMessage() error = %v", err)
+	}
+	want := []ToolCall{
+		{ID: "call_0", Name: "calculator", Arguments: `{"expression":"6*7"}`},
+		{ID: "call_1", Name: "now", Arguments: `{}`},
+	}
+	if msg.Content != "checking" || len(msg.ToolCalls) != 2 || msg.ToolCalls[0] != want[0] || msg.ToolCalls[1] != want[1] {
+		t.Errorf("geminiMessage() = %+v, want %+v", msg, want)
+	}
+}
+
+func TestGeminiClient_ConfigureModel_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		safety   []SafetySetting
+		messages []Message
+	}{
+		{"unknown category", []SafetySetting{{Category: "HARM_CATEGORY_BOREDOM", Threshold: "BLOCK_NONE"}}, []Message{{Role: "user", Content: "hi"}}},
+		{"unknown threshold", []SafetySetting{{Category: "HARM_CATEGORY_HATE_SPEECH", Threshold: "BLOCK_SOME"}}, []Message{{Role: "user", Content: "hi"}}},
+		{"only system messages", nil, []Message{{Role: "system", Content: "rules"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewGeminiClient("key", Config{SafetySettings: tt.safety})
+			if _, err := client.configureModel(&genai.GenerativeModel{}, tt.messages); err == nil {
+				t.Error("configureModel() should fail")
+			}
+		})
+	}
+}
+
+func TestGeminiClient_ReusesClientUntilClose(t *testing.T) {
+	client := NewGeminiClient("key", Config{})
+	ctx := context.Background()
+
+	first, err := client.genaiClient(ctx)
+	if err != nil {
+		t.Fatalf("genaiClient() error = %v", err)
+	}
+	second, _ := client.genaiClient(ctx)
+	if first != second {
+		t.Error("genaiClient() should reuse the SDK client")
+	}
+
+	if err := client.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if client.client != nil {
+		t.Error("Close() should release the SDK client")
+	}
+	if err := client.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestGeminiTransport_ThinkingBudget(t *testing.T) {
+	var got *http.Request
+	var body map[string]any
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = req
+		body = nil
+		if req.Body != nil {
+			json.NewDecoder(req.Body).Decode(&body)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	transport := &geminiTransport{key: "secret", thinkingBudget: 2048, base: base}
+
+	send := func(path, payload string) {
+		t.Helper()
+		req, _ := http.NewRequest("POST", "https://example.test"+path, strings.NewReader(payload))
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip(%s) error = %v", path, err)
+		}
+	}
+
+	send("/v1beta/models/gemini:streamGenerateContent", `{"contents":[],"generationConfig":{"temperature":0.1}}`)
+	if got.Header.Get("x-goog-api-key") != "secret" {
+		t.Error("API key header missing")
+	}
+	genCfg, _ := body["generationConfig"].(map[string]any)
+	thinking, _ := genCfg["thinkingConfig"].(map[string]any)
+	if thinking["thinkingBudget"] != float64(2048) || thinking["includeThoughts"] != true || genCfg["temperature"] != 0.1 {
+		t.Errorf("generationConfig = %v, want thinkingBudget 2048 and thought summaries alongside temperature", genCfg)
+	}
+	if got.ContentLength <= 0 {
+		t.Errorf("ContentLength = %d, want the rewritten body length", got.ContentLength)
+	}
+
+	send("/v1beta/models/gemini:countTokens", `{"contents":[]}`)
+	if _, ok := body["generationConfig"]; ok {
+		t.Error("non-generation requests should be left unchanged")
+	}
+
+	// Without a budget, thought summaries are asked for only with reasoning
+	transport.thinkingBudget = 0
+	send("/v1beta/models/gemini:generateContent", `{"contents":[]}`)
+	if _, ok := body["generationConfig"]; ok {
+		t.Errorf("body = %v, want no thinkingConfig without a budget or reasoning", body)
+	}
+	ctx := WithReasoning(context.Background(), func(string) {})
+	req, _ := http.NewRequestWithContext(ctx, "POST", "https://example.test/v1beta/models/gemini:generateContent", strings.NewReader(`{"contents":[]}`))
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	genCfg, _ = body["generationConfig"].(map[string]any)
+	thinking, _ = genCfg["thinkingConfig"].(map[string]any)
+	if _, ok := thinking["thinkingBudget"]; ok || thinking["includeThoughts"] != true {
+		t.Errorf("thinkingConfig = %v, want thought summaries and the model's budget", thinking)
+	}
+}
+
+func TestGeminiTransport_Thoughts(t *testing.T) {
+	const (
+		stream = `[{"candidates":[{"content":{"role":"model","parts":[{"text":"weighing costs","thought":true}]}}]}` + "\n,\r\n" +
+			`{"candidates":[{"content":{"role":"model","parts":[{"text":"; then risks","thought":true},{"text":"Approve"}]}}]},` +
+			`{"candidates":[{"content":{"role":"model","parts":[{"text":" it."}]}}],"usageMetadata":{"totalTokenCount":42}}]`
+		single = `{"candidates":[{"content":{"role":"model","parts":[{"text":"hmm","thought":true},{"text":"Approve it."}]},"avgLogprobs":-0.12345678901234567890123}],"modelVersion":"gemini"}`
+	)
+	tests := []struct {
+		path, body, want, reasoning string
+	}{
+		{":streamGenerateContent", stream,
+			`[{"candidates":[{"content":{"role":"model","parts":[]}}]},` +
+				`{"candidates":[{"content":{"role":"model","parts":[{"text":"Approve"}]}}]},` +
+				`{"candidates":[{"content":{"role":"model","parts":[{"text":" it."}]}}],"usageMetadata":{"totalTokenCount":42}}]`,
+			"weighing costs; then risks"},
+		// Key order, numbers and unknown fields are kept
+		{":generateContent", single, `{"candidates":[{"content":{"role":"model","parts":[{"text":"Approve it."}]},"avgLogprobs":-0.12345678901234567890123}],"modelVersion":"gemini"}`, "hmm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(tt.body))}, nil
+			})
+			transport := &geminiTransport{key: "secret", thinkingBudget: -1, base: base}
+			var reasoning strings.Builder
+			ctx := WithReasoning(context.Background(), func(s string) { reasoning.WriteString(s) })
+			req, _ := http.NewRequestWithContext(ctx, "POST", "https://example.test/v1beta/models/gemini"+tt.path, strings.NewReader(`{}`))
+
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			got, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("body = %s\nwant   %s", got, tt.want)
+			}
+			if reasoning.String() != tt.reasoning {
+				t.Errorf("reasoning = %q, want %q", reasoning.String(), tt.reasoning)
+			}
+		})
+	}
+
+	// A broken stream is reported, not ended early
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`[{"candidates":[]},{"cand`))}, nil
+	})
+	req, _ := http.NewRequest("POST", "https://example.test/v1beta/models/gemini:streamGenerateContent", strings.NewReader(`{}`))
+	resp, err := (&geminiTransport{thinkingBudget: 1024, base: base}).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if _, err := io.ReadAll(resp.Body); err == nil {
+		t.Error("reading a broken stream should fail")
+	}
+}
+
+func TestGeminiClient_ChatStream_EndOfStream(t *testing.T) {
+	const chunk = `{"candidates":[{"content":{"role":"model","parts":[{"text":"Approve it."}]},"finishReason":"STOP"}]}`
+	tests := []struct {
+		name, body string
+		wantErr    error
+	}{
+		{"complete", "[" + chunk + "]", nil},
+		{"truncated", "[" + chunk, ErrStreamTruncated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := NewGeminiClient("test-key", Config{Provider: ProviderGemini, Model: "gemini-test", BaseURL: server.URL})
+			defer client.Close()
+			got, err := client.ChatStream(context.Background(), []Message{{Role: "user", Content: "hi"}}, func(string) {})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ChatStream() error = %v", err)
+				}
+				if got != "Approve it." {
+					t.Errorf("ChatStream() = %q, want %q", got, "Approve it.")
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatStream() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
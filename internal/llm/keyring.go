@@ -0,0 +1,182 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How long a key the provider rejected is skipped. Rate-limited keys come
+// back after the provider's Retry-After, or keyThrottleBench without one.
+const (
+	keyAuthBench     = time.Hour
+	keyThrottleBench = time.Minute
+)
+
+// keyRings holds one ring per provider and key set for the whole process,
+// so every client using the same keys shares the rotation
+var keyRings = struct {
+	mu sync.Mutex
+	m  map[string]*keyRing
+}{m: make(map[string]*keyRing)}
+
+// ringFor returns the shared ring for provider and keys
+func ringFor(provider Provider, keys []string) *keyRing {
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	id := string(provider) + "/" + hex.EncodeToString(sum[:8])
+
+	keyRings.mu.Lock()
+	defer keyRings.mu.Unlock()
+	r, ok := keyRings.m[id]
+	if !ok {
+		r = &keyRing{now: time.Now, benched: make([]time.Time, len(keys))}
+		keyRings.m[id] = r
+	}
+	return r
+}
+
+// keyRing hands out the keys of a provider in turn, skipping keys the
+// provider rejected recently
+type keyRing struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	next    int
+	benched []time.Time // per key: skipped until then
+}
+
+// pick returns the index of the next usable key. When every key is
+// benched, the one that comes back first is used anyway.
+func (r *keyRing) pick() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	best := -1
+	for n := range r.benched {
+		i := (r.next + n) % len(r.benched)
+		if !r.benched[i].After(now) {
+			best = i
+			break
+		}
+		if best < 0 || r.benched[i].Before(r.benched[best]) {
+			best = i
+		}
+	}
+	r.next = (best + 1) % len(r.benched)
+	return best
+}
+
+// reject benches key i after the provider refused it with err
+func (r *keyRing) reject(i int, err error) {
+	bench := keyThrottleBench
+	var rateErr *RateLimitError
+	switch {
+	case errors.As(err, &rateErr) && rateErr.Code != "insufficient_quota":
+		if rateErr.RetryAfter > 0 {
+			bench = rateErr.RetryAfter
+		}
+	default:
+		// A bad key or an exhausted quota will not recover soon
+		bench = keyAuthBench
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.benched[i] = r.now().Add(bench)
+}
+
+// keyRejected reports whether err means the provider refused the key
+// itself, so that another key may succeed
+func keyRejected(err error) bool {
+	var authErr *AuthError
+	var rateErr *RateLimitError
+	return errors.As(err, &authErr) || errors.As(err, &rateErr)
+}
+
+// keyRotationClient spreads calls over one client per API key
+type keyRotationClient struct {
+	ring    *keyRing
+	clients []Client // in key order
+}
+
+// withKeyRotation returns a client that starts each call on the next of
+// keys in turn and moves on to the following key when the provider rejects
+// one (401/403 or 429), skipping it for a while. clients[i] uses keys[i].
+// A single client is returned as is.
+func withKeyRotation(provider Provider, keys []string, clients []Client) Client {
+	if len(clients) == 1 {
+		return clients[0]
+	}
+	return &keyRotationClient{ring: ringFor(provider, keys), clients: clients}
+}
+
+// Usage sums the tokens consumed with every key
+func (c *keyRotationClient) Usage() Usage {
+	var total Usage
+	for _, client := range c.clients {
+		total = total.Add(client.Usage())
+	}
+	return total
+}
+
+func (c *keyRotationClient) Close() error {
+	var errs []error
+	for _, client := range c.clients {
+		errs = append(errs, client.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (c *keyRotationClient) Chat(ctx context.Context, messages []Message) (result string, err error) {
+	err = c.rotate(ctx, func(client Client) (bool, error) {
+		result, err = client.Chat(ctx, messages)
+		return false, err
+	})
+	return result, err
+}
+
+// ChatStream only moves to another key while no chunk has reached onChunk
+func (c *keyRotationClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (result string, err error) {
+	err = c.rotate(ctx, func(client Client) (bool, error) {
+		sent := false
+		result, err = client.ChatStream(ctx, messages, func(chunk string) {
+			sent = true
+			if onChunk != nil {
+				onChunk(chunk)
+			}
+		})
+		return sent, err
+	})
+	return result, err
+}
+
+func (c *keyRotationClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (result Message, err error) {
+	err = c.rotate(ctx, func(client Client) (bool, error) {
+		result, err = client.ChatTools(ctx, messages, tools)
+		return false, err
+	})
+	return result, err
+}
+
+// rotate runs call with one key after another until a key is accepted or
+// each has been tried once. call reports whether output already reached
+// the caller, which ends the rotation.
+func (c *keyRotationClient) rotate(ctx context.Context, call func(Client) (sent bool, err error)) error {
+	var err error
+	for range c.clients {
+		i := c.ring.pick()
+		var sent bool
+		if sent, err = call(c.clients[i]); err == nil {
+			return nil
+		}
+		if sent || ctx.Err() != nil || !keyRejected(err) {
+			return err
+		}
+		c.ring.reject(i, err)
+	}
+	return err
+}
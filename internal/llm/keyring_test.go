@@ -0,0 +1,152 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// keyedClient answers with its API key; keys starting with "bad" are
+// rejected as invalid and keys starting with "busy" are throttled
+type keyedClient struct {
+	key   string
+	calls *[]string
+}
+
+func (c *keyedClient) answer() (string, error) {
+	*c.calls = append(*c.calls, c.key)
+	apiErr := &APIError{Provider: "keyed", Message: "rejected " + c.key}
+	switch {
+	case strings.HasPrefix(c.key, "bad"):
+		apiErr.StatusCode = 401
+	case strings.HasPrefix(c.key, "busy"):
+		apiErr.StatusCode = 429
+		apiErr.RetryAfter = 30 * time.Second
+	default:
+		return c.key, nil
+	}
+	return "", classifyAPIError(apiErr)
+}
+
+func (c *keyedClient) Chat(context.Context, []Message) (string, error) { return c.answer() }
+func (c *keyedClient) ChatStream(_ context.Context, _ []Message, onChunk func(string)) (string, error) {
+	result, err := c.answer()
+	if err == nil && onChunk != nil {
+		onChunk(result)
+	}
+	return result, err
+}
+func (c *keyedClient) ChatTools(context.Context, []Message, []Tool) (Message, error) {
+	result, err := c.answer()
+	return Message{Role: "assistant", Content: result}, err
+}
+func (c *keyedClient) Usage() Usage { return Usage{PromptTokens: 1} }
+func (c *keyedClient) Close() error { return nil }
+
+// keyedTestClient creates a client of the keyed test provider using keys.
+// The process-wide key rings and rate limiters it used are dropped after
+// the test, so that reruns start afresh.
+func keyedTestClient(t *testing.T, keys string) (Client, *[]string) {
+	t.Helper()
+	t.Cleanup(func() {
+		keyRings.mu.Lock()
+		clear(keyRings.m)
+		keyRings.mu.Unlock()
+		rateLimiters.mu.Lock()
+		for id := range rateLimiters.m {
+			if strings.HasPrefix(id, "keyed/") {
+				delete(rateLimiters.m, id)
+			}
+		}
+		rateLimiters.mu.Unlock()
+	})
+	calls := new([]string)
+	registerTestProvider(t, ProviderSpec{
+		Name:    "keyed",
+		EnvVars: []string{"KEYED_API_KEY"},
+		New: func(apiKey string, cfg Config) (Client, error) {
+			return &keyedClient{key: apiKey, calls: calls}, nil
+		},
+	})
+	t.Setenv(KeyListEnv("KEYED_API_KEY"), keys)
+	client, err := NewClient(Config{Provider: "keyed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, calls
+}
+
+func TestKeyRotation_RoundRobin(t *testing.T) {
+	client, _ := keyedTestClient(t, "rr-a,rr-b,rr-c")
+	var got []string
+	for range 4 {
+		answer, err := client.Chat(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, answer)
+	}
+	if strings.Join(got, " ") != "rr-a rr-b rr-c rr-a" {
+		t.Errorf("answers = %q, want the keys in turn", got)
+	}
+	if u := client.Usage(); u.PromptTokens != 3 {
+		t.Errorf("Usage() = %+v, want the usage of every key's client", u)
+	}
+}
+
+func TestKeyRotation_Rejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      string
+		wantCalls string // keys tried by two calls
+	}{
+		{"invalid key", "bad-1,good-1", "bad-1 good-1 good-1"},
+		{"throttled key", "busy-2,good-2", "busy-2 good-2 good-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, calls := keyedTestClient(t, tt.keys)
+			for range 2 {
+				if _, err := client.ChatStream(context.Background(), nil, nil); err != nil {
+					t.Fatalf("ChatStream() error = %v, want the other key to answer", err)
+				}
+			}
+			if got := strings.Join(*calls, " "); got != tt.wantCalls {
+				t.Errorf("keys tried = %q, want %q: the rejected key is skipped afterwards", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestKeyRotation_AllRejected(t *testing.T) {
+	client, calls := keyedTestClient(t, "bad-3,busy-3")
+	_, err := client.Chat(context.Background(), nil)
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Errorf("Chat() error = %v, want the last key's error", err)
+	}
+	if len(*calls) != 2 {
+		t.Errorf("keys tried = %q, want each key once", *calls)
+	}
+}
+
+func TestKeyRing_Bench(t *testing.T) {
+	now := time.Now()
+	r := &keyRing{now: func() time.Time { return now }, benched: make([]time.Time, 2)}
+	r.reject(0, &RateLimitError{&APIError{StatusCode: 429, RetryAfter: 10 * time.Second}})
+	if a, b := r.pick(), r.pick(); a != 1 || b != 1 {
+		t.Errorf("pick() = %d, %d; want the benched key skipped", a, b)
+	}
+
+	r.reject(1, &AuthError{&APIError{StatusCode: 401}})
+	if got := r.pick(); got != 0 {
+		t.Errorf("pick() = %d with every key benched, want the one back first", got)
+	}
+
+	now = now.Add(11 * time.Second)
+	if a, b := r.pick(), r.pick(); a != 0 || b != 0 {
+		t.Errorf("pick() = %d, %d; want the throttled key back and the invalid one still benched", a, b)
+	}
+}
@@ -0,0 +1,338 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// MockOptions configure the offline mock provider. NewClient reads them
+// from the DIALECTA_MOCK_* environment variables (see MockOptionsFromEnv).
+type MockOptions struct {
+	// FixturesDir holds per-role answers that replace the built-in ones:
+	// pro.md, con.md and judge.md, or pro.json, con.json and judge.json
+	// when a ResponseFormat is set. Missing files fall back to the built-ins.
+	FixturesDir string
+
+	ChunkSize int           // runes per streamed chunk; 0 uses DefaultMockChunkSize
+	Latency   time.Duration // delay before each chunk
+
+	// Error injects a failure: "auth", "rate_limit", "server",
+	// "context_length", "content_filter", "truncated" (the stream breaks),
+	// or "length" (the answer stops at the token limit)
+	Error      string
+	ErrorAfter int // chunks streamed before the error; 0 fails before any output
+	ErrorCalls int // calls that fail before the client recovers; 0 fails every call
+}
+
+// DefaultMockChunkSize is the streamed chunk size of the mock provider
+const DefaultMockChunkSize = 8
+
+// mockErrors are the accepted values of MockOptions.Error
+var mockErrors = []string{"auth", "rate_limit", "server", "context_length", "content_filter", "truncated", "length"}
+
+// MockOptionsFromEnv reads DIALECTA_MOCK_FIXTURES, DIALECTA_MOCK_CHUNK_SIZE,
+// DIALECTA_MOCK_LATENCY (a duration such as 20ms), DIALECTA_MOCK_ERROR,
+// DIALECTA_MOCK_ERROR_AFTER and DIALECTA_MOCK_ERROR_CALLS
+func MockOptionsFromEnv() (MockOptions, error) {
+	opts := MockOptions{
+		FixturesDir: os.Getenv("DIALECTA_MOCK_FIXTURES"),
+		Error:       os.Getenv("DIALECTA_MOCK_ERROR"),
+	}
+	for env, dst := range map[string]*int{
+		"DIALECTA_MOCK_CHUNK_SIZE":  &opts.ChunkSize,
+		"DIALECTA_MOCK_ERROR_AFTER": &opts.ErrorAfter,
+		"DIALECTA_MOCK_ERROR_CALLS": &opts.ErrorCalls,
+	} {
+		if v := os.Getenv(env); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return MockOptions{}, fmt.Errorf("%s: invalid count %q", env, v)
+			}
+			*dst = n
+		}
+	}
+	if v := os.Getenv("DIALECTA_MOCK_LATENCY"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return MockOptions{}, fmt.Errorf("DIALECTA_MOCK_LATENCY: %w", err)
+		}
+		opts.Latency = d
+	}
+	if opts.Error != "" && !slices.Contains(mockErrors, opts.Error) {
+		return MockOptions{}, fmt.Errorf("DIALECTA_MOCK_ERROR: unknown error %q (supported: %s)", opts.Error, strings.Join(mockErrors, ", "))
+	}
+	return opts, nil
+}
+
+// MockClient is an offline provider with deterministic answers in the
+// debate's Markdown format, or JSON matching Config.ResponseFormat. It
+// needs no network or API key, which makes it suitable for demos and CI.
+type MockClient struct {
+	cfg   Config
+	opts  MockOptions
+	usage usageCounter
+
+	mu    sync.Mutex
+	calls int
+}
+
+// NewMockClient creates a mock client
+func NewMockClient(cfg Config, opts MockOptions) *MockClient {
+	if cfg.Model == "" {
+		cfg.Model = "mock"
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = ProviderMock
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultMockChunkSize
+	}
+	return &MockClient{cfg: cfg, opts: opts}
+}
+
+// Usage returns the estimated tokens of all calls so far
+func (c *MockClient) Usage() Usage {
+	return c.usage.get()
+}
+
+// Close is a no-op
+func (c *MockClient) Close() error {
+	return nil
+}
+
+func (c *MockClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	return c.ChatStream(ctx, messages, nil)
+}
+
+// ChatStream sends the answer in chunks of opts.ChunkSize runes, waiting
+// opts.Latency before each
+func (c *MockClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	answer, err := c.answer(messages)
+	if err != nil {
+		return "", err
+	}
+	failing := c.failing()
+
+	var sent strings.Builder
+	for i, chunk := range splitRunes(answer, c.opts.ChunkSize) {
+		if failing && i == c.opts.ErrorAfter {
+			break
+		}
+		if c.opts.Latency > 0 {
+			if err := sleepContext(ctx, c.opts.Latency); err != nil {
+				return sent.String(), err
+			}
+		} else if err := ctx.Err(); err != nil {
+			return sent.String(), err
+		}
+		sent.WriteString(chunk)
+		if onChunk != nil {
+			onChunk(chunk)
+		}
+	}
+
+	// Usage is estimated the way the rate limiter sizes prompts
+	c.usage.add(Usage{PromptTokens: estimateTokens(messages), CompletionTokens: estimateTokens([]Message{{Content: sent.String()}})})
+	if failing {
+		if c.opts.Error == "length" {
+			reportFinish(ctx, FinishLength)
+			return sent.String(), nil
+		}
+		return sent.String(), c.injectedError()
+	}
+	reportFinish(ctx, FinishStop)
+	return sent.String(), nil
+}
+
+// ChatTools answers directly; the mock never calls tools
+func (c *MockClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	content, err := c.Chat(ctx, messages)
+	return Message{Role: "assistant", Content: content}, err
+}
+
+// failing counts the call and reports whether it gets the injected error
+func (c *MockClient) failing() bool {
+	if c.opts.Error == "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return c.opts.ErrorCalls == 0 || c.calls <= c.opts.ErrorCalls
+}
+
+// injectedError builds the error selected by opts.Error, typed like a real
+// provider's
+func (c *MockClient) injectedError() error {
+	if c.opts.Error == "truncated" {
+		return ErrStreamTruncated
+	}
+	apiErr := &APIError{Provider: c.cfg.Provider, Model: c.cfg.Model, Message: "injected " + c.opts.Error + " error"}
+	switch c.opts.Error {
+	case "auth":
+		apiErr.StatusCode = http.StatusUnauthorized
+	case "rate_limit":
+		apiErr.StatusCode = http.StatusTooManyRequests
+		apiErr.RetryAfter = time.Second
+	case "server":
+		apiErr.StatusCode = http.StatusServiceUnavailable
+	case "context_length":
+		apiErr.StatusCode = http.StatusBadRequest
+		apiErr.Code = "context_length_exceeded"
+	case "content_filter":
+		apiErr.StatusCode = http.StatusBadRequest
+		apiErr.Code = "content_filter"
+	}
+	return classifyAPIError(apiErr)
+}
+
+// answer picks the fixture or built-in answer for the role the system
+// prompt describes
+func (c *MockClient) answer(messages []Message) (string, error) {
+	role := mockRole(messages)
+	ext := ".md"
+	if c.cfg.ResponseFormat != nil && c.cfg.ResponseFormat.Type != ResponseText {
+		ext = ".json"
+	}
+
+	if c.opts.FixturesDir != "" {
+		data, err := os.ReadFile(filepath.Join(c.opts.FixturesDir, role+ext))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("mock fixture: %w", err)
+		}
+	}
+
+	if ext == ".md" {
+		return mockAnswers[role], nil
+	}
+	var value any = map[string]any{}
+	if c.cfg.ResponseFormat.Schema != nil {
+		value = mockValue(c.cfg.ResponseFormat.Schema, role)
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// mockRole tells the debate roles apart by their system prompts: "pro",
+// "con" or "judge"
+func mockRole(messages []Message) string {
+	for _, m := range messages {
+		if m.Role != "system" {
+			continue
+		}
+		switch {
+		case strings.Contains(m.Content, "裁决"):
+			return "judge"
+		case strings.Contains(m.Content, "完全反对"):
+			return "con"
+		}
+	}
+	return "pro"
+}
+
+// mockValue returns a deterministic value that satisfies schema
+func mockValue(schema *Schema, name string) any {
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	switch schema.Type {
+	case "object":
+		obj := make(map[string]any, len(schema.Properties))
+		for key, prop := range schema.Properties {
+			obj[key] = mockValue(prop, key)
+		}
+		return obj
+	case "array":
+		if schema.Items == nil {
+			return []any{}
+		}
+		return []any{mockValue(schema.Items, name+" 1"), mockValue(schema.Items, name+" 2")}
+	case "integer":
+		return 75
+	case "number":
+		return 0.75
+	case "boolean":
+		return true
+	default:
+		return "mock " + name
+	}
+}
+
+// splitRunes cuts s into chunks of size runes
+func splitRunes(s string, size int) []string {
+	var chunks []string
+	for s != "" {
+		n, i := 0, 0
+		for i < len(s) && n < size {
+			_, w := utf8.DecodeRuneInString(s[i:])
+			i += w
+			n++
+		}
+		chunks = append(chunks, s[:i])
+		s = s[i:]
+	}
+	return chunks
+}
+
+// mockAnswers are the built-in Markdown answers in the format the debate
+// prompts ask for
+var mockAnswers = map[string]string{
+	"pro": `## 💡 One-Liner
+这不仅是一个可行的方案，更是用小步试错换取先发优势的理性选择。
+
+## 📝 Full Argument
+**【正方核心立场】**：方案目标清晰、投入可控，具备先行验证的条件。
+**【关键支撑论据】**：
+   1. 需求真实：材料描述的问题普遍存在，且尚无成熟解法。
+   2. 路径可行：可以用小规模试点在一个季度内验证核心假设。
+   3. 风险可控：分阶段投入，每个里程碑都保留止损选项。
+**【预期收益描绘】**：试点成功后可快速复制，形成数据与口碑的双重壁垒。
+**【潜在质疑的预先反驳】**：对成本的担忧可通过阶段性预算和里程碑评审化解。`,
+
+	"con": `## 💡 One-Liner
+该方案看似抓住了机会，实则把未经验证的假设当成了前提，风险被系统性低估。
+
+## 📝 Full Argument
+**【反方核心驳斥】**：材料缺少关键数据支撑，核心假设无法证伪。
+**【关键风险/漏洞】**：
+   1. 市场规模依赖乐观估计，没有给出获客成本。
+   2. 执行团队的能力与方案要求之间存在明显缺口。
+   3. 竞争对手的反应没有被纳入考量。
+**【最坏结果推演】**：投入耗尽而验证未完成，团队被迫在信息不足时做出更大赌注。
+**【竞争性替代视角】**：先用低成本调研验证需求，再决定是否投入资源。`,
+
+	"judge": `## 💡 One-Liner
+【评分: 68/100】 【结论：需修改】 正方的试点思路成立，但反方指出的数据缺口必须在投入前补齐。
+
+## 📝 Full Verdict
+## ⚖️ 综合裁决报告
+
+### 1. 争议焦点分析
+双方的分歧集中在需求是否真实，以及风险能否在试点阶段被控制。
+
+### 2. 论点效力评估
+* **正方高光时刻**：分阶段投入、设置止损点的执行框架。
+* **反方致命一击**：缺少获客成本与市场规模的数据支撑。
+
+### 3. 最终裁决
+* **综合评分**：68 / 100
+* **裁决结论**：需修改
+
+### 4. 优化建议 (Next Steps)
+* 补充目标用户调研与获客成本测算。
+* 明确试点的成功标准与止损条件。`,
+}
@@ -0,0 +1,182 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func mockMessages(system string) []Message {
+	return []Message{{Role: "system", Content: system}, {Role: "user", Content: "material"}}
+}
+
+func TestMockClient_ChatStream(t *testing.T) {
+	tests := []struct {
+		system string
+		want   string
+	}{
+		{"### Role\n你是一位【战略支持者】", mockAnswers["pro"]},
+		{"站在\"完全反对\"的立场上", mockAnswers["con"]},
+		{"你是一位客观公正的【首席裁决官】", mockAnswers["judge"]},
+	}
+	for _, tt := range tests {
+		client := NewMockClient(Config{}, MockOptions{ChunkSize: 5})
+		var chunks []string
+		var finish FinishReason
+		ctx := WithFinishReason(context.Background(), func(r FinishReason) { finish = r })
+		got, err := client.ChatStream(ctx, mockMessages(tt.system), func(s string) { chunks = append(chunks, s) })
+		if err != nil {
+			t.Fatalf("ChatStream() error = %v", err)
+		}
+		if got != tt.want || strings.Join(chunks, "") != tt.want {
+			t.Errorf("ChatStream() = %q, want the built-in answer", got)
+		}
+		for _, c := range chunks[:len(chunks)-1] {
+			if utf8.RuneCountInString(c) != 5 {
+				t.Errorf("chunk %q has %d runes, want 5", c, utf8.RuneCountInString(c))
+			}
+		}
+		if finish != FinishStop {
+			t.Errorf("finish reason = %q, want %q", finish, FinishStop)
+		}
+		if u := client.Usage(); u.PromptTokens == 0 || u.CompletionTokens == 0 {
+			t.Errorf("Usage() = %+v, want estimated tokens", u)
+		}
+	}
+}
+
+func TestMockClient_Fixtures(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pro.md"), []byte("## 💡 One-Liner\nfixture"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pro.json"), []byte(`{"one_liner":"fixture"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	client := NewMockClient(Config{}, MockOptions{FixturesDir: dir})
+	if got, err := client.Chat(ctx, mockMessages("pro")); err != nil || got != "## 💡 One-Liner\nfixture" {
+		t.Errorf("Chat() = %q, %v; want the pro.md fixture", got, err)
+	}
+	if got, _ := client.Chat(ctx, mockMessages("完全反对")); got != mockAnswers["con"] {
+		t.Errorf("Chat() = %q, want the built-in con answer without con.md", got)
+	}
+
+	jsonClient := NewMockClient(Config{ResponseFormat: &ResponseFormat{Type: ResponseJSONObject}}, MockOptions{FixturesDir: dir})
+	if got, _ := jsonClient.Chat(ctx, mockMessages("pro")); got != `{"one_liner":"fixture"}` {
+		t.Errorf("Chat() = %q, want the pro.json fixture", got)
+	}
+}
+
+func TestMockClient_JSONSchema(t *testing.T) {
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"one_liner": {Type: "string"},
+			"arguments": {Type: "array", Items: &Schema{Type: "string"}},
+			"score":     {Type: "integer"},
+			"decision":  {Type: "string", Enum: []string{"approve", "reject"}},
+		},
+		Required: []string{"one_liner", "arguments", "score", "decision"},
+	}
+	client := NewMockClient(Config{ResponseFormat: &ResponseFormat{Type: ResponseJSONSchema, Schema: schema}}, MockOptions{})
+	got, err := client.Chat(context.Background(), mockMessages("裁决"))
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if err := schema.Validate([]byte(got)); err != nil {
+		t.Errorf("answer %s does not match the schema: %v", got, err)
+	}
+}
+
+func TestMockClient_InjectedErrors(t *testing.T) {
+	tests := []struct {
+		kind  string
+		check func(error) bool
+	}{
+		{"auth", func(err error) bool { var e *AuthError; return errors.As(err, &e) }},
+		{"rate_limit", func(err error) bool { var e *RateLimitError; return errors.As(err, &e) && e.RetryAfter > 0 }},
+		{"server", func(err error) bool { var e *ServerError; return errors.As(err, &e) }},
+		{"context_length", func(err error) bool { var e *ContextLengthError; return errors.As(err, &e) }},
+		{"content_filter", func(err error) bool { var e *ContentFilterError; return errors.As(err, &e) }},
+		{"truncated", func(err error) bool { return errors.Is(err, ErrStreamTruncated) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			client := NewMockClient(Config{}, MockOptions{Error: tt.kind, ErrorAfter: 2, ChunkSize: 4})
+			var chunks []string
+			got, err := client.ChatStream(context.Background(), mockMessages("pro"), func(s string) { chunks = append(chunks, s) })
+			if !tt.check(err) {
+				t.Errorf("ChatStream() error = %v", err)
+			}
+			if len(chunks) != 2 || got != strings.Join(chunks, "") {
+				t.Errorf("streamed %q before failing, want 2 chunks", chunks)
+			}
+		})
+	}
+}
+
+func TestMockClient_ErrorCalls(t *testing.T) {
+	client := NewMockClient(Config{}, MockOptions{Error: "server", ErrorCalls: 2})
+	for i := 1; i <= 3; i++ {
+		_, err := client.Chat(context.Background(), mockMessages("pro"))
+		if failed := err != nil; failed != (i <= 2) {
+			t.Errorf("call %d: error = %v", i, err)
+		}
+	}
+}
+
+func TestMockClient_Length(t *testing.T) {
+	client := NewMockClient(Config{}, MockOptions{Error: "length", ErrorAfter: 1, ChunkSize: 3})
+	var finish FinishReason
+	ctx := WithFinishReason(context.Background(), func(r FinishReason) { finish = r })
+	got, err := client.Chat(ctx, mockMessages("pro"))
+	if err != nil || utf8.RuneCountInString(got) != 3 || finish != FinishLength {
+		t.Errorf("Chat() = %q, %v, finish %q; want one chunk cut off at the length limit", got, err, finish)
+	}
+}
+
+func TestMockClient_LatencyCancel(t *testing.T) {
+	client := NewMockClient(Config{}, MockOptions{Latency: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.Chat(ctx, mockMessages("pro")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Chat() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestMockOptionsFromEnv(t *testing.T) {
+	t.Setenv("DIALECTA_MOCK_FIXTURES", "/fixtures")
+	t.Setenv("DIALECTA_MOCK_CHUNK_SIZE", "3")
+	t.Setenv("DIALECTA_MOCK_LATENCY", "20ms")
+	t.Setenv("DIALECTA_MOCK_ERROR", "rate_limit")
+	t.Setenv("DIALECTA_MOCK_ERROR_AFTER", "4")
+	t.Setenv("DIALECTA_MOCK_ERROR_CALLS", "1")
+	got, err := MockOptionsFromEnv()
+	if err != nil {
+		t.Fatalf("MockOptionsFromEnv() error = %v", err)
+	}
+	want := MockOptions{FixturesDir: "/fixtures", ChunkSize: 3, Latency: 20 * time.Millisecond, Error: "rate_limit", ErrorAfter: 4, ErrorCalls: 1}
+	if got != want {
+		t.Errorf("MockOptionsFromEnv() = %+v, want %+v", got, want)
+	}
+
+	for env, value := range map[string]string{
+		"DIALECTA_MOCK_CHUNK_SIZE": "-1",
+		"DIALECTA_MOCK_LATENCY":    "soon",
+		"DIALECTA_MOCK_ERROR":      "bogus",
+	} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, value)
+			if _, err := MockOptionsFromEnv(); err == nil || !strings.Contains(err.Error(), env) {
+				t.Errorf("MockOptionsFromEnv() error = %v, want one naming %s", err, env)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const ollamaBaseURL = "http://localhost:11434"
+
+// OllamaClient implements the Client interface for a local Ollama server.
+// Requests never leave the machine and no API key is needed.
+type OllamaClient struct {
+	cfg   Config
+	http  *http.Client
+	usage usageCounter
+}
+
+// NewOllamaClient creates a new Ollama client
+func NewOllamaClient(cfg Config) *OllamaClient {
+	cfg.Provider = ProviderOllama
+	if cfg.Model == "" {
+		cfg.Model = "llama3.1"
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = OllamaBaseURL()
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &OllamaClient{
+		cfg:  cfg,
+		http: cfg.httpClient(),
+	}
+}
+
+// OllamaBaseURL returns the Ollama server address, honouring OLLAMA_HOST
+func OllamaBaseURL() string {
+	host := os.Getenv("OLLAMA_HOST")
+	if host == "" {
+		return ollamaBaseURL
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Options  *ollamaOptions  `json:"options,omitempty"` // nil when every option is the model default
+	Format   any             `json:"format,omitempty"`  // "json" or a JSON Schema
+}
+
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature,omitempty"`
+	TopP        float64 `json:"top_p,omitempty"`
+	TopK        int     `json:"top_k,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+}
+
+// ollamaResponse is both the non-streaming body and a single NDJSON stream line
+type ollamaResponse struct {
+	Message struct {
+		Content  string `json:"content"`
+		Thinking string `json:"thinking"` // thinking models with "think" enabled
+	} `json:"message"`
+	Done       bool   `json:"done"`
+	DoneReason string `json:"done_reason"` // "stop" or "length", on the final object
+	Error      string `json:"error"`
+
+	// Token counts, present on the final object only
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+func (r *ollamaResponse) usage() Usage {
+	return Usage{PromptTokens: r.PromptEvalCount, CompletionTokens: r.EvalCount}
+}
+
+// Usage returns the tokens consumed by this client so far
+func (c *OllamaClient) Usage() Usage {
+	return c.usage.get()
+}
+
+// Close is a no-op; the HTTP client may be shared with other clients
+func (c *OllamaClient) Close() error {
+	return nil
+}
+
+// ChatTools returns ErrToolsUnsupported; Ollama's tool calling is not wired
+// up yet
+func (c *OllamaClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	return Message{}, toolsUnsupported(c.cfg.Provider)
+}
+
+func (c *OllamaClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	return c.chat(ctx, messages, false, nil)
+}
+
+func (c *OllamaClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	return c.chat(ctx, messages, true, onChunk)
+}
+
+func (c *OllamaClient) chat(ctx context.Context, messages []Message, stream bool, onChunk func(string)) (string, error) {
+	oMessages := make([]openAIMessage, len(messages))
+	for i, m := range messages {
+		oMessages[i] = openAIMessage{Role: m.Role, Content: m.Content}
+	}
+
+	req := ollamaRequest{
+		Model:    c.cfg.Model,
+		Messages: oMessages,
+		Stream:   stream,
+	}
+	options := ollamaOptions{
+		Temperature: c.cfg.Temperature,
+		TopP:        c.cfg.TopP,
+		TopK:        c.cfg.TopK,
+		NumPredict:  c.cfg.MaxTokens,
+	}
+	if options != (ollamaOptions{}) {
+		req.Options = &options
+	}
+	if f := c.cfg.ResponseFormat; f.JSONSchema() {
+		req.Format = f.Schema
+	} else if f != nil && f.Type != ResponseText {
+		req.Format = "json"
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.cfg.BaseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range c.cfg.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("send request (is Ollama running at %s?): %w", c.cfg.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError(resp, c.cfg.Provider, c.cfg.Model)
+	}
+
+	if stream {
+		content, doneReason, err := c.handleStream(resp.Body, onChunk, reasoningFunc(ctx))
+		if err == nil {
+			reportFinish(ctx, openAIFinishReason(doneReason))
+		}
+		return content, err
+	}
+
+	var oResp ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&oResp); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	if oResp.Error != "" {
+		return "", classifyAPIError(&APIError{Provider: ProviderOllama, Model: c.cfg.Model, Message: oResp.Error})
+	}
+	c.usage.add(oResp.usage())
+	if onReasoning := reasoningFunc(ctx); onReasoning != nil && oResp.Message.Thinking != "" {
+		onReasoning(oResp.Message.Thinking)
+	}
+	reportFinish(ctx, openAIFinishReason(oResp.DoneReason))
+
+	return oResp.Message.Content, nil
+}
+
+// handleStream parses Ollama's NDJSON stream: one JSON object per line,
+// terminated by an object with "done": true that carries the done reason. A
+// stream that ends before it is reported as ErrStreamTruncated.
+func (c *OllamaClient) handleStream(body io.Reader, onChunk, onReasoning func(string)) (string, string, error) {
+	var fullContent strings.Builder
+	decoder := json.NewDecoder(body)
+
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return fullContent.String(), "", ErrStreamTruncated
+			}
+			return fullContent.String(), "", fmt.Errorf("read stream: %w", err)
+		}
+
+		var chunk ollamaResponse
+		if err := json.Unmarshal(raw, &chunk); err != nil {
+			return fullContent.String(), "", fmt.Errorf("decode stream: %w", err)
+		}
+		if chunk.Error != "" {
+			return fullContent.String(), "", classifyAPIError(&APIError{Provider: ProviderOllama, Model: c.cfg.Model, Message: chunk.Error, Body: string(raw)})
+		}
+
+		if chunk.Message.Thinking != "" && onReasoning != nil {
+			onReasoning(chunk.Message.Thinking)
+		}
+		if chunk.Message.Content != "" {
+			fullContent.WriteString(chunk.Message.Content)
+			if onChunk != nil {
+				onChunk(chunk.Message.Content)
+			}
+		}
+		if chunk.Done {
+			c.usage.add(chunk.usage())
+			return fullContent.String(), chunk.DoneReason, nil
+		}
+	}
+}
+
+// OllamaModel describes a locally installed Ollama model
+type OllamaModel struct {
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	ModifiedAt string `json:"modified_at"`
+}
+
+// ollamaListTimeout bounds ListOllamaModels, so a server that accepts the
+// connection but never answers does not hang the CLI
+var ollamaListTimeout = 10 * time.Second
+
+// ListOllamaModels returns the models installed on the Ollama server at
+// baseURL (empty means OllamaBaseURL()), giving up after ollamaListTimeout
+func ListOllamaModels(ctx context.Context, baseURL string) ([]OllamaModel, error) {
+	if baseURL == "" {
+		baseURL = OllamaBaseURL()
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: ollamaListTimeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request (is Ollama running at %s?): %w", baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp, ProviderOllama, "")
+	}
+
+	var tags struct {
+		Models []OllamaModel `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return tags.Models, nil
+}
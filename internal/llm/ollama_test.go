@@ -0,0 +1,268 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewOllamaClient(t *testing.T) {
+	origHost := os.Getenv("OLLAMA_HOST")
+	defer os.Setenv("OLLAMA_HOST", origHost)
+
+	tests := []struct {
+		name        string
+		host        string
+		cfg         Config
+		wantModel   string
+		wantBaseURL string
+	}{
+		{
+			name:        "defaults",
+			cfg:         Config{},
+			wantModel:   "llama3.1",
+			wantBaseURL: "http://localhost:11434",
+		},
+		{
+			name:        "OLLAMA_HOST without scheme",
+			host:        "127.0.0.1:9999",
+			cfg:         Config{Model: "qwen2.5"},
+			wantModel:   "qwen2.5",
+			wantBaseURL: "http://127.0.0.1:9999",
+		},
+		{
+			name:        "explicit base URL wins",
+			host:        "127.0.0.1:9999",
+			cfg:         Config{BaseURL: "http://gpu-box:11434/"},
+			wantModel:   "llama3.1",
+			wantBaseURL: "http://gpu-box:11434",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("OLLAMA_HOST", tt.host)
+			client := NewOllamaClient(tt.cfg)
+			if client.cfg.Model != tt.wantModel {
+				t.Errorf("client.cfg.Model = %v, want %v", client.cfg.Model, tt.wantModel)
+			}
+			if client.cfg.BaseURL != tt.wantBaseURL {
+				t.Errorf("client.cfg.BaseURL = %v, want %v", client.cfg.BaseURL, tt.wantBaseURL)
+			}
+		})
+	}
+}
+
+func TestOllamaClient_DefaultOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if options, ok := req["options"]; ok {
+			t.Errorf("options = %s, want none so the model defaults apply", options)
+		}
+		_, _ = io.WriteString(w, `{"message":{"role":"assistant","content":"ok"},"done":true}`)
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(Config{BaseURL: server.URL})
+	if _, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+}
+
+func TestOllamaClient_Implements_Client(t *testing.T) {
+	var _ Client = (*OllamaClient)(nil)
+}
+
+func TestOllamaClient_ChatStream_MockServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("Path = %s, want /api/chat", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization = %q, want empty", auth)
+		}
+
+		var req ollamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if !req.Stream || req.Options == nil || req.Options.NumPredict != 256 {
+			t.Errorf("request = %+v, want stream with num_predict 256", req)
+		}
+
+		_, _ = io.WriteString(w, `{"message":{"role":"assistant","content":"Hello"},"done":false}
+{"message":{"role":"assistant","content":" offline"},"done":false}
+{"message":{"role":"assistant","content":""},"done":true,"prompt_eval_count":26,"eval_count":2}
+`)
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(Config{BaseURL: server.URL, MaxTokens: 256})
+	var chunks []string
+	got, err := client.ChatStream(context.Background(), []Message{{Role: "user", Content: "hi"}}, func(s string) {
+		chunks = append(chunks, s)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if got != "Hello offline" {
+		t.Errorf("ChatStream() = %q, want %q", got, "Hello offline")
+	}
+	if len(chunks) != 2 {
+		t.Errorf("Callback was called %d times, want 2", len(chunks))
+	}
+	if got := client.Usage(); got != (Usage{PromptTokens: 26, CompletionTokens: 2}) {
+		t.Errorf("Usage() = %+v, want 26 prompt / 2 completion", got)
+	}
+}
+
+func TestOllamaClient_Chat_MockServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"message":{"role":"assistant","content":"full answer"},"done":true}`)
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(Config{BaseURL: server.URL})
+	got, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if got != "full answer" {
+		t.Errorf("Chat() = %q, want %q", got, "full answer")
+	}
+}
+
+func TestOllamaClient_Chat_ResponseFormat(t *testing.T) {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{"score": {Type: "integer"}}}
+	tests := []struct {
+		name   string
+		format *ResponseFormat
+		want   string
+	}{
+		{"text", nil, ""},
+		{"json object", &ResponseFormat{Type: ResponseJSONObject}, `"json"`},
+		{"json schema", &ResponseFormat{Type: ResponseJSONSchema, Schema: schema},
+			`{"type":"object","properties":{"score":{"type":"integer"}},"additionalProperties":false}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var format json.RawMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Format json.RawMessage `json:"format"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+				format = req.Format
+				_, _ = io.WriteString(w, `{"message":{"role":"assistant","content":"{}"},"done":true}`)
+			}))
+			defer server.Close()
+
+			client := NewOllamaClient(Config{BaseURL: server.URL, ResponseFormat: tt.format})
+			if _, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}); err != nil {
+				t.Fatalf("Chat() error = %v", err)
+			}
+			if got := string(format); got != tt.want {
+				t.Errorf("format = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOllamaClient_HandleStream_Thinking(t *testing.T) {
+	streamData := `{"message":{"role":"assistant","content":"","thinking":"hmm"},"done":false}
+{"message":{"role":"assistant","content":"answer"},"done":true}
+`
+	client := &OllamaClient{}
+	var reasoning string
+	got, _, err := client.handleStream(strings.NewReader(streamData), nil, func(s string) { reasoning += s })
+	if err != nil {
+		t.Fatalf("handleStream() error = %v", err)
+	}
+	if got != "answer" || reasoning != "hmm" {
+		t.Errorf("handleStream() = %q, reasoning %q; want answer, hmm", got, reasoning)
+	}
+}
+
+func TestOllamaClient_HandleStream_Error(t *testing.T) {
+	streamData := `{"message":{"content":"part"},"done":false}
+{"error":"model 'nope' not found"}
+`
+	client := &OllamaClient{}
+	got, _, err := client.handleStream(strings.NewReader(streamData), nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("handleStream() error = %v, want model not found", err)
+	}
+	if got != "part" {
+		t.Errorf("handleStream() = %q, want %q", got, "part")
+	}
+}
+
+func TestOllamaClient_HandleStream_Truncated(t *testing.T) {
+	client := &OllamaClient{}
+	for name, streamData := range map[string]string{
+		"no done":      `{"message":{"content":"part"},"done":false}` + "\n",
+		"cut mid-line": `{"message":{"content":"part"},"done":false}` + "\n" + `{"message":{"conte`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, _, err := client.handleStream(strings.NewReader(streamData), nil, nil)
+			if !errors.Is(err, ErrStreamTruncated) || got != "part" {
+				t.Errorf("handleStream() = %q, %v; want the partial answer and ErrStreamTruncated", got, err)
+			}
+		})
+	}
+
+	_, _, err := client.handleStream(strings.NewReader("Loading model...\n"), nil, nil)
+	if err == nil || errors.Is(err, ErrStreamTruncated) {
+		t.Errorf("handleStream() error = %v, want a read error for a line that is not JSON", err)
+	}
+
+	// Lines longer than bufio.Scanner's default limit are read whole
+	long := strings.Repeat("长", 100_000)
+	got, _, err := client.handleStream(strings.NewReader(`{"message":{"content":"`+long+`"},"done":true}`), nil, nil)
+	if err != nil || got != long {
+		t.Errorf("handleStream() = %d bytes, %v; want the long line", len(got), err)
+	}
+}
+
+func TestListOllamaModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			t.Errorf("Path = %s, want /api/tags", r.URL.Path)
+		}
+		_, _ = io.WriteString(w, `{"models":[{"name":"llama3.1:latest","size":4661224676},{"name":"qwen2.5:7b","size":4683087332}]}`)
+	}))
+	defer server.Close()
+
+	models, err := ListOllamaModels(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("ListOllamaModels() error = %v", err)
+	}
+	if len(models) != 2 || models[0].Name != "llama3.1:latest" {
+		t.Errorf("ListOllamaModels() = %+v", models)
+	}
+}
+
+func TestListOllamaModels_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	defer func(timeout time.Duration) { ollamaListTimeout = timeout }(ollamaListTimeout)
+	ollamaListTimeout = 50 * time.Millisecond
+
+	if _, err := ListOllamaModels(context.Background(), server.URL); err == nil {
+		t.Error("ListOllamaModels() should give up on a server that never answers")
+	}
+}
@@ -0,0 +1,384 @@
+package llm
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const openaiBaseURL = "https://api.openai.com/v1"
+
+// OpenAIClient implements the Client interface for any OpenAI-compatible
+// chat completions endpoint (OpenAI, vLLM, LM Studio, OpenRouter, gateways)
+type OpenAIClient struct {
+	apiKey string
+	cfg    Config
+	http   *http.Client
+	usage  usageCounter
+
+	url       string // chat completions endpoint
+	keyHeader string // header carrying apiKey as is; empty sends a Bearer token
+}
+
+// NewOpenAIClient creates a new OpenAI-compatible client.
+// An empty apiKey omits the Authorization header, which suits local servers.
+func NewOpenAIClient(apiKey string, cfg Config) *OpenAIClient {
+	if cfg.Provider == "" {
+		cfg.Provider = ProviderOpenAI
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = openaiBaseURL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &OpenAIClient{
+		apiKey: apiKey,
+		cfg:    cfg,
+		http:   cfg.httpClient(),
+		url:    cfg.BaseURL + "/chat/completions",
+	}
+}
+
+type openAIRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	Temperature float64         `json:"temperature,omitempty"`
+	TopP        float64         `json:"top_p,omitempty"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Stream      bool            `json:"stream,omitempty"`
+
+	StreamOptions  *openAIStreamOptions  `json:"stream_options,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	Tools          []openAITool          `json:"tools,omitempty"`
+}
+
+type openAITool struct {
+	Type     string         `json:"type"` // always "function"
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Parameters  *Schema `json:"parameters"`
+}
+
+type openAIToolCall struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Function openAIFunctionCall `json:"function"`
+}
+
+type openAIFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON-encoded object
+}
+
+// openAIStreamOptions asks for a final usage chunk on streamed responses
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIResponseFormat struct {
+	Type       ResponseFormatType `json:"type"`
+	JSONSchema *openAIJSONSchema  `json:"json_schema,omitempty"`
+}
+
+type openAIJSONSchema struct {
+	Name   string  `json:"name"`
+	Schema *Schema `json:"schema"`
+	Strict bool    `json:"strict"`
+}
+
+// responseFormat maps cfg.ResponseFormat to the request field. DeepSeek
+// rejects json_schema, so it gets json_object and relies on the prompt.
+func (c *OpenAIClient) responseFormat() *openAIResponseFormat {
+	f := c.cfg.ResponseFormat
+	if f == nil || f.Type == ResponseText {
+		return nil
+	}
+	if !f.JSONSchema() || c.cfg.Provider == ProviderDeepSeek {
+		return &openAIResponseFormat{Type: ResponseJSONObject}
+	}
+	name := f.Name
+	if name == "" {
+		name = "response"
+	}
+	return &openAIResponseFormat{
+		Type:       ResponseJSONSchema,
+		JSONSchema: &openAIJSONSchema{Name: name, Schema: f.Schema, Strict: true},
+	}
+}
+
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content          string           `json:"content"`
+			ReasoningContent string           `json:"reasoning_content"`
+			Reasoning        string           `json:"reasoning"`
+			ToolCalls        []openAIToolCall `json:"tool_calls"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+type openAIStreamDelta struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+			// reasoning_content is used by DeepSeek and Qwen thinking models,
+			// reasoning by OpenRouter and vLLM
+			ReasoningContent string `json:"reasoning_content"`
+			Reasoning        string `json:"reasoning"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"` // null until the last chunk
+	} `json:"choices"`
+	Usage *openAIUsage    `json:"usage"` // only on the final chunk with include_usage
+	Error json.RawMessage `json:"error"` // set when the provider fails mid-stream
+}
+
+type openAIUsage struct {
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
+	PromptCacheHitTokens int `json:"prompt_cache_hit_tokens"` // DeepSeek
+}
+
+func (u *openAIUsage) toUsage() Usage {
+	cached := u.PromptTokensDetails.CachedTokens
+	if cached == 0 {
+		cached = u.PromptCacheHitTokens
+	}
+	return Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		CachedTokens:     cached,
+	}
+}
+
+// Usage returns the tokens consumed by this client so far
+func (c *OpenAIClient) Usage() Usage {
+	return c.usage.get()
+}
+
+// Close is a no-op; the HTTP client may be shared with other clients
+func (c *OpenAIClient) Close() error {
+	return nil
+}
+
+func (c *OpenAIClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	msg, err := c.complete(ctx, c.newRequest(messages, false))
+	return msg.Content, err
+}
+
+func (c *OpenAIClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	resp, err := c.post(ctx, c.newRequest(messages, true))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	content, finishReason, err := c.handleStream(resp.Body, onChunk, reasoningFunc(ctx))
+	if err == nil {
+		reportFinish(ctx, openAIFinishReason(finishReason))
+	}
+	return content, err
+}
+
+// ChatTools sends tools as function definitions and returns the assistant
+// message, with tool_calls converted to ToolCalls
+func (c *OpenAIClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	req := c.newRequest(messages, false)
+	for _, t := range tools {
+		params := t.Parameters
+		if params == nil {
+			params = &Schema{Type: "object"}
+		}
+		req.Tools = append(req.Tools, openAITool{
+			Type:     "function",
+			Function: openAIFunction{Name: t.Name, Description: t.Description, Parameters: params},
+		})
+	}
+	return c.complete(ctx, req)
+}
+
+func (c *OpenAIClient) newRequest(messages []Message, stream bool) openAIRequest {
+	oaiMessages := make([]openAIMessage, len(messages))
+	for i, m := range messages {
+		oaiMessages[i] = openAIMessage{Role: m.Role, Content: m.Content, ToolCallID: m.ToolCallID}
+		for _, call := range m.ToolCalls {
+			oaiMessages[i].ToolCalls = append(oaiMessages[i].ToolCalls, openAIToolCall{
+				ID:       call.ID,
+				Type:     "function",
+				Function: openAIFunctionCall{Name: call.Name, Arguments: call.Arguments},
+			})
+		}
+	}
+
+	req := openAIRequest{
+		Model:       c.cfg.Model,
+		Messages:    oaiMessages,
+		Temperature: c.cfg.Temperature,
+		TopP:        c.cfg.TopP,
+		MaxTokens:   c.cfg.MaxTokens,
+		Stream:      stream,
+
+		ResponseFormat: c.responseFormat(),
+	}
+	if stream {
+		req.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+	}
+	return req
+}
+
+// post sends req and returns the response when the status is OK
+func (c *OpenAIClient) post(ctx context.Context, req openAIRequest) (*http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	switch {
+	case c.apiKey == "":
+	case c.keyHeader != "":
+		httpReq.Header.Set(c.keyHeader, c.apiKey)
+	default:
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	for k, v := range c.cfg.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp, c.cfg.Provider, c.cfg.Model)
+	}
+	return resp, nil
+}
+
+// complete sends a non-streaming request and returns the first choice
+func (c *OpenAIClient) complete(ctx context.Context, req openAIRequest) (Message, error) {
+	resp, err := c.post(ctx, req)
+	if err != nil {
+		return Message{}, err
+	}
+	defer resp.Body.Close()
+
+	var oaiResp openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&oaiResp); err != nil {
+		return Message{}, fmt.Errorf("decode response: %w", err)
+	}
+
+	if oaiResp.Usage != nil {
+		c.usage.add(oaiResp.Usage.toUsage())
+	}
+
+	if len(oaiResp.Choices) == 0 {
+		return Message{}, fmt.Errorf("no choices in response")
+	}
+
+	msg := oaiResp.Choices[0].Message
+	reportFinish(ctx, openAIFinishReason(oaiResp.Choices[0].FinishReason))
+	if onReasoning := reasoningFunc(ctx); onReasoning != nil {
+		if reasoning := cmp.Or(msg.ReasoningContent, msg.Reasoning); reasoning != "" {
+			onReasoning(reasoning)
+		}
+	}
+	result := Message{Role: "assistant", Content: msg.Content}
+	for _, call := range msg.ToolCalls {
+		result.ToolCalls = append(result.ToolCalls, ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: call.Function.Arguments})
+	}
+	return result, nil
+}
+
+// handleStream decodes an OpenAI-compatible SSE stream and returns the
+// content and the finish reason of the first choice. A stream that ends
+// without [DONE] or a finish reason is reported as ErrStreamTruncated, and an
+// error payload sent mid-stream (an "error" event or a chunk with an error
+// field) as an APIError; both come with the content received so far.
+func (c *OpenAIClient) handleStream(body io.Reader, onChunk, onReasoning func(string)) (string, string, error) {
+	var fullContent strings.Builder
+	var finishReason string
+	decoder := newSSEDecoder(body)
+
+	for {
+		event, err := decoder.Next()
+		if err == io.EOF {
+			if finishReason == "" {
+				return fullContent.String(), "", ErrStreamTruncated
+			}
+			return fullContent.String(), finishReason, nil
+		}
+		if err != nil {
+			return fullContent.String(), finishReason, fmt.Errorf("read stream: %w", err)
+		}
+
+		if event.Data == "[DONE]" {
+			return fullContent.String(), finishReason, nil
+		}
+		if event.Type == "error" {
+			return fullContent.String(), finishReason, c.streamError(event.Data)
+		}
+
+		var delta openAIStreamDelta
+		if err := json.Unmarshal([]byte(event.Data), &delta); err != nil {
+			return fullContent.String(), finishReason, fmt.Errorf("decode stream chunk: %w", err)
+		}
+		if len(delta.Error) > 0 && string(delta.Error) != "null" {
+			return fullContent.String(), finishReason, c.streamError(event.Data)
+		}
+
+		if delta.Usage != nil {
+			c.usage.add(delta.Usage.toUsage())
+		}
+
+		if len(delta.Choices) > 0 {
+			choice := delta.Choices[0]
+			if choice.FinishReason != "" {
+				finishReason = choice.FinishReason
+			}
+			d := choice.Delta
+			if reasoning := cmp.Or(d.ReasoningContent, d.Reasoning); reasoning != "" && onReasoning != nil {
+				onReasoning(reasoning)
+			}
+			content := d.Content
+			if content == "" {
+				continue
+			}
+			fullContent.WriteString(content)
+			if onChunk != nil {
+				onChunk(content)
+			}
+		}
+	}
+}
+
+// streamError builds the typed error for an error payload received mid-stream
+func (c *OpenAIClient) streamError(data string) error {
+	apiErr := &APIError{Provider: c.cfg.Provider, Model: c.cfg.Model, Body: data}
+	apiErr.parseBody()
+	return classifyAPIError(apiErr)
+}
@@ -0,0 +1,434 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewOpenAIClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		wantBaseURL string
+	}{
+		{
+			name:        "default base URL",
+			cfg:         Config{Model: "gpt-4o-mini"},
+			wantBaseURL: openaiBaseURL,
+		},
+		{
+			name:        "custom base URL - trailing slash trimmed",
+			cfg:         Config{Model: "local", BaseURL: "http://localhost:8000/v1/"},
+			wantBaseURL: "http://localhost:8000/v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewOpenAIClient("key", tt.cfg)
+			if client.cfg.BaseURL != tt.wantBaseURL {
+				t.Errorf("client.cfg.BaseURL = %v, want %v", client.cfg.BaseURL, tt.wantBaseURL)
+			}
+			if client.http == nil {
+				t.Error("client.http is nil")
+			}
+		})
+	}
+}
+
+func TestOpenAIClient_PresetBaseURLs(t *testing.T) {
+	if got := NewDeepSeekClient("k", Config{}).cfg.BaseURL; got != deepseekBaseURL {
+		t.Errorf("DeepSeek BaseURL = %v, want %v", got, deepseekBaseURL)
+	}
+	if got := NewDashScopeClient("k", Config{}).cfg.BaseURL; got != dashscopeBaseURL {
+		t.Errorf("DashScope BaseURL = %v, want %v", got, dashscopeBaseURL)
+	}
+	if got := NewDeepSeekClient("k", Config{BaseURL: "http://gateway/v1"}).cfg.BaseURL; got != "http://gateway/v1" {
+		t.Errorf("DeepSeek BaseURL override = %v, want %v", got, "http://gateway/v1")
+	}
+}
+
+func TestOpenAIClient_Chat_MockServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Path = %s, want /v1/chat/completions", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-api-key" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("X-Gateway-Tenant") != "debate" {
+			t.Errorf("X-Gateway-Tenant = %q, want debate", r.Header.Get("X-Gateway-Tenant"))
+		}
+
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Model != "test-model" {
+			t.Errorf("Model = %v, want test-model", req.Model)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(mockOpenAIResponse("This is a test response")))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient("test-api-key", Config{
+		Model:   "test-model",
+		BaseURL: server.URL + "/v1",
+		Headers: map[string]string{"X-Gateway-Tenant": "debate"},
+	})
+
+	got, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if got != "This is a test response" {
+		t.Errorf("Chat() = %v, want %v", got, "This is a test response")
+	}
+}
+
+func TestOpenAIClient_ResponseFormat(t *testing.T) {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{"one_liner": {Type: "string"}}, Required: []string{"one_liner"}}
+	tests := []struct {
+		name     string
+		provider Provider
+		format   *ResponseFormat
+		want     string
+	}{
+		{"text", ProviderOpenAI, nil, ""},
+		{"json object", ProviderOpenAI, &ResponseFormat{Type: ResponseJSONObject}, `{"type":"json_object"}`},
+		{"json schema", ProviderOpenAI, &ResponseFormat{Type: ResponseJSONSchema, Name: "argument", Schema: schema},
+			`{"type":"json_schema","json_schema":{"name":"argument","schema":{"type":"object","properties":{"one_liner":{"type":"string"}},"required":["one_liner"],"additionalProperties":false},"strict":true}}`},
+		{"deepseek downgrades json schema", ProviderDeepSeek, &ResponseFormat{Type: ResponseJSONSchema, Schema: schema}, `{"type":"json_object"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewOpenAIClient("key", Config{Provider: tt.provider, ResponseFormat: tt.format})
+			got := ""
+			if f := client.responseFormat(); f != nil {
+				data, err := json.Marshal(f)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = string(data)
+			}
+			if got != tt.want {
+				t.Errorf("response_format = %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIClient_ChatTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if len(req.Tools) != 1 || req.Tools[0].Type != "function" || req.Tools[0].Function.Name != "calculator" {
+			t.Errorf("Tools = %+v", req.Tools)
+		}
+		if len(req.Messages) != 3 {
+			t.Fatalf("Messages = %+v, want user, assistant call, tool result", req.Messages)
+		}
+		if call := req.Messages[1].ToolCalls; len(call) != 1 || call[0].ID != "call_a" || call[0].Function.Arguments != `{"expression":"1+1"}` {
+			t.Errorf("assistant tool_calls = %+v", call)
+		}
+		if m := req.Messages[2]; m.Role != "tool" || m.ToolCallID != "call_a" || m.Content != "2" {
+			t.Errorf("tool message = %+v", m)
+		}
+
+		_, _ = io.WriteString(w, `{"choices":[{"message":{"content":"","tool_calls":[
+			{"id":"call_b","type":"function","function":{"name":"calculator","arguments":"{\"expression\":\"2*3\"}"}}
+		]}}],"usage":{"prompt_tokens":20,"completion_tokens":4}}`)
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient("key", Config{Model: "gpt-4o", BaseURL: server.URL})
+	call := ToolCall{ID: "call_a", Name: "calculator", Arguments: `{"expression":"1+1"}`}
+	reply, err := client.ChatTools(context.Background(), []Message{
+		{Role: "user", Content: "1+1, then double it"},
+		{Role: "assistant", ToolCalls: []ToolCall{call}},
+		ToolResultMessage(call, "2"),
+	}, []Tool{{Name: "calculator", Parameters: &Schema{Type: "object"}}})
+	if err != nil {
+		t.Fatalf("ChatTools() error = %v", err)
+	}
+	want := ToolCall{ID: "call_b", Name: "calculator", Arguments: `{"expression":"2*3"}`}
+	if reply.Role != "assistant" || len(reply.ToolCalls) != 1 || reply.ToolCalls[0] != want {
+		t.Errorf("ChatTools() = %+v, want a call to %+v", reply, want)
+	}
+	if got := client.Usage().TotalTokens(); got != 24 {
+		t.Errorf("Usage() = %d tokens, want 24", got)
+	}
+}
+
+func TestOpenAIClient_ChatStream_NoAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization = %q, want empty", auth)
+		}
+		_, _ = io.WriteString(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\n\ndata: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient("", Config{Model: "local", BaseURL: server.URL})
+
+	var chunks []string
+	got, err := client.ChatStream(context.Background(), []Message{{Role: "user", Content: "hi"}}, func(s string) {
+		chunks = append(chunks, s)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if got != "Hello" || len(chunks) != 1 {
+		t.Errorf("ChatStream() = %q with %d chunks, want %q with 1 chunk", got, len(chunks), "Hello")
+	}
+}
+
+func TestOpenAIClient_ChatStream_Usage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.StreamOptions == nil || !req.StreamOptions.IncludeUsage {
+			t.Errorf("stream_options = %+v, want include_usage", req.StreamOptions)
+		}
+		_, _ = io.WriteString(w, `data: {"choices":[{"delta":{"content":"Hi"}}]}
+
+data: {"choices":[],"usage":{"prompt_tokens":120,"completion_tokens":30,"prompt_tokens_details":{"cached_tokens":100}}}
+
+data: [DONE]
+
+`)
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient("k", Config{BaseURL: server.URL})
+	if _, err := client.ChatStream(context.Background(), []Message{{Role: "user", Content: "hi"}}, nil); err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	want := Usage{PromptTokens: 120, CompletionTokens: 30, CachedTokens: 100}
+	if got := client.Usage(); got != want {
+		t.Errorf("Usage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestOpenAIClient_Chat_Usage_DeepSeekCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":50,"completion_tokens":5,"prompt_cache_hit_tokens":40}}`)
+	}))
+	defer server.Close()
+
+	client := NewDeepSeekClient("k", Config{BaseURL: server.URL})
+	for i := 0; i < 2; i++ {
+		if _, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}); err != nil {
+			t.Fatalf("Chat() error = %v", err)
+		}
+	}
+	want := Usage{PromptTokens: 100, CompletionTokens: 10, CachedTokens: 80}
+	if got := client.Usage(); got != want {
+		t.Errorf("Usage() after two calls = %+v, want %+v", got, want)
+	}
+}
+
+func TestOpenAIClient_ChatStream_Reasoning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `data: {"choices":[{"delta":{"reasoning_content":"Let me "}}]}
+
+data: {"choices":[{"delta":{"reasoning_content":"think."}}]}
+
+data: {"choices":[{"delta":{"content":"## 💡 One-Liner"}}]}
+
+data: [DONE]
+
+`)
+	}))
+	defer server.Close()
+
+	var reasoning strings.Builder
+	ctx := WithReasoning(context.Background(), func(s string) { reasoning.WriteString(s) })
+
+	client := NewDeepSeekClient("k", Config{Model: "deepseek-reasoner", BaseURL: server.URL})
+	var chunks []string
+	got, err := client.ChatStream(ctx, []Message{{Role: "user", Content: "hi"}}, func(s string) {
+		chunks = append(chunks, s)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if got != "## 💡 One-Liner" || len(chunks) != 1 {
+		t.Errorf("ChatStream() = %q with %d chunks, want answer only", got, len(chunks))
+	}
+	if reasoning.String() != "Let me think." {
+		t.Errorf("reasoning = %q, want %q", reasoning.String(), "Let me think.")
+	}
+}
+
+func TestOpenAIClient_Chat_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad key"}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient("bad", Config{BaseURL: server.URL})
+	_, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Chat() error = %v, want status 401", err)
+	}
+}
+
+func TestOpenAIClient_HandleStream(t *testing.T) {
+	tests := []struct {
+		name       string
+		stream     string
+		want       string
+		wantFinish string
+		wantErr    func(error) bool
+	}{
+		{
+			name:       "finish reason",
+			stream:     "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\r\n\r\ndata: {\"choices\":[{\"delta\":{},\"finish_reason\":\"length\"}]}\r\n\r\ndata: [DONE]\r\n\r\n",
+			want:       "Hi",
+			wantFinish: "length",
+		},
+		{
+			name:       "finish reason without DONE",
+			stream:     "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"},\"finish_reason\":\"stop\"}]}\n\n",
+			want:       "Hi",
+			wantFinish: "stop",
+		},
+		{
+			name:    "truncated",
+			stream:  "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n",
+			want:    "Hi",
+			wantErr: func(err error) bool { return errors.Is(err, ErrStreamTruncated) && IsRetryable(err) },
+		},
+		{
+			name:    "error payload",
+			stream:  "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\ndata: {\"error\":{\"type\":\"server_error\",\"message\":\"boom\"}}\n\n",
+			want:    "Hi",
+			wantErr: func(err error) bool { var e *ServerError; return errors.As(err, &e) && e.Message == "boom" },
+		},
+		{
+			name:    "error event",
+			stream:  "event: error\ndata: {\"error\":{\"code\":\"rate_limit_exceeded\",\"message\":\"slow down\"}}\n\n",
+			wantErr: func(err error) bool { var e *RateLimitError; return errors.As(err, &e) },
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: {\"choices\":[{\"delta\":\ndata: {\"content\":\"Hi\"}}]}\n\ndata: [DONE]\n\n",
+			want:   "Hi",
+		},
+		{
+			name:   "keep-alive comments",
+			stream: ": OPENROUTER PROCESSING\n\ndata: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\ndata: [DONE]\n\n",
+			want:   "Hi",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewOpenAIClient("", Config{})
+			got, finish, err := client.handleStream(strings.NewReader(tt.stream), nil, nil)
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Errorf("handleStream() error = %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("handleStream() error = %v", err)
+			}
+			if got != tt.want || finish != tt.wantFinish {
+				t.Errorf("handleStream() = %q, %q; want %q, %q", got, finish, tt.want, tt.wantFinish)
+			}
+		})
+	}
+}
+
+func TestResolveOpenAIEndpoint(t *testing.T) {
+	origKey := os.Getenv("OPENAI_API_KEY")
+	origURL := os.Getenv("OPENAI_BASE_URL")
+	origCustom := os.Getenv("GATEWAY_KEY")
+	defer func() {
+		os.Setenv("OPENAI_API_KEY", origKey)
+		os.Setenv("OPENAI_BASE_URL", origURL)
+		os.Setenv("GATEWAY_KEY", origCustom)
+	}()
+
+	tests := []struct {
+		name        string
+		setup       func()
+		cfg         Config
+		wantKey     string
+		wantBaseURL string
+		wantErr     bool
+	}{
+		{
+			name:    "public API requires key",
+			setup:   func() {},
+			cfg:     Config{},
+			wantErr: true,
+		},
+		{
+			name:    "public API with key",
+			setup:   func() { os.Setenv("OPENAI_API_KEY", "sk-test") },
+			cfg:     Config{},
+			wantKey: "sk-test",
+		},
+		{
+			name:        "local server without key",
+			setup:       func() {},
+			cfg:         Config{BaseURL: "http://localhost:1234/v1"},
+			wantBaseURL: "http://localhost:1234/v1",
+		},
+		{
+			name:        "base URL from environment",
+			setup:       func() { os.Setenv("OPENAI_BASE_URL", "http://vllm:8000/v1") },
+			cfg:         Config{},
+			wantBaseURL: "http://vllm:8000/v1",
+		},
+		{
+			name:    "explicit key env must be set",
+			setup:   func() {},
+			cfg:     Config{BaseURL: "http://gateway/v1", APIKeyEnv: "GATEWAY_KEY"},
+			wantErr: true,
+		},
+		{
+			name:        "explicit key env",
+			setup:       func() { os.Setenv("GATEWAY_KEY", "gw-key") },
+			cfg:         Config{BaseURL: "http://gateway/v1", APIKeyEnv: "GATEWAY_KEY"},
+			wantKey:     "gw-key",
+			wantBaseURL: "http://gateway/v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Unsetenv("OPENAI_API_KEY")
+			os.Unsetenv("OPENAI_BASE_URL")
+			os.Unsetenv("GATEWAY_KEY")
+
+			tt.setup()
+
+			keys, baseURL, err := ResolveOpenAIEndpoint(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveOpenAIEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if key := strings.Join(keys, ","); key != tt.wantKey {
+				t.Errorf("apiKeys = %v, want %v", key, tt.wantKey)
+			}
+			if baseURL != tt.wantBaseURL {
+				t.Errorf("baseURL = %v, want %v", baseURL, tt.wantBaseURL)
+			}
+		})
+	}
+}
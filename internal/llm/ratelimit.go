@@ -0,0 +1,288 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// RateLimit caps traffic to one provider account. Zero fields are unlimited.
+type RateLimit struct {
+	RequestsPerMinute int // requests started in any 60s window
+	TokensPerMinute   int // prompt plus completion tokens in any 60s window
+	MaxInFlight       int // concurrent requests
+}
+
+// IsZero reports whether no limit is set
+func (l RateLimit) IsZero() bool {
+	return l == RateLimit{}
+}
+
+func (l RateLimit) String() string {
+	if l.IsZero() {
+		return "unlimited"
+	}
+	var parts []string
+	for _, f := range []struct {
+		n    int
+		unit string
+	}{{l.RequestsPerMinute, "rpm"}, {l.TokensPerMinute, "tpm"}, {l.MaxInFlight, "in-flight"}} {
+		if f.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", f.n, f.unit))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// rateWindow is the sliding window RequestsPerMinute and TokensPerMinute
+// are measured over
+const rateWindow = time.Minute
+
+// rateLimiters holds one limiter per provider and API key for the whole
+// process, so every client sharing an account shares its budget
+var rateLimiters = struct {
+	mu sync.Mutex
+	m  map[string]*rateLimiter
+}{m: make(map[string]*rateLimiter)}
+
+// limiterFor returns the shared limiter for provider and apiKey. A non-zero
+// limit replaces the limiter's current settings.
+func limiterFor(provider Provider, apiKey string, limit RateLimit) *rateLimiter {
+	sum := sha256.Sum256([]byte(apiKey))
+	key := string(provider) + "/" + hex.EncodeToString(sum[:8])
+
+	rateLimiters.mu.Lock()
+	defer rateLimiters.mu.Unlock()
+	l, ok := rateLimiters.m[key]
+	if !ok {
+		l = newRateLimiter(limit)
+		rateLimiters.m[key] = l
+	} else if !limit.IsZero() {
+		l.setLimit(limit)
+	}
+	return l
+}
+
+// rateLimiter enforces a RateLimit with a sliding window of recent requests.
+// Callers block in acquire until the request fits; nothing is rejected.
+type rateLimiter struct {
+	now func() time.Time
+
+	mu          sync.Mutex
+	limit       RateLimit
+	inFlight    int
+	window      []*rateEvent // requests started within rateWindow, oldest first
+	pausedUntil time.Time    // set when the provider answers 429
+	changed     chan struct{}
+}
+
+type rateEvent struct {
+	at     time.Time
+	tokens int
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	return &rateLimiter{now: time.Now, limit: limit, changed: make(chan struct{})}
+}
+
+func (l *rateLimiter) setLimit(limit RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+	l.notify()
+}
+
+// notify wakes all waiters; l.mu must be held
+func (l *rateLimiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// acquire blocks until a request estimated at tokens fits the limit, then
+// reserves it. The returned release must be called with the tokens actually
+// used (0 keeps the estimate).
+func (l *rateLimiter) acquire(ctx context.Context, tokens int) (release func(used int), err error) {
+	for {
+		l.mu.Lock()
+		wait := l.delay(tokens)
+		if wait == 0 {
+			event := &rateEvent{at: l.now(), tokens: tokens}
+			l.window = append(l.window, event)
+			l.inFlight++
+			l.mu.Unlock()
+			return func(used int) { l.release(event, used) }, nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		if err := waitChange(ctx, changed, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitChange waits for the limiter to change or, when wait > 0, for wait
+func waitChange(ctx context.Context, changed <-chan struct{}, wait time.Duration) error {
+	var timeout <-chan time.Time
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+	case <-timeout:
+	}
+	return nil
+}
+
+// delay returns 0 when a request of tokens may start now, the time until
+// the window frees up, or -1 to wait for a running request to finish.
+// l.mu must be held.
+func (l *rateLimiter) delay(tokens int) time.Duration {
+	now := l.now()
+	if wait := l.pausedUntil.Sub(now); wait > 0 {
+		return wait
+	}
+
+	cutoff := now.Add(-rateWindow)
+	for len(l.window) > 0 && !l.window[0].at.After(cutoff) {
+		l.window = l.window[1:]
+	}
+
+	if l.limit.MaxInFlight > 0 && l.inFlight >= l.limit.MaxInFlight {
+		return -1
+	}
+	if l.limit.RequestsPerMinute > 0 && len(l.window) >= l.limit.RequestsPerMinute {
+		return l.window[0].at.Sub(cutoff)
+	}
+	if l.limit.TokensPerMinute > 0 && len(l.window) > 0 {
+		// A request larger than the whole budget runs alone rather than never
+		used := 0
+		for _, e := range l.window {
+			used += e.tokens
+		}
+		if used+tokens > l.limit.TokensPerMinute {
+			return l.window[0].at.Sub(cutoff)
+		}
+	}
+	return 0
+}
+
+func (l *rateLimiter) release(event *rateEvent, used int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if used > 0 {
+		event.tokens = used
+	}
+	l.inFlight--
+	l.notify()
+}
+
+// pause holds back every request on this account for d, after the provider
+// reported a rate limit
+func (l *rateLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := l.now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.notify()
+}
+
+// rateLimitPause is how long an account is held back after a 429 that did
+// not say how long to wait
+const rateLimitPause = 5 * time.Second
+
+// rateLimitedClient routes every request of a provider client through the
+// shared limiter for its account
+type rateLimitedClient struct {
+	inner   Client
+	limiter *rateLimiter
+}
+
+// WithRateLimit wraps client so that it waits for the process-wide limiter
+// of provider and apiKey before each request. Clients for the same account
+// share one budget; limit, when non-zero, (re)configures it.
+func WithRateLimit(client Client, provider Provider, apiKey string, limit RateLimit) Client {
+	return &rateLimitedClient{inner: client, limiter: limiterFor(provider, apiKey, limit)}
+}
+
+func (c *rateLimitedClient) Usage() Usage {
+	return c.inner.Usage()
+}
+
+func (c *rateLimitedClient) Close() error {
+	return c.inner.Close()
+}
+
+func (c *rateLimitedClient) Chat(ctx context.Context, messages []Message) (result string, err error) {
+	err = c.do(ctx, messages, func() error {
+		result, err = c.inner.Chat(ctx, messages)
+		return err
+	})
+	return result, err
+}
+
+func (c *rateLimitedClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (result string, err error) {
+	err = c.do(ctx, messages, func() error {
+		result, err = c.inner.ChatStream(ctx, messages, onChunk)
+		return err
+	})
+	return result, err
+}
+
+func (c *rateLimitedClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (result Message, err error) {
+	err = c.do(ctx, messages, func() error {
+		result, err = c.inner.ChatTools(ctx, messages, tools)
+		return err
+	})
+	return result, err
+}
+
+func (c *rateLimitedClient) do(ctx context.Context, messages []Message, call func() error) error {
+	release, err := c.limiter.acquire(ctx, estimateTokens(messages))
+	if err != nil {
+		return err
+	}
+
+	before := c.inner.Usage()
+	err = call()
+	used := c.inner.Usage().TotalTokens() - before.TotalTokens()
+	release(used)
+
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) {
+		pause := rateErr.RetryAfter
+		if pause <= 0 {
+			pause = rateLimitPause
+		}
+		c.limiter.pause(pause)
+	}
+	return err
+}
+
+// estimateTokens roughly sizes a prompt before it is sent: about four bytes
+// per token for ASCII text and one token per character otherwise
+func estimateTokens(messages []Message) int {
+	tokens := 0
+	for _, m := range messages {
+		runes := utf8.RuneCountInString(m.Content)
+		ascii := 0
+		for i := 0; i < len(m.Content); i++ {
+			if m.Content[i] < utf8.RuneSelf {
+				ascii++
+			}
+		}
+		tokens += (runes - ascii) + (ascii+3)/4
+	}
+	return tokens
+}
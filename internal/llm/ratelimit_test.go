@@ -0,0 +1,180 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable time source for the limiter
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter(limit RateLimit) (*rateLimiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := newRateLimiter(limit)
+	l.now = clock.now
+	return l, clock
+}
+
+func mustAcquire(t *testing.T, l *rateLimiter, tokens int) func(int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	release, err := l.acquire(ctx, tokens)
+	if err != nil {
+		t.Fatalf("acquire(%d) error = %v", tokens, err)
+	}
+	return release
+}
+
+func TestRateLimiter_RequestsPerMinute(t *testing.T) {
+	l, clock := newTestLimiter(RateLimit{RequestsPerMinute: 2})
+	mustAcquire(t, l, 0)(0)
+	clock.advance(10 * time.Second)
+	mustAcquire(t, l, 0)(0)
+
+	if got, want := l.delay(0), 50*time.Second; got != want {
+		t.Errorf("delay() = %v, want %v until the first request leaves the window", got, want)
+	}
+	clock.advance(50 * time.Second)
+	if got := l.delay(0); got != 0 {
+		t.Errorf("delay() = %v after the window moved, want 0", got)
+	}
+}
+
+func TestRateLimiter_TokensPerMinute(t *testing.T) {
+	l, clock := newTestLimiter(RateLimit{TokensPerMinute: 1000})
+
+	// The estimate is replaced by the actual usage on release
+	mustAcquire(t, l, 100)(700)
+	if got := l.delay(200); got != 0 {
+		t.Errorf("delay(200) = %v, want 0 with 300 tokens left", got)
+	}
+	if got := l.delay(400); got != time.Minute {
+		t.Errorf("delay(400) = %v, want %v", got, time.Minute)
+	}
+
+	// A request larger than the whole budget runs once the window is empty
+	clock.advance(time.Minute)
+	if got := l.delay(5000); got != 0 {
+		t.Errorf("delay(5000) = %v on an empty window, want 0", got)
+	}
+}
+
+func TestRateLimiter_MaxInFlight(t *testing.T) {
+	l, _ := newTestLimiter(RateLimit{MaxInFlight: 1})
+	release := mustAcquire(t, l, 0)
+
+	acquired := make(chan func(int))
+	go func() {
+		r, err := l.acquire(context.Background(), 0)
+		if err != nil {
+			t.Errorf("acquire() error = %v", err)
+		}
+		acquired <- r
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second request should wait for the first to finish")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release(0)
+	select {
+	case r := <-acquired:
+		r(0)
+	case <-time.After(time.Second):
+		t.Fatal("second request did not start after release")
+	}
+}
+
+func TestRateLimiter_WaitHonoursContext(t *testing.T) {
+	l, _ := newTestLimiter(RateLimit{MaxInFlight: 1})
+	mustAcquire(t, l, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquire() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestRateLimiter_Pause(t *testing.T) {
+	l, clock := newTestLimiter(RateLimit{})
+	l.pause(3 * time.Second)
+	if got := l.delay(0); got != 3*time.Second {
+		t.Errorf("delay() = %v, want 3s", got)
+	}
+	clock.advance(3 * time.Second)
+	if got := l.delay(0); got != 0 {
+		t.Errorf("delay() = %v after the pause, want 0", got)
+	}
+}
+
+func TestLimiterFor_SharedPerAccount(t *testing.T) {
+	a := limiterFor("test-shared", "key-1", RateLimit{RequestsPerMinute: 10})
+	b := limiterFor("test-shared", "key-1", RateLimit{})
+	if a != b {
+		t.Fatal("clients of the same provider and key should share a limiter")
+	}
+	if a.limit.RequestsPerMinute != 10 {
+		t.Errorf("zero limit should keep the existing settings, got %+v", a.limit)
+	}
+	if c := limiterFor("test-shared", "key-2", RateLimit{}); c == a {
+		t.Error("different API keys should not share a limiter")
+	}
+	if d := limiterFor("test-shared", "key-1", RateLimit{MaxInFlight: 2}); d.limit.MaxInFlight != 2 {
+		t.Errorf("non-zero limit should replace the settings, got %+v", d.limit)
+	}
+}
+
+func TestRateLimitedClient_PausesOnRateLimitError(t *testing.T) {
+	inner := &scriptedClient{errs: []error{&RateLimitError{&APIError{StatusCode: 429, RetryAfter: 7 * time.Second}}}}
+	client := WithRateLimit(inner, "test-pause", "key", RateLimit{}).(*rateLimitedClient)
+	l, _ := newTestLimiter(RateLimit{})
+	client.limiter = l
+
+	if _, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}); err == nil {
+		t.Fatal("Chat() should return the provider error")
+	}
+	if got := l.delay(0); got != 7*time.Second {
+		t.Errorf("delay() = %v, want the 7s Retry-After", got)
+	}
+	if l.inFlight != 0 {
+		t.Errorf("inFlight = %d, want 0 after the call", l.inFlight)
+	}
+	if got := l.window[0].tokens; got != 10 {
+		t.Errorf("window tokens = %d, want the reported usage 10", got)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		content string
+		want    int
+	}{
+		{"", 0},
+		{"abcdefgh", 2},
+		{"我们应该", 4},
+		{"AI 创业", 3}, // "AI " rounds up to 1, plus 2 characters
+	}
+	for _, tt := range tests {
+		if got := estimateTokens([]Message{{Content: tt.content}}); got != tt.want {
+			t.Errorf("estimateTokens(%q) = %d, want %d", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimit_String(t *testing.T) {
+	if got := (RateLimit{}).String(); got != "unlimited" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (RateLimit{RequestsPerMinute: 60, MaxInFlight: 2}).String(); got != "60 rpm, 2 in-flight" {
+		t.Errorf("String() = %q", got)
+	}
+}
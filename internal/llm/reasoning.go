@@ -0,0 +1,19 @@
+package llm
+
+import "context"
+
+type reasoningKey struct{}
+
+// WithReasoning returns a context that routes reasoning ("thinking") output
+// to onReasoning. Reasoning is delivered separately from the answer chunks
+// passed to onChunk, so it never mixes with the visible response. Providers
+// that expose no reasoning simply never call it.
+func WithReasoning(ctx context.Context, onReasoning func(string)) context.Context {
+	return context.WithValue(ctx, reasoningKey{}, onReasoning)
+}
+
+// reasoningFunc returns the reasoning callback registered on ctx, or nil
+func reasoningFunc(ctx context.Context) func(string) {
+	fn, _ := ctx.Value(reasoningKey{}).(func(string))
+	return fn
+}
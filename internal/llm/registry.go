@@ -0,0 +1,286 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ProviderSpec describes a provider to the registry. NewClient,
+// ParseProvider, configuration checks, default models, the CLI help and the
+// interactive menu are all derived from the registered specs.
+type ProviderSpec struct {
+	Name        Provider
+	Aliases     []string // other names ParseProvider accepts, e.g. "claude"
+	Description string   // one line for help text, e.g. "Alibaba Qwen"
+
+	// EnvVars are the API key variables, tried in order; empty when the
+	// provider needs no key. Config.Credentials may supply keys as well.
+	EnvVars []string
+	// EnvHelp describes the environment in help text; empty lists EnvVars
+	EnvHelp string
+
+	DefaultModel string
+	Free         bool // local or offline, never billed
+
+	// Resolve, when set, replaces the EnvVars lookup: it returns the API keys
+	// for cfg and may fill in settings such as the base URL. It also reports
+	// invalid provider settings before any client is created.
+	Resolve func(cfg Config) (apiKeys []string, resolved Config, err error)
+
+	// New creates the bare client for one API key; NewClient adds timeouts,
+	// rate limiting, key rotation, retries and caching around it
+	New func(apiKey string, cfg Config) (Client, error)
+
+	Menu *ProviderMenu // judge group in the interactive menu; nil for none
+}
+
+// ProviderMenu lists the debates offered with a provider as the judge in
+// the interactive menu
+type ProviderMenu struct {
+	Label   string        // short name used in combination names, e.g. "Qwen"
+	Icon    string        // shown before the group header
+	Order   int           // groups are listed by ascending Order
+	Debates [][2]Provider // Pro and Con providers, in menu order
+}
+
+// Credentials returns the API keys for cfg, in rotation order, and the
+// config to create the clients with, or an error naming what is missing.
+// Keys come from EnvVars and cfg.Credentials.
+func (s ProviderSpec) Credentials(cfg Config) ([]string, Config, error) {
+	if s.Resolve != nil {
+		return s.Resolve(cfg)
+	}
+	if len(s.EnvVars) == 0 {
+		return nil, cfg, nil
+	}
+	keys, err := cfg.Credentials.Keys(s.Name, s.EnvVars)
+	if err == nil && len(keys) == 0 {
+		err = cfg.Credentials.missingKeyError(s.Name, s.EnvVars)
+	}
+	return keys, cfg, err
+}
+
+// EnvSummary describes the provider's environment for help text
+func (s ProviderSpec) EnvSummary() string {
+	if s.EnvHelp != "" {
+		return s.EnvHelp
+	}
+	return strings.Join(s.EnvVars, " / ")
+}
+
+// providerRegistry holds the registered providers in registration order
+type providerRegistry struct {
+	mu    sync.RWMutex
+	specs []ProviderSpec
+	names map[string]Provider // names and aliases
+}
+
+// registry is filled during package initialisation, so the built-in
+// providers precede any registered from an init function
+var registry = newProviderRegistry(builtinProviders)
+
+func newProviderRegistry(specs []ProviderSpec) *providerRegistry {
+	r := &providerRegistry{names: make(map[string]Provider)}
+	for _, spec := range specs {
+		r.register(spec)
+	}
+	return r
+}
+
+// RegisterProvider adds a provider to the registry, typically from an init
+// function. It panics if the spec has no name or constructor, or if its
+// name or an alias is taken, so that a conflicting registration fails at
+// startup.
+func RegisterProvider(spec ProviderSpec) {
+	registry.register(spec)
+}
+
+func (r *providerRegistry) register(spec ProviderSpec) {
+	if spec.Name == "" || spec.New == nil {
+		panic("llm: RegisterProvider needs a name and a constructor")
+	}
+	names := append([]string{string(spec.Name)}, spec.Aliases...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		if _, dup := r.names[name]; dup {
+			panic(fmt.Sprintf("llm: provider name %q registered twice", name))
+		}
+	}
+	for _, name := range names {
+		r.names[name] = spec.Name
+	}
+	r.specs = append(r.specs, spec)
+}
+
+// LookupProvider returns the spec registered under name or one of its
+// aliases
+func LookupProvider(name string) (ProviderSpec, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	provider, ok := registry.names[name]
+	if !ok {
+		return ProviderSpec{}, false
+	}
+	for _, spec := range registry.specs {
+		if spec.Name == provider {
+			return spec, true
+		}
+	}
+	return ProviderSpec{}, false
+}
+
+// Providers returns every registered provider in registration order
+func Providers() []ProviderSpec {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return append([]ProviderSpec(nil), registry.specs...)
+}
+
+// ProviderNames returns the registered provider names, comma-separated
+func ProviderNames() string {
+	var names []string
+	for _, spec := range Providers() {
+		names = append(names, string(spec.Name))
+	}
+	return strings.Join(names, ", ")
+}
+
+// builtinProviders are registered first, in this order
+var builtinProviders = []ProviderSpec{
+	{
+		Name:         ProviderDeepSeek,
+		Description:  "DeepSeek API",
+		EnvVars:      []string{"DEEPSEEK_API_KEY"},
+		DefaultModel: "deepseek-chat",
+		New: func(apiKey string, cfg Config) (Client, error) {
+			return NewDeepSeekClient(apiKey, cfg), nil
+		},
+		Menu: &ProviderMenu{Label: "DeepSeek", Icon: "⚡", Order: 2, Debates: [][2]Provider{
+			{ProviderDeepSeek, ProviderDeepSeek},
+			{ProviderDeepSeek, ProviderDashScope},
+			{ProviderDashScope, ProviderDashScope},
+		}},
+	},
+	{
+		Name:         ProviderGemini,
+		Aliases:      []string{"google"},
+		Description:  "Google Gemini",
+		EnvVars:      []string{"GEMINI_API_KEY", "GOOGLE_API_KEY"},
+		DefaultModel: "gemini-3-pro-preview",
+		New: func(apiKey string, cfg Config) (Client, error) {
+			return NewGeminiClient(apiKey, cfg), nil
+		},
+		Menu: &ProviderMenu{Label: "Gemini", Icon: "🌟", Order: 1, Debates: [][2]Provider{
+			{ProviderGemini, ProviderGemini},
+			{ProviderDeepSeek, ProviderDeepSeek},
+			{ProviderDeepSeek, ProviderDashScope},
+			{ProviderDashScope, ProviderDashScope},
+		}},
+	},
+	{
+		Name:         ProviderDashScope,
+		Aliases:      []string{"qwen", "alibaba"},
+		Description:  "Alibaba Qwen",
+		EnvVars:      []string{"DASHSCOPE_API_KEY"},
+		DefaultModel: "qwen-plus",
+		New: func(apiKey string, cfg Config) (Client, error) {
+			return NewDashScopeClient(apiKey, cfg), nil
+		},
+		Menu: &ProviderMenu{Label: "Qwen", Icon: "🔷", Order: 3, Debates: [][2]Provider{
+			{ProviderDashScope, ProviderDashScope},
+		}},
+	},
+	{
+		Name:         ProviderOpenAI,
+		Aliases:      []string{"openai-compatible"},
+		Description:  "OpenAI-compatible",
+		EnvVars:      []string{"OPENAI_API_KEY"},
+		EnvHelp:      "OPENAI_API_KEY / OPENAI_BASE_URL",
+		DefaultModel: "gpt-4o-mini",
+		Resolve: func(cfg Config) ([]string, Config, error) {
+			apiKey, baseURL, err := ResolveOpenAIEndpoint(cfg)
+			cfg.BaseURL = baseURL
+			return apiKey, cfg, err
+		},
+		New: func(apiKey string, cfg Config) (Client, error) {
+			return NewOpenAIClient(apiKey, cfg), nil
+		},
+	},
+	{
+		Name:         ProviderAnthropic,
+		Aliases:      []string{"claude"},
+		Description:  "Anthropic Claude",
+		EnvVars:      []string{"ANTHROPIC_API_KEY"},
+		DefaultModel: "claude-sonnet-4-5",
+		New: func(apiKey string, cfg Config) (Client, error) {
+			return NewAnthropicClient(apiKey, cfg), nil
+		},
+		Menu: &ProviderMenu{Label: "Claude", Icon: "🔶", Order: 4, Debates: [][2]Provider{
+			{ProviderDeepSeek, ProviderDashScope},
+			{ProviderAnthropic, ProviderAnthropic},
+		}},
+	},
+	{
+		Name:         ProviderAzure,
+		Aliases:      []string{"azure-openai"},
+		Description:  "Azure OpenAI",
+		EnvVars:      []string{"AZURE_OPENAI_API_KEY"},
+		EnvHelp:      "AZURE_OPENAI_API_KEY / AZURE_OPENAI_ENDPOINT",
+		DefaultModel: "gpt-4o",
+		Resolve: func(cfg Config) ([]string, Config, error) {
+			apiKeys, endpoint, err := ResolveAzureEndpoint(cfg)
+			cfg.BaseURL = endpoint
+			return apiKeys, cfg, err
+		},
+		New: func(apiKey string, cfg Config) (Client, error) {
+			return NewAzureClient(apiKey, cfg), nil
+		},
+	},
+	{
+		Name:         ProviderOllama,
+		Aliases:      []string{"local"},
+		Description:  "Local Ollama",
+		EnvHelp:      "no key (OLLAMA_HOST, offline)",
+		DefaultModel: "llama3.1",
+		Free:         true,
+		New: func(_ string, cfg Config) (Client, error) {
+			return NewOllamaClient(cfg), nil
+		},
+	},
+	{
+		Name:         ProviderMock,
+		Description:  "Offline mock",
+		EnvHelp:      "no key (DIALECTA_MOCK_*, demos and CI)",
+		DefaultModel: "mock",
+		Free:         true,
+		Resolve: func(cfg Config) ([]string, Config, error) {
+			_, err := MockOptionsFromEnv()
+			return nil, cfg, err
+		},
+		New: func(_ string, cfg Config) (Client, error) {
+			opts, err := MockOptionsFromEnv()
+			if err != nil {
+				return nil, err
+			}
+			return NewMockClient(cfg, opts), nil
+		},
+	},
+	{
+		Name:         ProviderExec,
+		Aliases:      []string{"plugin"},
+		Description:  "External plugin",
+		EnvHelp:      "no key (DIALECTA_EXEC_COMMAND or --*-exec)",
+		DefaultModel: "default",
+		Resolve: func(cfg Config) ([]string, Config, error) {
+			command, err := ResolveExecCommand(cfg)
+			cfg.Command = command
+			return nil, cfg, err
+		},
+		New: func(_ string, cfg Config) (Client, error) {
+			return NewExecClient(cfg), nil
+		},
+	},
+}
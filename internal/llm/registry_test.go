@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// registerTestProvider registers spec for the duration of the test
+func registerTestProvider(t *testing.T, spec ProviderSpec) {
+	t.Helper()
+	RegisterProvider(spec)
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		for name, p := range registry.names {
+			if p == spec.Name {
+				delete(registry.names, name)
+			}
+		}
+		for i, s := range registry.specs {
+			if s.Name == spec.Name {
+				registry.specs = append(registry.specs[:i], registry.specs[i+1:]...)
+				break
+			}
+		}
+	})
+}
+
+func TestRegisterProvider(t *testing.T) {
+	t.Setenv("ECHO_API_KEY", "secret")
+	var gotKey string
+	registerTestProvider(t, ProviderSpec{
+		Name:         "echo",
+		Aliases:      []string{"parrot"},
+		Description:  "Echo test provider",
+		EnvVars:      []string{"ECHO_API_KEY"},
+		DefaultModel: "echo-1",
+		New: func(apiKey string, cfg Config) (Client, error) {
+			gotKey = apiKey
+			return NewMockClient(cfg, MockOptions{}), nil
+		},
+	})
+
+	if p, err := ParseProvider("parrot"); err != nil || p != "echo" {
+		t.Errorf("ParseProvider(parrot) = %q, %v; want echo", p, err)
+	}
+	if names := ProviderNames(); !strings.HasSuffix(names, ", exec, echo") {
+		t.Errorf("ProviderNames() = %q, want echo listed after the built-ins", names)
+	}
+
+	client, err := NewClient(Config{Provider: "echo"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if _, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Errorf("Chat() error = %v", err)
+	}
+	if gotKey != "secret" {
+		t.Errorf("constructor got key %q, want it read from ECHO_API_KEY", gotKey)
+	}
+
+	t.Setenv("ECHO_API_KEY", "")
+	if _, err := NewClient(Config{Provider: "echo"}); err == nil || !strings.Contains(err.Error(), "ECHO_API_KEY") {
+		t.Errorf("NewClient() without key error = %v, want one naming ECHO_API_KEY", err)
+	}
+}
+
+func TestRegisterProvider_Conflicts(t *testing.T) {
+	newClient := func(string, Config) (Client, error) { return nil, nil }
+	tests := []struct {
+		name string
+		spec ProviderSpec
+	}{
+		{"no name", ProviderSpec{New: newClient}},
+		{"no constructor", ProviderSpec{Name: "fresh"}},
+		{"duplicate name", ProviderSpec{Name: ProviderGemini, New: newClient}},
+		{"duplicate alias", ProviderSpec{Name: "fresh", Aliases: []string{"claude"}, New: newClient}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newProviderRegistry(builtinProviders)
+			defer func() {
+				if recover() == nil {
+					t.Error("register() did not panic")
+				}
+				if _, ok := r.names["fresh"]; ok {
+					t.Error("a rejected spec was partly registered")
+				}
+			}()
+			r.register(tt.spec)
+		})
+	}
+}
+
+func TestProviderSpec_Credentials(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "google-key")
+	gemini, _ := LookupProvider("google")
+	if keys, _, err := gemini.Credentials(Config{}); err != nil || len(keys) != 1 || keys[0] != "google-key" {
+		t.Errorf("Credentials() = %q, %v; want the GOOGLE_API_KEY fallback", keys, err)
+	}
+
+	t.Setenv("GOOGLE_API_KEY", "")
+	if _, _, err := gemini.Credentials(Config{}); err == nil || err.Error() != "GEMINI_API_KEY or GOOGLE_API_KEY environment variable is required" {
+		t.Errorf("Credentials() error = %v", err)
+	}
+
+	t.Setenv("OPENAI_BASE_URL", "http://localhost:8000/v1")
+	t.Setenv("OPENAI_API_KEY", "")
+	openai, _ := LookupProvider("openai")
+	if _, cfg, err := openai.Credentials(Config{}); err != nil || cfg.BaseURL != "http://localhost:8000/v1" {
+		t.Errorf("Credentials() = %q, %v; want the base URL resolved without a key", cfg.BaseURL, err)
+	}
+
+	ollama, _ := LookupProvider("ollama")
+	if _, _, err := ollama.Credentials(Config{}); err != nil || !ollama.Free {
+		t.Errorf("ollama Credentials() error = %v, Free = %v; want no key and free", err, ollama.Free)
+	}
+}
@@ -0,0 +1,225 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how transient provider failures are retried
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first; <= 1 disables retries
+	BaseDelay   time.Duration // delay before the first retry, doubled on each attempt
+	MaxDelay    time.Duration // upper bound for a single delay, including Retry-After
+}
+
+// DefaultRetryPolicy is used by the default role configurations
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+}
+
+// Enabled reports whether the policy allows more than one attempt
+func (p RetryPolicy) Enabled() bool {
+	return p.MaxAttempts > 1
+}
+
+// backoff returns the jittered delay before retry number attempt (1-based).
+// A server-provided Retry-After takes precedence over the computed delay.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	if retryAfter > 0 {
+		return min(retryAfter, maxDelay)
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxDelay)
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter: keep half the delay, randomise the other half
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying:
+// rate limits, overloaded or failing servers, network errors, and connect or
+// idle timeouts.
+// Context cancellation and client errors (bad key, bad request) are final.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return timeoutErr.Kind != TimeoutTotal
+	}
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) {
+		// An exhausted quota will not recover by waiting
+		return rateErr.Code != "insufficient_quota"
+	}
+	var serverErr *ServerError
+	if errors.As(err, &serverErr) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+			529: // Anthropic "overloaded"
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryAfter extracts the server-requested delay from err, if any
+func retryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// retryClient wraps a Client and retries transient failures
+type retryClient struct {
+	inner  Client
+	policy RetryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+// WithRetry wraps client so that retryable errors are retried according to
+// policy. Streams are only retried while no chunk has reached onChunk, so
+// the caller never sees the same output twice.
+func WithRetry(client Client, policy RetryPolicy) Client {
+	if !policy.Enabled() {
+		return client
+	}
+	return &retryClient{inner: client, policy: policy, sleep: sleepContext}
+}
+
+// Usage includes tokens consumed by failed attempts
+func (c *retryClient) Usage() Usage {
+	return c.inner.Usage()
+}
+
+func (c *retryClient) Close() error {
+	return c.inner.Close()
+}
+
+func (c *retryClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	var result string
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.inner.Chat(ctx, messages)
+		return err
+	})
+	return result, err
+}
+
+func (c *retryClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	var result Message
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.inner.ChatTools(ctx, messages, tools)
+		return err
+	})
+	return result, err
+}
+
+// retry runs a non-streaming call until it succeeds or the policy gives up
+func (c *retryClient) retry(ctx context.Context, call func() error) error {
+	var lastErr error
+	for attempt := 1; attempt <= c.policy.MaxAttempts; attempt++ {
+		err := call()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !IsRetryable(err) || attempt == c.policy.MaxAttempts {
+			break
+		}
+		if err := c.sleep(ctx, c.policy.backoff(attempt, retryAfter(err))); err != nil {
+			return err
+		}
+	}
+	return c.exhausted(lastErr)
+}
+
+func (c *retryClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	var lastErr error
+	for attempt := 1; attempt <= c.policy.MaxAttempts; attempt++ {
+		sent := false
+		result, err := c.inner.ChatStream(ctx, messages, func(chunk string) {
+			sent = true
+			if onChunk != nil {
+				onChunk(chunk)
+			}
+		})
+		if err == nil {
+			return result, nil
+		}
+		if sent {
+			// Output already reached the caller; replaying would duplicate it
+			return result, err
+		}
+		lastErr = err
+		if !IsRetryable(err) || attempt == c.policy.MaxAttempts {
+			break
+		}
+		if err := c.sleep(ctx, c.policy.backoff(attempt, retryAfter(err))); err != nil {
+			return "", err
+		}
+	}
+	return "", c.exhausted(lastErr)
+}
+
+func (c *retryClient) exhausted(err error) error {
+	if IsRetryable(err) {
+		return fmt.Errorf("giving up after %d attempts: %w", c.policy.MaxAttempts, err)
+	}
+	return err
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,263 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// scriptedClient returns the scripted errors in order, then succeeds
+type scriptedClient struct {
+	errs   []error
+	chunks []string // chunks emitted before each scripted error
+	calls  int
+	closed bool
+}
+
+func (c *scriptedClient) Usage() Usage {
+	return Usage{PromptTokens: 10 * c.calls}
+}
+
+func (c *scriptedClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *scriptedClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	return c.ChatStream(ctx, messages, nil)
+}
+
+func (c *scriptedClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	content, err := c.ChatStream(ctx, messages, nil)
+	return Message{Role: "assistant", Content: content}, err
+}
+
+func (c *scriptedClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	c.calls++
+	if c.calls <= len(c.errs) {
+		if c.calls <= len(c.chunks) && c.chunks[c.calls-1] != "" && onChunk != nil {
+			onChunk(c.chunks[c.calls-1])
+		}
+		return "", c.errs[c.calls-1]
+	}
+	if onChunk != nil {
+		onChunk("ok")
+	}
+	return "ok", nil
+}
+
+func newTestRetryClient(inner Client, attempts int) (*retryClient, *[]time.Duration) {
+	var delays []time.Duration
+	return &retryClient{
+		inner:  inner,
+		policy: RetryPolicy{MaxAttempts: attempts, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second},
+		sleep: func(ctx context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return ctx.Err()
+		},
+	}, &delays
+}
+
+func TestRetryClient_Chat_RetriesTransientErrors(t *testing.T) {
+	inner := &scriptedClient{errs: []error{
+		&APIError{StatusCode: 429, RetryAfter: 2 * time.Second},
+		&APIError{StatusCode: 503},
+	}}
+	client, delays := newTestRetryClient(inner, 3)
+
+	got, err := client.Chat(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if got != "ok" || inner.calls != 3 {
+		t.Errorf("Chat() = %q after %d calls, want ok after 3", got, inner.calls)
+	}
+	if len(*delays) != 2 {
+		t.Fatalf("slept %d times, want 2", len(*delays))
+	}
+	if (*delays)[0] != time.Second {
+		t.Errorf("Retry-After delay = %v, want capped at MaxDelay %v", (*delays)[0], time.Second)
+	}
+}
+
+func TestRetryClient_Chat_NonRetryable(t *testing.T) {
+	inner := &scriptedClient{errs: []error{&APIError{StatusCode: 401, Body: "bad key"}}}
+	client, delays := newTestRetryClient(inner, 3)
+
+	_, err := client.Chat(context.Background(), nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 401 {
+		t.Fatalf("Chat() error = %v, want APIError 401", err)
+	}
+	if inner.calls != 1 || len(*delays) != 0 {
+		t.Errorf("calls = %d, sleeps = %d; want 1 call and no sleep", inner.calls, len(*delays))
+	}
+}
+
+func TestRetryClient_Chat_Exhausted(t *testing.T) {
+	inner := &scriptedClient{errs: []error{
+		&APIError{StatusCode: 500},
+		&APIError{StatusCode: 500},
+		&APIError{StatusCode: 500},
+	}}
+	client, _ := newTestRetryClient(inner, 2)
+
+	_, err := client.Chat(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "giving up after 2 attempts") {
+		t.Errorf("Chat() error = %v, want exhausted error", err)
+	}
+	if !IsRetryable(err) {
+		t.Error("exhausted error should still wrap the retryable cause")
+	}
+	if inner.calls != 2 {
+		t.Errorf("calls = %d, want 2", inner.calls)
+	}
+}
+
+func TestRetryClient_ChatStream_RetriesBeforeFirstChunk(t *testing.T) {
+	inner := &scriptedClient{errs: []error{&APIError{StatusCode: 429}}}
+	client, _ := newTestRetryClient(inner, 3)
+
+	var chunks []string
+	got, err := client.ChatStream(context.Background(), nil, func(s string) { chunks = append(chunks, s) })
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if got != "ok" || len(chunks) != 1 {
+		t.Errorf("ChatStream() = %q with chunks %v, want ok with one chunk", got, chunks)
+	}
+}
+
+func TestRetryClient_ChatStream_NoReplayAfterChunks(t *testing.T) {
+	inner := &scriptedClient{
+		errs:   []error{io.ErrUnexpectedEOF},
+		chunks: []string{"partial"},
+	}
+	client, delays := newTestRetryClient(inner, 3)
+
+	var chunks []string
+	_, err := client.ChatStream(context.Background(), nil, func(s string) { chunks = append(chunks, s) })
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ChatStream() error = %v, want unexpected EOF", err)
+	}
+	if inner.calls != 1 || len(*delays) != 0 {
+		t.Errorf("stream was retried after emitting chunks (calls = %d)", inner.calls)
+	}
+	if len(chunks) != 1 || chunks[0] != "partial" {
+		t.Errorf("chunks = %v, want [partial]", chunks)
+	}
+}
+
+func TestRetryClient_ContextCancelledDuringBackoff(t *testing.T) {
+	inner := &scriptedClient{errs: []error{&APIError{StatusCode: 503}}}
+	client := WithRetry(inner, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Chat(ctx, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Chat() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestWithRetry_Disabled(t *testing.T) {
+	inner := &scriptedClient{}
+	if got := WithRetry(inner, RetryPolicy{MaxAttempts: 1}); got != Client(inner) {
+		t.Error("WithRetry() should return the client unchanged when retries are disabled")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 6: time.Second} {
+		for i := 0; i < 20; i++ {
+			got := p.backoff(attempt, 0)
+			if got < want/2 || got > want {
+				t.Errorf("backoff(%d) = %v, want within [%v, %v]", attempt, got, want/2, want)
+			}
+		}
+	}
+
+	if got := p.backoff(1, 300*time.Millisecond); got != 300*time.Millisecond {
+		t.Errorf("backoff with Retry-After = %v, want 300ms", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-1", 0},
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second},
+		{now.Add(-10 * time.Second).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rate limit", &APIError{StatusCode: 429}, true},
+		{"overloaded", &APIError{StatusCode: 529}, true},
+		{"bad gateway wrapped", fmt.Errorf("affirmative: %w", &APIError{StatusCode: 502}), true},
+		{"unauthorized", &APIError{StatusCode: 401}, false},
+		{"bad request", &APIError{StatusCode: 400}, false},
+		{"context canceled", context.Canceled, false},
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"plain error", errors.New("decode response"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIClient_RetryAfterFromServer(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"slow down"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(mockOpenAIResponse("recovered")))
+	}))
+	defer server.Close()
+
+	client := WithRetry(NewOpenAIClient("k", Config{BaseURL: server.URL}), RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	got, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if got != "recovered" || atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("Chat() = %q after %d calls, want recovered after 2", got, calls)
+	}
+}
@@ -0,0 +1,215 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// ResponseFormatType selects how a response is constrained
+type ResponseFormatType string
+
+const (
+	ResponseText       ResponseFormatType = ""            // free text (default)
+	ResponseJSONObject ResponseFormatType = "json_object" // any valid JSON object
+	ResponseJSONSchema ResponseFormatType = "json_schema" // JSON matching Schema
+)
+
+// ResponseFormat asks the provider for JSON output.
+//
+// OpenAI-compatible endpoints get response_format, Gemini ResponseMIMEType
+// and ResponseSchema, and Ollama format. DeepSeek only accepts json_object,
+// so json_schema is downgraded there; Anthropic has no JSON mode and relies
+// on the prompt alone. Either way, callers should describe the expected
+// shape in the prompt and check the answer with Schema.Validate.
+type ResponseFormat struct {
+	Type   ResponseFormatType `json:"type"`
+	Name   string             `json:"name,omitempty"` // schema name, required by OpenAI for json_schema
+	Schema *Schema            `json:"schema,omitempty"`
+}
+
+// JSONSchema reports whether the format carries a schema to enforce
+func (f *ResponseFormat) JSONSchema() bool {
+	return f != nil && f.Type == ResponseJSONSchema && f.Schema != nil
+}
+
+// Schema is the subset of JSON Schema that every provider with structured
+// output understands: typed values, object properties, array items and
+// string enums
+type Schema struct {
+	Type        string             // object, array, string, number, integer, boolean
+	Description string             // shown to the model
+	Properties  map[string]*Schema // object members
+	Required    []string           // object members that must be present
+	Items       *Schema            // array elements
+	Enum        []string           // allowed string values
+}
+
+// MarshalJSON renders s as JSON Schema. Objects are closed
+// (additionalProperties: false), as OpenAI strict mode requires.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type jsonSchema struct {
+		Type                 string             `json:"type"`
+		Description          string             `json:"description,omitempty"`
+		Properties           map[string]*Schema `json:"properties,omitempty"`
+		Required             []string           `json:"required,omitempty"`
+		AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+		Items                *Schema            `json:"items,omitempty"`
+		Enum                 []string           `json:"enum,omitempty"`
+	}
+	out := jsonSchema{
+		Type:        s.Type,
+		Description: s.Description,
+		Properties:  s.Properties,
+		Required:    s.Required,
+		Items:       s.Items,
+		Enum:        s.Enum,
+	}
+	if s.Type == "object" {
+		closed := false
+		out.AdditionalProperties = &closed
+	}
+	return json.Marshal(out)
+}
+
+// genaiTypes maps JSON Schema type names to Gemini schema types
+var genaiTypes = map[string]genai.Type{
+	"object":  genai.TypeObject,
+	"array":   genai.TypeArray,
+	"string":  genai.TypeString,
+	"number":  genai.TypeNumber,
+	"integer": genai.TypeInteger,
+	"boolean": genai.TypeBoolean,
+}
+
+// toGenai converts s to the Gemini SDK schema
+func (s *Schema) toGenai() (*genai.Schema, error) {
+	if s == nil {
+		return nil, nil
+	}
+	t, ok := genaiTypes[s.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported schema type %q", s.Type)
+	}
+	out := &genai.Schema{Type: t, Description: s.Description, Required: s.Required, Enum: s.Enum}
+	if len(s.Enum) > 0 {
+		out.Format = "enum"
+	}
+	if s.Items != nil {
+		items, err := s.Items.toGenai()
+		if err != nil {
+			return nil, err
+		}
+		out.Items = items
+	}
+	if len(s.Properties) > 0 {
+		out.Properties = make(map[string]*genai.Schema, len(s.Properties))
+		for name, prop := range s.Properties {
+			p, err := prop.toGenai()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			out.Properties[name] = p
+		}
+	}
+	return out, nil
+}
+
+// Validate checks that data is a JSON document matching s. Providers
+// without schema enforcement (or with json_object only) may return
+// anything, so answers should be validated before use.
+func (s *Schema) Validate(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return s.validate("$", v)
+}
+
+func (s *Schema) validate(path string, v any) error {
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return schemaTypeError(path, s.Type, v)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required field %q", path, name)
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if len(s.Properties) > 0 {
+					return fmt.Errorf("%s: unexpected field %q", path, name)
+				}
+				continue
+			}
+			if err := prop.validate(path+"."+name, obj[name]); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return schemaTypeError(path, s.Type, v)
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return schemaTypeError(path, s.Type, v)
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			return fmt.Errorf("%s: %q is not one of %s", path, str, strings.Join(s.Enum, ", "))
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return schemaTypeError(path, s.Type, v)
+		}
+	case "integer":
+		if n, ok := v.(float64); !ok || n != float64(int64(n)) {
+			return schemaTypeError(path, s.Type, v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return schemaTypeError(path, s.Type, v)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", path, s.Type)
+	}
+	return nil
+}
+
+func schemaTypeError(path, want string, v any) error {
+	got := "null"
+	switch v.(type) {
+	case map[string]any:
+		got = "object"
+	case []any:
+		got = "array"
+	case string:
+		got = "string"
+	case float64:
+		got = "number"
+	case bool:
+		got = "boolean"
+	}
+	return fmt.Errorf("%s: expected %s, got %s", path, want, got)
+}
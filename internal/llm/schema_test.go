@@ -0,0 +1,73 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var testVerdictSchema = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{
+		"one_liner": {Type: "string"},
+		"arguments": {Type: "array", Items: &Schema{Type: "string"}},
+		"score":     {Type: "integer"},
+		"decision":  {Type: "string", Enum: []string{"approve", "reject"}},
+	},
+	Required: []string{"one_liner", "score"},
+}
+
+func TestSchema_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(&Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"tags": {Type: "array", Description: "labels", Items: &Schema{Type: "string"}}},
+		Required:   []string{"tags"},
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"type":"object","properties":{"tags":{"type":"array","description":"labels","items":{"type":"string"}}},"required":["tags"],"additionalProperties":false}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s\nwant %s", data, want)
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"valid", `{"one_liner":"ok","arguments":["a","b"],"score":80,"decision":"approve"}`, ""},
+		{"optional fields omitted", `{"one_liner":"ok","score":80}`, ""},
+		{"not JSON", `## 💡 One-Liner`, "invalid JSON"},
+		{"not an object", `["ok"]`, "$: expected object, got array"},
+		{"missing required", `{"one_liner":"ok"}`, `missing required field "score"`},
+		{"unexpected field", `{"one_liner":"ok","score":1,"extra":true}`, `unexpected field "extra"`},
+		{"wrong type", `{"one_liner":1,"score":1}`, "$.one_liner: expected string, got number"},
+		{"fractional integer", `{"one_liner":"ok","score":1.5}`, "$.score: expected integer"},
+		{"bad array item", `{"one_liner":"ok","score":1,"arguments":["a",null]}`, "$.arguments[1]: expected string, got null"},
+		{"enum", `{"one_liner":"ok","score":1,"decision":"maybe"}`, `"maybe" is not one of approve, reject`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testVerdictSchema.Validate([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchema_ToGenaiUnsupportedType(t *testing.T) {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{"when": {Type: "date"}}}
+	if _, err := s.toGenai(); err == nil || !strings.Contains(err.Error(), "when") {
+		t.Errorf("toGenai() error = %v, want an error naming the property", err)
+	}
+}
@@ -0,0 +1,126 @@
+package llm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrStreamTruncated reports a response stream that ended before the
+// provider's end-of-stream marker, e.g. because the connection dropped. It
+// matches io.ErrUnexpectedEOF, so WithRetry treats it as transient.
+var ErrStreamTruncated = fmt.Errorf("stream truncated: %w", io.ErrUnexpectedEOF)
+
+// sseEvent is one dispatched Server-Sent Event
+type sseEvent struct {
+	Type string // the event field; empty for unnamed ("message") events
+	Data string // data lines joined with "\n"
+	ID   string // last event id seen on the stream
+}
+
+// sseDecoder reads a text/event-stream as specified by the WHATWG HTML
+// standard: LF, CRLF or CR line endings, comments, named events and
+// multi-line data. Lines have no length limit. Unlike the standard, a final
+// event missing its terminating blank line is still delivered, since some
+// servers close the stream right after the last data line.
+type sseDecoder struct {
+	r       *bufio.Reader
+	pending []string // lines already split off the last read
+	lastID  string
+	started bool // the BOM check has run
+}
+
+func newSSEDecoder(r io.Reader) *sseDecoder {
+	return &sseDecoder{r: bufio.NewReader(r)}
+}
+
+// Next returns the next event, or io.EOF once the stream is exhausted.
+// Read errors are returned as they are.
+func (d *sseDecoder) Next() (sseEvent, error) {
+	var event sseEvent
+	var data strings.Builder
+	hasData := false
+
+	for {
+		line, err := d.readLine()
+		if err == io.EOF {
+			if hasData {
+				return d.dispatch(event, data.String()), nil
+			}
+			return sseEvent{}, io.EOF
+		}
+		if err != nil {
+			return sseEvent{}, err
+		}
+
+		if line == "" {
+			if hasData {
+				return d.dispatch(event, data.String()), nil
+			}
+			// Blank line without data: reset and keep reading
+			event = sseEvent{}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment, e.g. a keep-alive
+		}
+
+		field, value, found := strings.Cut(line, ":")
+		if found {
+			value = strings.TrimPrefix(value, " ")
+		}
+		switch field {
+		case "event":
+			event.Type = value
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				d.lastID = value
+			}
+		}
+		// retry and unknown fields are ignored
+	}
+}
+
+func (d *sseDecoder) dispatch(event sseEvent, data string) sseEvent {
+	event.Data = data
+	event.ID = d.lastID
+	return event
+}
+
+// readLine returns the next line without its terminator, or io.EOF when
+// no characters are left
+func (d *sseDecoder) readLine() (string, error) {
+	if len(d.pending) > 0 {
+		line := d.pending[0]
+		d.pending = d.pending[1:]
+		return line, nil
+	}
+
+	chunk, err := d.r.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if chunk == "" {
+		return "", io.EOF
+	}
+	if !d.started {
+		d.started = true
+		chunk = strings.TrimPrefix(chunk, "\uFEFF")
+	}
+
+	// ReadString stops at LF, so a CR right before it is part of a CRLF
+	// pair; any other CR ends a line of its own
+	chunk = strings.TrimSuffix(chunk, "\n")
+	chunk = strings.TrimSuffix(chunk, "\r")
+	lines := strings.Split(chunk, "\r")
+	d.pending = lines[1:]
+	return lines[0], nil
+}
@@ -0,0 +1,168 @@
+package llm
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// decodeAll reads every event from r
+func decodeAll(r io.Reader) ([]sseEvent, error) {
+	decoder := newSSEDecoder(r)
+	var events []sseEvent
+	for {
+		event, err := decoder.Next()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+}
+
+func TestSSEDecoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   []sseEvent
+	}{
+		{
+			name:   "LF",
+			stream: "data: a\n\ndata: b\n\n",
+			want:   []sseEvent{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:   "CRLF",
+			stream: "data: a\r\n\r\ndata: b\r\n\r\n",
+			want:   []sseEvent{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:   "CR",
+			stream: "data: a\r\rdata: b\r\r",
+			want:   []sseEvent{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: {\"a\":\ndata: 1}\n\n",
+			want:   []sseEvent{{Data: "{\"a\":\n1}"}},
+		},
+		{
+			name:   "named event and id",
+			stream: "event: error\nid: 7\ndata: x\n\ndata: y\n\n",
+			want:   []sseEvent{{Type: "error", Data: "x", ID: "7"}, {Data: "y", ID: "7"}},
+		},
+		{
+			name:   "comments and unknown fields",
+			stream: ": keep-alive\nretry: 1000\nfoo: bar\ndata: a\n\n",
+			want:   []sseEvent{{Data: "a"}},
+		},
+		{
+			name:   "value without space or colon",
+			stream: "data:a\ndata\n\n",
+			want:   []sseEvent{{Data: "a\n"}},
+		},
+		{
+			name:   "event without data is not dispatched",
+			stream: "event: ping\n\ndata: a\n\n",
+			want:   []sseEvent{{Data: "a"}},
+		},
+		{
+			name:   "BOM",
+			stream: "\uFEFFdata: a\n\n",
+			want:   []sseEvent{{Data: "a"}},
+		},
+		{
+			name:   "final event without blank line",
+			stream: "data: a\n\ndata: b",
+			want:   []sseEvent{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:   "empty",
+			stream: "",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeAll(strings.NewReader(tt.stream))
+			if err != nil {
+				t.Fatalf("Next() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSEDecoder_LongLine(t *testing.T) {
+	long := strings.Repeat("x", 200*1024) // beyond bufio.Scanner's 64KB limit
+	got, err := decodeAll(strings.NewReader("data: " + long + "\n\n"))
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Data != long {
+		t.Errorf("got %d events, want the long line back", len(got))
+	}
+}
+
+func TestSSEDecoder_ReadError(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("data: a\n\n"), iotest.ErrReader(errors.New("connection reset")))
+	got, err := decodeAll(r)
+	if err == nil || err.Error() != "connection reset" {
+		t.Errorf("Next() error = %v, want connection reset", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("got %d events before the error, want 1", len(got))
+	}
+}
+
+func FuzzSSEDecoder(f *testing.F) {
+	f.Add("data: a\n\ndata: b\n\n")
+	f.Add("event: error\r\ndata: {\"error\":{}}\r\n\r\n")
+	f.Add("data: a\rdata: b\r\r: c\n")
+	f.Add("\uFEFFid: 1\ndata\n\ndata:")
+	f.Fuzz(func(t *testing.T, stream string) {
+		want, err := decodeAll(strings.NewReader(stream))
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		for _, event := range want {
+			if strings.ContainsAny(event.Type, "\r\n") || strings.ContainsAny(event.ID, "\r\n") {
+				t.Errorf("event %+v contains a line break", event)
+			}
+		}
+
+		// Events must not depend on how the stream is split into reads
+		got, err := decodeAll(iotest.OneByteReader(strings.NewReader(stream)))
+		if err != nil {
+			t.Fatalf("Next() error with one-byte reads = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("one-byte reads = %+v, want %+v", got, want)
+		}
+	})
+}
+
+func FuzzOpenAIStream(f *testing.F) {
+	f.Add("data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n")
+	f.Add("data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+	f.Add("data: {\"error\":{\"type\":\"server_error\",\"message\":\"boom\"}}\n\n")
+	f.Add("event: error\ndata: oops\n\n")
+	f.Fuzz(func(t *testing.T, stream string) {
+		client := &OpenAIClient{}
+		var chunks strings.Builder
+		content, _, err := client.handleStream(strings.NewReader(stream), func(s string) { chunks.WriteString(s) }, nil)
+		if content != chunks.String() {
+			t.Errorf("content %q differs from the streamed chunks %q", content, chunks.String())
+		}
+		if err == nil && strings.TrimSpace(stream) == "" {
+			t.Error("empty stream decoded without error, want ErrStreamTruncated")
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Timeouts bound how long a client waits on its provider. Zero fields
+// disable the corresponding limit.
+type Timeouts struct {
+	Connect time.Duration // TCP connect and TLS handshake
+	Total   time.Duration // one request from start to last byte, per attempt
+	Idle    time.Duration // silence between stream chunks, and before the first
+}
+
+// DefaultTimeouts is used by the default role configurations. Idle allows
+// for thinking models that stay silent before their first token; Total
+// allows for a long verdict.
+var DefaultTimeouts = Timeouts{
+	Connect: 10 * time.Second,
+	Total:   10 * time.Minute,
+	Idle:    2 * time.Minute,
+}
+
+// TimeoutKind names the limit a TimeoutError ran into
+type TimeoutKind string
+
+const (
+	TimeoutConnect TimeoutKind = "connect"
+	TimeoutTotal   TimeoutKind = "total"
+	TimeoutIdle    TimeoutKind = "idle"
+)
+
+// TimeoutError is returned when a request exceeds one of its Timeouts.
+// Connect and idle timeouts are retryable; a request that ran out of its
+// total time is not retried.
+type TimeoutError struct {
+	Provider Provider
+	Model    string
+	Kind     TimeoutKind
+	Timeout  time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	var what string
+	switch e.Kind {
+	case TimeoutConnect:
+		what = fmt.Sprintf("could not connect within %s", e.Timeout)
+	case TimeoutIdle:
+		what = fmt.Sprintf("stream stalled, no output for %s", e.Timeout)
+	default:
+		what = fmt.Sprintf("no complete answer within %s", e.Timeout)
+	}
+
+	switch {
+	case e.Provider != "" && e.Model != "":
+		return fmt.Sprintf("%s (%s): %s (%s timeout)", e.Provider, e.Model, what, e.Kind)
+	case e.Provider != "":
+		return fmt.Sprintf("%s: %s (%s timeout)", e.Provider, what, e.Kind)
+	}
+	return fmt.Sprintf("%s (%s timeout)", what, e.Kind)
+}
+
+// timeoutDialer returns a DialContext that fails with a TimeoutError when
+// a connection takes longer than timeout
+func (cfg Config) timeoutDialer(timeout time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, addr)
+		var netErr net.Error
+		if err != nil && ctx.Err() == nil && errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, &TimeoutError{Provider: cfg.Provider, Model: cfg.Model, Kind: TimeoutConnect, Timeout: timeout}
+		}
+		return conn, err
+	}
+}
+
+// connectTransport returns a copy of the default transport that applies
+// cfg.Timeouts.Connect to dialing and the TLS handshake
+func (cfg Config) connectTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = cfg.timeoutDialer(cfg.Timeouts.Connect)
+	transport.TLSHandshakeTimeout = cfg.Timeouts.Connect
+	return transport
+}
+
+// timeoutClient wraps a Client and enforces the total and idle timeouts of
+// every call
+type timeoutClient struct {
+	inner    Client
+	provider Provider
+	model    string
+	timeouts Timeouts
+}
+
+// WithTimeouts wraps client so that each call fails with a *TimeoutError
+// once it exceeds timeouts.Total, or once a stream has sent nothing for
+// timeouts.Idle. Reasoning deltas count as stream activity. The connect
+// timeout is applied by the HTTP transport instead (see Config.httpClient).
+func WithTimeouts(client Client, provider Provider, model string, timeouts Timeouts) Client {
+	if timeouts.Total <= 0 && timeouts.Idle <= 0 {
+		return client
+	}
+	return &timeoutClient{inner: client, provider: provider, model: model, timeouts: timeouts}
+}
+
+func (c *timeoutClient) Usage() Usage {
+	return c.inner.Usage()
+}
+
+func (c *timeoutClient) Close() error {
+	return c.inner.Close()
+}
+
+func (c *timeoutClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	ctx, w := c.watch(ctx, false)
+	result, err := c.inner.Chat(ctx, messages)
+	return result, w.done(err)
+}
+
+func (c *timeoutClient) ChatTools(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	ctx, w := c.watch(ctx, false)
+	result, err := c.inner.ChatTools(ctx, messages, tools)
+	return result, w.done(err)
+}
+
+func (c *timeoutClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string)) (string, error) {
+	ctx, w := c.watch(ctx, true)
+	if w.idle != nil {
+		onReasoning := reasoningFunc(ctx)
+		ctx = WithReasoning(ctx, func(text string) {
+			w.touch()
+			if onReasoning != nil {
+				onReasoning(text)
+			}
+		})
+	}
+	result, err := c.inner.ChatStream(ctx, messages, func(chunk string) {
+		w.touch()
+		if onChunk != nil {
+			onChunk(chunk)
+		}
+	})
+	return result, w.done(err)
+}
+
+// callWatch cancels one call when it runs out of time
+type callWatch struct {
+	ctx         context.Context
+	cancel      context.CancelCauseFunc
+	total, idle *time.Timer
+	idleTimeout time.Duration
+}
+
+// watch derives the context of one call; idle enables the idle timer
+func (c *timeoutClient) watch(parent context.Context, idle bool) (context.Context, *callWatch) {
+	ctx, cancel := context.WithCancelCause(parent)
+	w := &callWatch{ctx: ctx, cancel: cancel}
+	expire := func(kind TimeoutKind, d time.Duration) func() {
+		return func() {
+			cancel(&TimeoutError{Provider: c.provider, Model: c.model, Kind: kind, Timeout: d})
+		}
+	}
+	if d := c.timeouts.Total; d > 0 {
+		w.total = time.AfterFunc(d, expire(TimeoutTotal, d))
+	}
+	if d := c.timeouts.Idle; idle && d > 0 {
+		w.idle = time.AfterFunc(d, expire(TimeoutIdle, d))
+		w.idleTimeout = d
+	}
+	return ctx, w
+}
+
+// touch restarts the idle timer after stream activity
+func (w *callWatch) touch() {
+	if w.idle != nil {
+		w.idle.Reset(w.idleTimeout)
+	}
+}
+
+// done stops the timers and, when the call failed because a timer fired,
+// replaces its error with the TimeoutError
+func (w *callWatch) done(err error) error {
+	if w.total != nil {
+		w.total.Stop()
+	}
+	if w.idle != nil {
+		w.idle.Stop()
+	}
+	cause := context.Cause(w.ctx)
+	w.cancel(nil)
+
+	var timeoutErr *TimeoutError
+	if err != nil && errors.As(cause, &timeoutErr) {
+		return timeoutErr
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stallServer streams the given OpenAI-style deltas, one every interval,
+// and then hangs until the client gives up
+func stallServer(t *testing.T, interval time.Duration, deltas ...string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range deltas {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":%s}]}\n\n", delta)
+			w.(http.Flusher).Flush()
+			select {
+			case <-time.After(interval):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithTimeouts_IdleStream(t *testing.T) {
+	server := stallServer(t, 0, `{"content":"Hel"}`, `{"content":"lo"}`)
+	client, err := NewClient(Config{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-4o",
+		BaseURL:  server.URL,
+		Retry:    DefaultRetryPolicy,
+		Timeouts: Timeouts{Idle: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var chunks []string
+	start := time.Now()
+	got, err := client.ChatStream(context.Background(), []Message{{Role: "user", Content: "hi"}}, func(s string) { chunks = append(chunks, s) })
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Kind != TimeoutIdle {
+		t.Fatalf("ChatStream() error = %v, want an idle TimeoutError", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "openai (gpt-4o)") || !strings.Contains(msg, "idle timeout") {
+		t.Errorf("error = %q, want it to name the provider and the timeout", msg)
+	}
+	if got != "Hello" || len(chunks) != 2 {
+		t.Errorf("ChatStream() = %q after %d chunks, want the partial output", got, len(chunks))
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("stall detected after %s, want about 50ms and no retry", elapsed)
+	}
+}
+
+func TestWithTimeouts_ActivityResetsIdle(t *testing.T) {
+	// Each gap is shorter than the idle timeout, the whole stream is not;
+	// the reasoning deltas keep a silent answer alive
+	deltas := []string{`{"reasoning_content":"a"}`, `{"reasoning_content":"b"}`, `{"reasoning_content":"c"}`, `{"content":"done"}`}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, delta := range deltas {
+			time.Sleep(30 * time.Millisecond)
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":%s}]}\n\n", delta)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := WithTimeouts(NewOpenAIClient("", Config{BaseURL: server.URL}), ProviderOpenAI, "", Timeouts{Idle: 80 * time.Millisecond})
+	var reasoning string
+	ctx := WithReasoning(context.Background(), func(s string) { reasoning += s })
+	got, err := client.ChatStream(ctx, []Message{{Role: "user", Content: "hi"}}, nil)
+	if err != nil || got != "done" {
+		t.Fatalf("ChatStream() = %q, %v; want the full answer", got, err)
+	}
+	if reasoning != "abc" {
+		t.Errorf("reasoning = %q, want it passed through", reasoning)
+	}
+}
+
+func TestWithTimeouts_Total(t *testing.T) {
+	client := WithTimeouts(NewMockClient(Config{}, MockOptions{Latency: time.Second}), ProviderMock, "mock", Timeouts{Total: 20 * time.Millisecond})
+	calls := map[string]func() error{
+		"Chat": func() error {
+			_, err := client.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}})
+			return err
+		},
+		"ChatTools": func() error {
+			_, err := client.ChatTools(context.Background(), []Message{{Role: "user", Content: "hi"}}, nil)
+			return err
+		},
+		"ChatStream": func() error {
+			_, err := client.ChatStream(context.Background(), []Message{{Role: "user", Content: "hi"}}, nil)
+			return err
+		},
+	}
+	for name, call := range calls {
+		err := call()
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) || timeoutErr.Kind != TimeoutTotal || timeoutErr.Timeout != 20*time.Millisecond {
+			t.Errorf("%s() error = %v, want a total TimeoutError", name, err)
+		}
+		if IsRetryable(err) {
+			t.Errorf("%s() error is retryable, want total timeouts to be final", name)
+		}
+	}
+}
+
+func TestWithTimeouts_CallerCancel(t *testing.T) {
+	client := WithTimeouts(NewMockClient(Config{}, MockOptions{Latency: time.Second}), ProviderMock, "mock", Timeouts{Total: time.Minute, Idle: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := client.ChatStream(ctx, []Message{{Role: "user", Content: "hi"}}, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("ChatStream() error = %v, want the caller's context.Canceled", err)
+	}
+}
+
+func TestWithTimeouts_Disabled(t *testing.T) {
+	inner := NewMockClient(Config{}, MockOptions{})
+	if client := WithTimeouts(inner, ProviderMock, "", Timeouts{Connect: time.Second}); client != Client(inner) {
+		t.Errorf("WithTimeouts() = %T, want the client unwrapped without total or idle timeouts", client)
+	}
+}
+
+func TestTimeoutDialer(t *testing.T) {
+	dial := Config{Provider: ProviderOllama, Model: "llama3.1"}.timeoutDialer(time.Nanosecond)
+	_, err := dial(context.Background(), "tcp", "192.0.2.1:80")
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Kind != TimeoutConnect || timeoutErr.Provider != ProviderOllama {
+		t.Fatalf("dial error = %v, want a connect TimeoutError", err)
+	}
+	if !IsRetryable(err) {
+		t.Error("connect timeouts should be retryable")
+	}
+}
@@ -0,0 +1,48 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tool describes a function the model may ask the caller to run
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  *Schema // an object schema of the arguments; nil takes none
+}
+
+// ToolCall is a model's request to run a tool. Results are sent back as a
+// message with Role "tool" and ToolCallID set to ID.
+type ToolCall struct {
+	ID        string // provider call id; generated for providers without one
+	Name      string
+	Arguments string // JSON object
+}
+
+// ErrToolsUnsupported is returned by ChatTools on providers without
+// function calling
+var ErrToolsUnsupported = errors.New("tool calling is not supported")
+
+// ToolResultMessage returns the message answering call with content
+func ToolResultMessage(call ToolCall, content string) Message {
+	return Message{Role: "tool", Content: content, ToolCallID: call.ID}
+}
+
+// toolCallName finds the tool named by the call id in the most recent
+// assistant message of history that requested it
+func toolCallName(history []Message, id string) string {
+	for i := len(history) - 1; i >= 0; i-- {
+		for _, call := range history[i].ToolCalls {
+			if call.ID == id {
+				return call.Name
+			}
+		}
+	}
+	return ""
+}
+
+// toolsUnsupported builds the ChatTools error of a provider without tools
+func toolsUnsupported(provider Provider) error {
+	return fmt.Errorf("%s: %w", provider, ErrToolsUnsupported)
+}
@@ -0,0 +1,42 @@
+package llm
+
+import "sync"
+
+// Usage reports token consumption as billed by the provider
+type Usage struct {
+	PromptTokens     int // input tokens, including cached ones
+	CompletionTokens int // output tokens, including any hidden reasoning
+	CachedTokens     int // subset of PromptTokens served from the provider's cache
+}
+
+// TotalTokens returns prompt plus completion tokens
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// Add returns the sum of u and other
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		CachedTokens:     u.CachedTokens + other.CachedTokens,
+	}
+}
+
+// usageCounter accumulates usage across calls; safe for concurrent use
+type usageCounter struct {
+	mu    sync.Mutex
+	total Usage
+}
+
+func (c *usageCounter) add(u Usage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total = c.total.Add(u)
+}
+
+func (c *usageCounter) get() Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
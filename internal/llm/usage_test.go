@@ -0,0 +1,17 @@
+package llm
+
+import "testing"
+
+func TestUsage_Add(t *testing.T) {
+	a := Usage{PromptTokens: 100, CompletionTokens: 20, CachedTokens: 50}
+	b := Usage{PromptTokens: 10, CompletionTokens: 5}
+
+	got := a.Add(b)
+	want := Usage{PromptTokens: 110, CompletionTokens: 25, CachedTokens: 50}
+	if got != want {
+		t.Errorf("Add() = %+v, want %+v", got, want)
+	}
+	if got.TotalTokens() != 135 {
+		t.Errorf("TotalTokens() = %d, want 135", got.TotalTokens())
+	}
+}
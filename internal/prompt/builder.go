@@ -2,6 +2,7 @@ package prompt
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hrygo/dialecta/internal/llm"
 )
@@ -40,3 +41,118 @@ func BuildAdjudicatorMessages(material, proArgument, conArgument string) []llm.M
 		{Role: "user", Content: userContent},
 	}
 }
+
+// Statement is one speech in a debate transcript, e.g. a rebuttal
+type Statement struct {
+	Speaker string // who spoke in which round, e.g. "第 2 轮 · 正方反驳"
+	Content string
+}
+
+// BuildPersonaMessages builds the messages for a panel persona named name;
+// persona is its own prompt
+func BuildPersonaMessages(name, persona, material string) []llm.Message {
+	return []llm.Message{
+		{Role: "system", Content: fmt.Sprintf(PersonaSystemPrompt, name, persona)},
+		{Role: "user", Content: fmt.Sprintf("**用户提供的材料如下：**\n\n%s", material)},
+	}
+}
+
+// BuildPanelAdjudicatorMessages builds the messages for the Adjudicator of a
+// panel debate; opinions holds every persona's statements in speaking order
+func BuildPanelAdjudicatorMessages(material string, opinions []Statement) []llm.Message {
+	return []llm.Message{
+		{Role: "system", Content: PanelAdjudicatorSystemPrompt},
+		{Role: "user", Content: transcript(material, PanelTranscriptNote, opinions)},
+	}
+}
+
+// BuildChiefJudgeMessages builds the messages for the chief judge of a
+// judge panel; verdicts holds each judge's verdict and consensus summarises
+// their aggregate score and decision
+func BuildChiefJudgeMessages(material string, verdicts []Statement, consensus string) []llm.Message {
+	return []llm.Message{
+		{Role: "system", Content: ChiefJudgeSystemPrompt},
+		{Role: "user", Content: transcript(material, fmt.Sprintf(ChiefJudgeNote, consensus), verdicts)},
+	}
+}
+
+// BuildTranscriptAdjudicatorMessages builds the messages for the Adjudicator
+// of a multi-round debate; transcript holds every statement in speaking order
+func BuildTranscriptAdjudicatorMessages(material string, statements []Statement) []llm.Message {
+	return []llm.Message{
+		{Role: "system", Content: AdjudicatorSystemPrompt},
+		{Role: "user", Content: transcript(material, TranscriptNote, statements)},
+	}
+}
+
+// transcript lays out the material, a note and the statements for the
+// Adjudicator
+func transcript(material, note string, statements []Statement) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**输入数据：**\n\n**【原始材料】**：\n%s\n\n%s", material, note)
+	for _, s := range statements {
+		fmt.Fprintf(&b, "\n\n**【%s】**：\n%s", s.Speaker, s.Content)
+	}
+	return b.String()
+}
+
+// BuildRebuttalMessages continues a debater's conversation for the next
+// round: its own previous answer, then the opponent's latest argument to
+// rebut. messages is the conversation that produced ownArgument, starting
+// with the opening statement's messages.
+func BuildRebuttalMessages(messages []llm.Message, ownArgument, opponentArgument string) []llm.Message {
+	out := make([]llm.Message, len(messages), len(messages)+2)
+	copy(out, messages)
+	return append(out,
+		llm.Message{Role: "assistant", Content: ownArgument},
+		llm.Message{Role: "user", Content: fmt.Sprintf(RebuttalPrompt, opponentArgument)},
+	)
+}
+
+// BuildPanelRebuttalMessages continues a panel persona's conversation for
+// the next round like BuildRebuttalMessages, with the other personas' latest
+// statements to answer
+func BuildPanelRebuttalMessages(messages []llm.Message, ownArgument string, others []Statement) []llm.Message {
+	var b strings.Builder
+	for i, s := range others {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "**【%s】**：\n%s", s.Speaker, s.Content)
+	}
+	out := make([]llm.Message, len(messages), len(messages)+2)
+	copy(out, messages)
+	return append(out,
+		llm.Message{Role: "assistant", Content: ownArgument},
+		llm.Message{Role: "user", Content: fmt.Sprintf(PanelRebuttalPrompt, b.String())},
+	)
+}
+
+// WithJSONOutput returns a copy of messages whose system prompt asks for
+// format (ArgumentJSONFormat or VerdictJSONFormat) instead of Markdown
+func WithJSONOutput(messages []llm.Message, format string) []llm.Message {
+	out := make([]llm.Message, len(messages))
+	copy(out, messages)
+	for i, m := range out {
+		if m.Role != "system" {
+			continue
+		}
+		if idx := strings.Index(m.Content, "### Output Format"); idx >= 0 {
+			out[i].Content = m.Content[:idx] + format
+		} else {
+			out[i].Content = m.Content + "\n\n" + format
+		}
+	}
+	return out
+}
+
+// BuildContinuationMessages returns the conversation that asks for the rest
+// of partial, an answer to messages that was cut off at the token limit
+func BuildContinuationMessages(messages []llm.Message, partial string) []llm.Message {
+	out := make([]llm.Message, len(messages), len(messages)+2)
+	copy(out, messages)
+	return append(out,
+		llm.Message{Role: "assistant", Content: partial},
+		llm.Message{Role: "user", Content: ContinuePrompt},
+	)
+}
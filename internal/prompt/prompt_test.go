@@ -3,6 +3,8 @@ package prompt
 import (
 	"strings"
 	"testing"
+
+	"github.com/hrygo/dialecta/internal/llm"
 )
 
 func TestBuildAffirmativeMessages(t *testing.T) {
@@ -192,3 +194,170 @@ func TestBuildMessages_LongContent(t *testing.T) {
 		t.Error("Long content not preserved in messages")
 	}
 }
+
+func TestWithJSONOutput(t *testing.T) {
+	original := BuildAdjudicatorMessages("material", "pro", "con")
+	messages := WithJSONOutput(original, VerdictJSONFormat)
+
+	if original[0].Content != AdjudicatorSystemPrompt {
+		t.Error("WithJSONOutput() should not modify the input messages")
+	}
+	system := messages[0].Content
+	if !strings.Contains(system, "首席裁决官") {
+		t.Error("system prompt lost its role description")
+	}
+	if strings.Contains(system, "综合裁决报告") || strings.Contains(system, "## 💡 One-Liner") {
+		t.Error("Markdown output format should be replaced")
+	}
+	if !strings.HasSuffix(system, VerdictJSONFormat) {
+		t.Error("system prompt should end with the JSON format")
+	}
+	if messages[1].Content != original[1].Content {
+		t.Error("user message should be unchanged")
+	}
+
+	// Prompts without an Output Format section get the format appended
+	custom := WithJSONOutput([]llm.Message{{Role: "system", Content: "Be brief."}}, ArgumentJSONFormat)
+	if want := "Be brief.\n\n" + ArgumentJSONFormat; custom[0].Content != want {
+		t.Errorf("Content = %q, want %q", custom[0].Content, want)
+	}
+}
+
+func TestBuildContinuationMessages(t *testing.T) {
+	original := BuildAffirmativeMessages("material")
+	messages := BuildContinuationMessages(original, "## 💡 One-Liner\n半句")
+
+	if len(original) != 2 {
+		t.Fatalf("BuildContinuationMessages() should not modify the input, got %d messages", len(original))
+	}
+	if len(messages) != 4 {
+		t.Fatalf("got %d messages, want 4", len(messages))
+	}
+	if messages[2].Role != "assistant" || messages[2].Content != "## 💡 One-Liner\n半句" {
+		t.Errorf("messages[2] = %+v, want the partial answer from the assistant", messages[2])
+	}
+	if messages[3].Role != "user" || messages[3].Content != ContinuePrompt {
+		t.Errorf("messages[3] = %+v, want the continue prompt", messages[3])
+	}
+}
+
+func TestBuildRebuttalMessages(t *testing.T) {
+	original := BuildNegativeMessages("material")
+	messages := BuildRebuttalMessages(original, "反方立论", "正方立论")
+
+	if len(original) != 2 {
+		t.Fatalf("BuildRebuttalMessages() should not modify the input, got %d messages", len(original))
+	}
+	if len(messages) != 4 {
+		t.Fatalf("got %d messages, want 4", len(messages))
+	}
+	if messages[2].Role != "assistant" || messages[2].Content != "反方立论" {
+		t.Errorf("messages[2] = %+v, want the debater's own argument", messages[2])
+	}
+	if messages[3].Role != "user" || !strings.Contains(messages[3].Content, "正方立论") || !strings.Contains(messages[3].Content, "反驳") {
+		t.Errorf("messages[3] = %+v, want the opponent's argument to rebut", messages[3])
+	}
+}
+
+func TestBuildTranscriptAdjudicatorMessages(t *testing.T) {
+	messages := BuildTranscriptAdjudicatorMessages("原始材料", []Statement{
+		{Speaker: "第 1 轮 · 正方立论", Content: "正方论点"},
+		{Speaker: "第 1 轮 · 反方立论", Content: "反方论点"},
+		{Speaker: "第 2 轮 · 正方反驳", Content: "正方反驳"},
+	})
+
+	if len(messages) != 2 || messages[0].Content != AdjudicatorSystemPrompt {
+		t.Fatalf("got %+v, want the adjudicator system prompt and one user message", messages)
+	}
+	content := messages[1].Content
+	last := 0
+	for _, want := range []string{"原始材料", TranscriptNote, "**【第 1 轮 · 正方立论】**：\n正方论点", "反方论点", "**【第 2 轮 · 正方反驳】**：\n正方反驳"} {
+		i := strings.Index(content, want)
+		if i < last {
+			t.Errorf("transcript is missing %q or has it out of order:\n%s", want, content)
+		}
+		last = i
+	}
+}
+
+func TestBuildPersonaMessages(t *testing.T) {
+	messages := BuildPersonaMessages("CFO", "你关注成本与回报。", "材料")
+
+	if len(messages) != 2 {
+		t.Fatalf("got %d messages, want 2", len(messages))
+	}
+	system := messages[0].Content
+	if !strings.Contains(system, "【CFO】。你关注成本与回报。") || !strings.Contains(system, "作为【CFO】") {
+		t.Errorf("system prompt does not introduce the persona:\n%s", system)
+	}
+	if !strings.Contains(system, "## 💡 One-Liner") || !strings.Contains(system, "## 📝 Full Argument") {
+		t.Error("system prompt should ask for the usual One-Liner and Full Argument sections")
+	}
+	if !strings.Contains(messages[1].Content, "材料") {
+		t.Errorf("user message = %q, want the material", messages[1].Content)
+	}
+}
+
+func TestBuildPanelAdjudicatorMessages(t *testing.T) {
+	messages := BuildPanelAdjudicatorMessages("原始材料", []Statement{
+		{Speaker: "CFO", Content: "成本过高"},
+		{Speaker: "Security", Content: "风险可控"},
+	})
+
+	if len(messages) != 2 || messages[0].Content != PanelAdjudicatorSystemPrompt {
+		t.Fatalf("got %+v, want the panel adjudicator system prompt and one user message", messages)
+	}
+	content := messages[1].Content
+	last := 0
+	for _, want := range []string{"原始材料", PanelTranscriptNote, "**【CFO】**：\n成本过高", "**【Security】**：\n风险可控"} {
+		i := strings.Index(content, want)
+		if i < last {
+			t.Errorf("panel input is missing %q or has it out of order:\n%s", want, content)
+		}
+		last = i
+	}
+	if !strings.Contains(PanelAdjudicatorSystemPrompt, "【评分: XX/100】") {
+		t.Error("panel verdict One-Liner should carry the score like the pair verdict")
+	}
+}
+
+func TestBuildPanelRebuttalMessages(t *testing.T) {
+	original := BuildPersonaMessages("CFO", "", "material")
+	messages := BuildPanelRebuttalMessages(original, "CFO 意见", []Statement{
+		{Speaker: "Security", Content: "安全意见"},
+		{Speaker: "Ops", Content: "运维意见"},
+	})
+
+	if len(original) != 2 || len(messages) != 4 {
+		t.Fatalf("got %d → %d messages, want 2 → 4", len(original), len(messages))
+	}
+	if messages[2].Role != "assistant" || messages[2].Content != "CFO 意见" {
+		t.Errorf("messages[2] = %+v, want the persona's own opinion", messages[2])
+	}
+	if answer := messages[3].Content; !strings.Contains(answer, "**【Security】**：\n安全意见\n\n**【Ops】**：\n运维意见") {
+		t.Errorf("messages[3] = %q, want every other persona's opinion", answer)
+	}
+}
+
+func TestBuildChiefJudgeMessages(t *testing.T) {
+	messages := BuildChiefJudgeMessages("原始材料", []Statement{
+		{Speaker: "裁判 1 · deepseek/deepseek-chat", Content: "【评分: 60/100】"},
+		{Speaker: "裁判 2 · gemini/gemini-2.5-pro", Content: "【评分: 80/100】"},
+	}, "均值评分 70.0")
+
+	if len(messages) != 2 || messages[0].Content != ChiefJudgeSystemPrompt {
+		t.Fatalf("got %+v, want the chief judge system prompt and one user message", messages)
+	}
+	content := messages[1].Content
+	last := 0
+	for _, want := range []string{"原始材料", "统计汇总：均值评分 70.0", "**【裁判 1 · deepseek/deepseek-chat】**：\n【评分: 60/100】", "**【裁判 2 · gemini/gemini-2.5-pro】**"} {
+		i := strings.Index(content, want)
+		if i < last {
+			t.Errorf("chief judge input is missing %q or has it out of order:\n%s", want, content)
+		}
+		last = i
+	}
+	if !strings.Contains(ChiefJudgeSystemPrompt, "【评分: XX/100】") {
+		t.Error("chief verdict One-Liner should carry the score like the Judge's")
+	}
+}
@@ -0,0 +1,79 @@
+// Package testutil holds helpers shared by tests in several packages. It
+// imports testing and must only be imported from _test.go files.
+//
+// The debate cassettes in internal/debate/testdata/cassettes hold the
+// provider HTTP/SSE traffic of one streamed debate per provider. The checked
+// in cassettes are synthetic: hand-written in each provider's wire format
+// with short fixed answers, so they stay small and deterministic. To replace
+// them with real recordings, export the provider keys and run:
+//
+//	DIALECTA_RECORD=1 go test ./internal/debate -run Cassette
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hrygo/dialecta/internal/config"
+	"github.com/hrygo/dialecta/internal/llm"
+)
+
+// CassetteMaterial is the material debated in the cassettes
+const CassetteMaterial = "我们应该在明年启动一个 AI 创业项目"
+
+// CassetteProviders are the providers with a cassette
+var CassetteProviders = []llm.Provider{llm.ProviderDeepSeek, llm.ProviderDashScope, llm.ProviderGemini}
+
+// Recording reports whether DIALECTA_RECORD asks for new recordings
+func Recording() bool {
+	return os.Getenv("DIALECTA_RECORD") != ""
+}
+
+// OpenCassette opens the provider's cassette in replay mode, or in record
+// mode when Recording. Recordings are saved on cleanup.
+func OpenCassette(t *testing.T, provider llm.Provider) *llm.Cassette {
+	t.Helper()
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("cannot locate the cassettes")
+	}
+	path := filepath.Join(filepath.Dir(file), "..", "debate", "testdata", "cassettes", string(provider)+".json")
+
+	mode := llm.CassetteReplay
+	if Recording() {
+		mode = llm.CassetteRecord
+	} else {
+		// Clients refuse to start without a key; replays never send it
+		for _, env := range []string{"DEEPSEEK_API_KEY", "DASHSCOPE_API_KEY", "GEMINI_API_KEY"} {
+			t.Setenv(env, "test-key")
+		}
+	}
+
+	cassette, err := llm.NewCassette(path, mode)
+	if err != nil {
+		t.Fatalf("NewCassette() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := cassette.Save(); err != nil {
+			t.Errorf("Save() error = %v", err)
+		}
+	})
+	return cassette
+}
+
+// CassetteConfig runs every role on provider through the cassette, one
+// request at a time
+func CassetteConfig(provider llm.Provider, cassette *llm.Cassette) *config.Config {
+	cfg := config.New()
+	for _, role := range []*config.RoleConfig{&cfg.ProRole, &cfg.ConRole, &cfg.JudgeRole} {
+		role.Provider = provider
+		role.Model = config.GetDefaultModel(provider)
+		role.HTTPClient = cassette.HTTPClient()
+		role.Retry = llm.RetryPolicy{}
+	}
+	// Pro and Con share one account, so they queue on its limiter
+	cfg.SetRateLimit(provider, llm.RateLimit{MaxInFlight: 1})
+	return cfg
+}